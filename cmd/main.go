@@ -5,16 +5,30 @@
 //	@host		localhost:9090
 //	@BasePath	/api/
 
-// @securityDefinitions.basic	BasicAuth
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				"Bearer" followed by an access token issued by /auth/login
+
+// @securityDefinitions.apikey	ApiKeyAuth
+// @in							header
+// @name						X-API-Key
+// @description				API key created at /keys, it is also accepted as a bearer credential
 package main
 
 import (
 	"context"
 	"github.com/rs/zerolog"
 	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/adapters/blobstore"
 	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
 	"github.com/vlasashk/task-manager/internal/models/logger"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
 	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"github.com/vlasashk/task-manager/internal/ports/purger"
+	// the service image has no time zone database, task time zones are resolved with the embedded one
+	_ "time/tzdata"
 )
 
 func main() {
@@ -25,13 +39,42 @@ func main() {
 		log.Fatal().Err(err).Msg("config parse fail")
 	}
 	log.Info().Msg("config parsing success")
+	workflow, err := tasktodo.NewWorkflow(cfg.Workflow)
+	if err != nil {
+		log.Fatal().Err(err).Msg("workflow config fail")
+	}
+	if cfg.Auth.Secret == "" && cfg.Auth.PrivateKey == "" {
+		log.Warn().Msg("neither JWT_SECRET nor JWT_ED25519_KEY is set, tokens are signed with a random secret and expire on restart")
+	}
+	tokens, err := account.NewIssuer(cfg.Auth)
+	if err != nil {
+		log.Fatal().Err(err).Msg("auth config fail")
+	}
+	if cfg.Pagination.CursorSecret == "" {
+		log.Warn().Msg("CURSOR_SECRET is not set, cursors are signed with a random key and expire on restart")
+	}
+	pages, err := tasktodo.NewPaginator(cfg.Pagination)
+	if err != nil {
+		log.Fatal().Err(err).Msg("pagination config fail")
+	}
+	attachments, err := tasktodo.NewAttachmentPolicy(cfg.Attachment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("attachment config fail")
+	}
+	blobs, err := blobstore.New(cfg.Blob)
+	if err != nil {
+		log.Fatal().Err(err).Msg("blob store config fail")
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	storage, err := pgrepo.NewTasksRepo(ctx, cfg.Postgres)
+	storage, err := pgrepo.NewTasksRepo(ctx, cfg.Postgres, pgrepo.WithInitialState(workflow.Initial()))
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
 	log.Info().Msg("db connection success")
-	service := httpchi.NewService(storage)
+	go purger.Run(ctx, storage, blobs, cfg.Trash, log)
+	service := httpchi.NewService(storage, httpchi.WithWorkflow(workflow), httpchi.WithAuth(storage, tokens),
+		httpchi.WithPagination(pages), httpchi.WithAttachments(blobs, attachments), httpchi.WithDueDatePolicy(tasktodo.NewDueDatePolicy(cfg.DueDate)),
+		httpchi.WithBulkLimit(cfg.Bulk.MaxSize))
 	httpchi.Run(service, log, cfg.App)
 }
@@ -1,12 +1,22 @@
 package config
 
 import (
+	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
+	"time"
 )
 
 type Config struct {
-	App      AppCfg
-	Postgres PostgresCfg
+	App        AppCfg
+	Postgres   PostgresCfg
+	Workflow   WorkflowCfg
+	Auth       AuthCfg
+	Pagination PaginationCfg
+	Trash      TrashCfg
+	Blob       BlobCfg
+	Attachment AttachmentCfg
+	DueDate    DueDateCfg
+	Bulk       BulkCfg
 }
 
 type AppCfg struct {
@@ -21,6 +31,91 @@ type PostgresCfg struct {
 	Host         string `env:"POSTGRES_HOST" env-default:"localhost"`
 	NameDB       string `env:"POSTGRES_DB" env-default:"postgres"`
 	InitFilePath string `env:"PG_INIT_SQL_PATH" env-default:"./internal/sql/task.sql"`
+	// SearchLanguage is the text search configuration used to index and query tasks, e.g. english or russian.
+	SearchLanguage string `env:"PG_SEARCH_LANGUAGE" env-default:"english"`
+}
+
+// WorkflowCfg overrides the default task workflow, empty values keep the defaults.
+// Transitions are listed as "from:to1|to2,from2:to3".
+type WorkflowCfg struct {
+	States      []string          `env:"WORKFLOW_STATES"`
+	Initial     string            `env:"WORKFLOW_INITIAL"`
+	Transitions map[string]string `env:"WORKFLOW_TRANSITIONS"`
+}
+
+// AuthCfg configures issuing of access tokens. Tokens are signed with EdDSA when
+// the base64 encoded Ed25519 seed is provided and with HS256 using the secret otherwise,
+// a random secret is generated when both are empty, so tokens do not survive a restart.
+type AuthCfg struct {
+	Secret     string        `env:"JWT_SECRET"`
+	PrivateKey string        `env:"JWT_ED25519_KEY"`
+	AccessTTL  time.Duration `env:"JWT_ACCESS_TTL" env-default:"15m"`
+	RefreshTTL time.Duration `env:"JWT_REFRESH_TTL" env-default:"720h"`
+}
+
+// PaginationCfg limits the size of a page of tasks. Cursors are signed with the secret,
+// a random one is generated when it is empty, so cursors do not survive a restart.
+type PaginationCfg struct {
+	CursorSecret string `env:"CURSOR_SECRET"`
+	DefaultLimit uint   `env:"PAGE_DEFAULT_LIMIT" env-default:"10"`
+	MaxLimit     uint   `env:"PAGE_MAX_LIMIT" env-default:"100"`
+}
+
+// TrashCfg sets how long deleted tasks stay restorable and how often the expired ones are purged.
+type TrashCfg struct {
+	Retention     time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// Validate checks that both durations are positive, the purger ticks every purge interval.
+func (cfg TrashCfg) Validate() error {
+	if cfg.Retention <= 0 {
+		return fmt.Errorf("TRASH_RETENTION must be positive, got %s", cfg.Retention)
+	}
+	if cfg.PurgeInterval <= 0 {
+		return fmt.Errorf("TRASH_PURGE_INTERVAL must be positive, got %s", cfg.PurgeInterval)
+	}
+	return nil
+}
+
+// BlobCfg selects where the content of attachments is kept: "local" stores it in the directory
+// and "s3" in the bucket of an S3 compatible storage such as MinIO.
+type BlobCfg struct {
+	Backend string `env:"BLOB_BACKEND" env-default:"local"`
+	Dir     string `env:"BLOB_DIR" env-default:"./blobs"`
+	// S3Endpoint is the base URL of the storage, e.g. http://minio:9000, buckets are addressed by path.
+	S3Endpoint  string `env:"S3_ENDPOINT"`
+	S3Region    string `env:"S3_REGION" env-default:"us-east-1"`
+	S3Bucket    string `env:"S3_BUCKET"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+}
+
+// AttachmentCfg limits uploaded attachments. The type is detected from the content
+// and has to be one of the listed media types, "image/*" allows all images.
+type AttachmentCfg struct {
+	MaxSize int64    `env:"ATTACHMENT_MAX_SIZE" env-default:"10485760"`
+	Types   []string `env:"ATTACHMENT_TYPES" env-default:"image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf,application/zip,application/x-gzip"`
+}
+
+// DueDateCfg limits due dates of new tasks and changed due dates to PastDays days before today at most,
+// a negative value allows any date. Admins, listed by user ID, may import tasks with any due date.
+type DueDateCfg struct {
+	PastDays int      `env:"DUE_DATE_PAST_DAYS" env-default:"0"`
+	Admins   []string `env:"DUE_DATE_ADMINS"`
+}
+
+// BulkCfg limits the number of operations in a single bulk request.
+type BulkCfg struct {
+	MaxSize int `env:"BULK_MAX_SIZE" env-default:"100"`
+}
+
+// Validate checks that the size is positive, otherwise every bulk request would be rejected.
+func (cfg BulkCfg) Validate() error {
+	if cfg.MaxSize <= 0 {
+		return fmt.Errorf("BULK_MAX_SIZE must be positive, got %d", cfg.MaxSize)
+	}
+	return nil
 }
 
 func ParseConfigValues() (Config, error) {
@@ -28,5 +123,11 @@ func ParseConfigValues() (Config, error) {
 	if err := cleanenv.ReadEnv(&newConfig); err != nil {
 		return Config{}, err
 	}
+	if err := newConfig.Trash.Validate(); err != nil {
+		return Config{}, err
+	}
+	if err := newConfig.Bulk.Validate(); err != nil {
+		return Config{}, err
+	}
 	return newConfig, nil
 }
@@ -0,0 +1,42 @@
+package config_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/config"
+	"testing"
+	"time"
+)
+
+func TestTrashCfgValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cfg   config.TrashCfg
+		valid bool
+	}{
+		{name: "defaults", cfg: config.TrashCfg{Retention: 720 * time.Hour, PurgeInterval: time.Hour}, valid: true},
+		{name: "zero interval", cfg: config.TrashCfg{Retention: time.Hour}},
+		{name: "negative interval", cfg: config.TrashCfg{Retention: time.Hour, PurgeInterval: -time.Minute}},
+		{name: "zero retention", cfg: config.TrashCfg{PurgeInterval: time.Hour}},
+	}
+	for _, tc := range testCases {
+		err := tc.cfg.Validate()
+		assert.Equal(t, tc.valid, err == nil, tc.name)
+	}
+}
+
+func TestBulkCfgValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cfg   config.BulkCfg
+		valid bool
+	}{
+		{name: "default", cfg: config.BulkCfg{MaxSize: 100}, valid: true},
+		{name: "single", cfg: config.BulkCfg{MaxSize: 1}, valid: true},
+		{name: "zero", cfg: config.BulkCfg{}},
+		{name: "negative", cfg: config.BulkCfg{MaxSize: -1}},
+	}
+	for _, tc := range testCases {
+		err := tc.cfg.Validate()
+		assert.Equal(t, tc.valid, err == nil, tc.name)
+	}
+}
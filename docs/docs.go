@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/task": {
+        "/auth/login": {
             "post": {
-                "description": "Creates a task with specified fields: title, description, due date, and completion status",
+                "description": "Checks the credentials and issues a short-lived access token and a long-lived refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,35 +25,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Auth"
                 ],
-                "summary": "creates a new task",
+                "summary": "Logs a user in",
                 "parameters": [
                     {
-                        "description": "Data of the new task",
-                        "name": "taskRequest",
+                        "description": "Email and password of the user",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/tasktodo.Request"
+                            "$ref": "#/definitions/account.Credentials"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Task successfully created",
+                    "200": {
+                        "description": "Tokens issued",
                         "schema": {
-                            "$ref": "#/definitions/tasktodo.Task"
+                            "$ref": "#/definitions/account.Tokens"
                         }
                     },
                     "400": {
-                        "description": "Incorrect JSON or invalid date format",
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
                         "schema": {
                             "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     },
                     "422": {
-                        "description": "Invalid JSON",
+                        "description": "Invalid email or password length",
                         "schema": {
                             "$ref": "#/definitions/httpchi.ErrResp"
                         }
@@ -61,9 +67,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/task/{id}": {
-            "get": {
-                "description": "Retrieves a task based on the provided identifier",
+        "/auth/refresh": {
+            "post": {
+                "description": "Issues a new pair of tokens for a valid refresh token of an existing user",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,35 +77,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Auth"
                 ],
-                "summary": "Gets a task by ID",
+                "summary": "Refreshes tokens",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.RefreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task successfully retrieved",
+                        "description": "Tokens issued",
                         "schema": {
-                            "$ref": "#/definitions/tasktodo.Task"
+                            "$ref": "#/definitions/account.Tokens"
                         }
                     },
-                    "404": {
-                        "description": "Task not found",
+                    "400": {
+                        "description": "Incorrect JSON",
                         "schema": {
-                            "$ref": "#/definitions/httpchi.MsgResp"
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates a task by the specified identifier",
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Creates a user account, the password is stored as a bcrypt hash",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,56 +123,94 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Auth"
                 ],
-                "summary": "Updates a task by ID",
+                "summary": "Registers a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Data for updating the task",
-                        "name": "taskUpd",
+                        "description": "Email and password of the new user",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/tasktodo.Request"
+                            "$ref": "#/definitions/account.Credentials"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Task successfully updated",
+                    "201": {
+                        "description": "User successfully registered",
                         "schema": {
-                            "$ref": "#/definitions/tasktodo.Task"
+                            "$ref": "#/definitions/account.User"
                         }
                     },
                     "400": {
-                        "description": "Incorrect JSON or invalid date format",
+                        "description": "Incorrect JSON",
                         "schema": {
                             "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     },
-                    "404": {
-                        "description": "Task not found",
+                    "409": {
+                        "description": "Email already registered",
                         "schema": {
-                            "$ref": "#/definitions/httpchi.MsgResp"
+                            "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     },
                     "422": {
-                        "description": "Invalid JSON",
+                        "description": "Invalid email or password length",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all keys of the user including revoked ones, secrets are never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API keys"
+                ],
+                "summary": "Returns API keys",
+                "responses": {
+                    "200": {
+                        "description": "List of keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/account.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Keys are managed by users only",
                         "schema": {
                             "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Deletes a task by the specified identifier",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a key for machine clients with the given scopes: tasks:read, tasks:write, tasks:delete. The secret is returned only once",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,13 +218,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "API keys"
                 ],
-                "summary": "Deletes a task by ID",
+                "summary": "Creates an API key",
+                "parameters": [
+                    {
+                        "description": "Name and scopes of the new key",
+                        "name": "keyRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/account.APIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Key successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/account.CreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Keys are managed by users only",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or unknown scope",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the key, requests made with it are rejected from now on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API keys"
+                ],
+                "summary": "Revokes an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -178,13 +292,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task successfully deleted",
+                        "description": "Key revoked",
                         "schema": {
                             "$ref": "#/definitions/httpchi.MsgResp"
                         }
                     },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Keys are managed by users only",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Key not found or already revoked",
                         "schema": {
                             "$ref": "#/definitions/httpchi.MsgResp"
                         }
@@ -192,46 +318,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks": {
+        "/projects": {
             "get": {
-                "description": "Retrieves a list of tasks based on status, date, and page for pagination",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Retrieves projects with task counts ordered by name, archived projects are included on demand",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Projects"
                 ],
-                "summary": "Returns a list of tasks with filtering and pagination",
+                "summary": "Returns projects",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task completion status (true/false)",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Task date (format: YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Page number for pagination",
-                        "name": "page",
+                        "description": "Whether to include archived projects (true/false)",
+                        "name": "archived",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of tasks",
+                        "description": "List of projects",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tasktodo.Task"
+                                "$ref": "#/definitions/tasktodo.Project"
                             }
                         }
                     },
@@ -241,59 +360,5761 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     },
-                    "404": {
-                        "description": "Tasks not found",
+                    "401": {
+                        "description": "Missing credentials",
                         "schema": {
-                            "$ref": "#/definitions/httpchi.MsgResp"
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "httpchi.ErrResp": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "param": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a project to group tasks of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Creates a new project",
+                "parameters": [
+                    {
+                        "description": "Data of the new project",
+                        "name": "projectRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Project successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
                 }
             }
         },
-        "httpchi.MsgResp": {
-            "type": "object",
-            "properties": {
+        "/projects/{pid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a project with counts of its tasks by state",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Returns a project by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project details",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Project"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces name and description of the project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Updates a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New data of the project",
+                        "name": "projectRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the project, its tasks are kept outside any project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Deletes a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{pid}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archives the project as a whole: its tasks are left out of task listings and no tasks can be added to it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Archives a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project archived",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Project"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{pid}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves tasks of the project with the same filtering, pagination and v2 envelope as GET /tasks, archived projects included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Returns tasks of a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task completion status (true/false)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task date (format: YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names, repeated or comma separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether a task needs any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task has open blockers (true/false)",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task is past its due time and still open (true/false)",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creation time lower bound, RFC 3339 or YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title prefix",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Sort fields, repeated or comma separated, ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task query, e.g. status:open tag:backend due\u003c2025-01-01 release",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a task the same way as POST /task, the project is taken from the path",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Creates a new task in a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data of the new task",
+                        "name": "taskRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Task successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON or invalid date format",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Project is archived",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, unknown state, parent task, project or recurrence rule",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{pid}/tasks/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves up to 100 tasks into the project in a single transaction: either all of them are moved or none",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Moves tasks into a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tasks to move",
+                        "name": "moveRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.MoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moved tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Project is archived",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or unknown task",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{pid}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Brings the project and its tasks back to task listings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Restores an archived project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project restored",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Project"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves all tags ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Returns all tags",
+                "responses": {
+                    "200": {
+                        "description": "List of tags",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a tag with a unique name, tags are also created on the fly when assigned to a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Creates a new tag",
+                "parameters": [
+                    {
+                        "description": "Data of the new tag",
+                        "name": "tagRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tag successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a tag based on the provided identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Gets a tag by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag successfully retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Tag"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renames a tag, tasks having the tag get the new name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Renames a tag by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New tag data",
+                        "name": "tagRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a tag and removes it from all tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Deletes a tag by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag successfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a task with specified fields: title, description, due date, and workflow state (or legacy completion status)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "creates a new task",
+                "parameters": [
+                    {
+                        "description": "Data of the new task",
+                        "name": "taskRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Request"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import the task with any due date, admins only",
+                        "name": "import",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Task successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON or invalid date format",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted or import by a user who is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Project is archived",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, due date in the past, unknown state, parent task, project or recurrence rule",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a task based on the provided identifier, a task with subtasks also gets the completion roll-up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Gets a task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Known task ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Task has not changed (never used for tasks with subtasks, comments or checklist items)"
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates a task by the specified identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Updates a task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected task ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ETag that must not match",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Data for updating the task",
+                        "name": "taskUpd",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON, invalid date format or malformed precondition header",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "State transition is not allowed, task has open blockers, hierarchy cycle, archived project or concurrent update",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, due date changed to the past, unknown state, parent task, project or recurrence rule",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a task by the specified identifier together with all its subtasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Deletes a task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected task ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ETag that must not match",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed precondition header",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Applies a JSON Merge Patch (RFC 7396): only supplied fields are validated and updated, the stored task is returned.\nWith the series scope title, description and recurrence are applied to the series and its open occurrences.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Partially updates a task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected task ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ETag that must not match",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "occurrence",
+                            "series"
+                        ],
+                        "type": "string",
+                        "default": "occurrence",
+                        "description": "Patch only this occurrence or the whole series of a recurring task",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "taskPatch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Patch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON, invalid date format, unknown scope or malformed precondition header",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition is not allowed, task has open blockers, hierarchy cycle, archived project or concurrent update",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid field value, due date changed to the past, unknown project, field not shared by the series or task is not recurring",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the metadata of the files attached to a task, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Returns attachments of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachments of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Attachment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Uploads the file sent as the \"file\" field of a multipart form. The type is detected from the content,\nit has to be allowed by the server as well as the size. Files with the same content are stored once",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Attaches a file to a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Attachment successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed form or missing file",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "415": {
+                        "description": "File type is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/attachments/{aid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the content of the attached file with its detected type. The content never changes,\nso the ETag is its SHA-256 and If-None-Match is answered with 304 Not Modified",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Downloads an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "aid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached content",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Content of the attachment",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "SHA-256 of the content"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the attachment, its content is removed later by the purger unless other attachments share it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Deletes an attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "aid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment successfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/checklist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves checklist items of a task in their order together with the progress like \"3/5\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Checklist"
+                ],
+                "summary": "Returns the checklist of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checklist of the task",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Appends an unchecked item to the end of the checklist of a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Checklist"
+                ],
+                "summary": "Adds a checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item text",
+                        "name": "itemRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ChecklistItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Checklist with the new item",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/checklist/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Puts checklist items of a task in the order of the given IDs, every item has to be listed exactly once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Checklist"
+                ],
+                "summary": "Reorders checklist items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ChecklistOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reordered checklist",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or the IDs do not match the items",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/checklist/{iid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes an item from the checklist of a task, the order of the other items is kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Checklist"
+                ],
+                "summary": "Removes a checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checklist without the item",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets whether a checklist item is done. With complete set the task is moved to the done state as well\nonce every item is checked, unless the workflow does not allow it or the task has open blockers.\nThe completed task is returned along with the checklist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Checklist"
+                ],
+                "summary": "Checks or unchecks a checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item state",
+                        "name": "toggle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ChecklistToggle"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checklist with the item changed",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ChecklistResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/children": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves tasks whose parent is the specified task",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subtasks"
+                ],
+                "summary": "Returns direct subtasks of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of subtasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves comments of a task oldest first, a page at a time. The next page is asked for with the cursor of the previous one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Returns comments of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of comments",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.CommentPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a comment written in Markdown to the discussion of a task, the rendered HTML is returned along with the source",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Adds a comment to a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment body",
+                        "name": "commentRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Comment successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/comments/{cid}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the body of a comment written by the user, the comment is marked as edited",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Edits a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New comment body",
+                        "name": "commentRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comment successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a comment written by the user, it is no longer listed nor counted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Deletes a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comment successfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the tasks the specified task depends on, including finished ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dependencies"
+                ],
+                "summary": "Returns blockers of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blockers of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The task cannot be done until the blocker is done or cancelled, dependency cycles are refused",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dependencies"
+                ],
+                "summary": "Adds a blocker to a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Blocking task",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Dependency"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Blockers of the task",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Dependency cycle",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or unknown blocker",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/dependencies/{blocker}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the dependency between the task and the blocker",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dependencies"
+                ],
+                "summary": "Removes a blocker from a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Blocking task ID",
+                        "name": "blocker",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dependency successfully removed",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the recorded changes of a task, the latest first: who made them, within which request and the changed fields before and after.\nThe history is kept for deleted and purged tasks too",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "History"
+                ],
+                "summary": "Returns the history of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/occurrences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists due dates of the occurrences following the task according to the series recurrence rule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recurrence"
+                ],
+                "summary": "Previews upcoming occurrences of a recurring task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of occurrences (1-100)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upcoming occurrences",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Occurrences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid count",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Task is not recurring",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restores a task from the trash together with the subtasks deleted with it.\nA subtask can not be restored while its parent is deleted, nor a task whose due date has passed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restores a deleted task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully restored",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Parent task is deleted or the due date has passed",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the fields of the task to the ones it had at the version according to its history, the revert is a new version itself.\nThe recurrence is kept as it is, the state change has to be allowed by the workflow",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "History"
+                ],
+                "summary": "Reverts a task to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected task ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task ETag that must not match",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Version to revert to",
+                        "name": "revert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.RevertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully reverted",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON or malformed precondition header",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition is not allowed, hierarchy cycle, archived project or concurrent update",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, version not in the history, due date changed to the past, missing parent task or project",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/subtree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves all descendants of the specified task ordered by depth, the tree can be rebuilt with parent_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subtasks"
+                ],
+                "summary": "Returns the whole subtree of a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of descendants",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/transition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the workflow state of a task if the transition is allowed by the configured workflow",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workflow"
+                ],
+                "summary": "Moves a task to another state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected task ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Target state",
+                        "name": "transition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Transition"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task moved to the new state",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Task"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON or malformed precondition header",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition is not allowed or task has open blockers",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown state",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of tasks based on status, date, tags, blockers, project, priority, due date and creation ranges, title prefix, and page for pagination.\nTasks are ordered by priority and then by due date unless sort lists due_date, title, state (workflow order), created_at or priority. Tasks of archived projects are listed only when the project is asked for.\nWith cursor or limit the tasks are wrapped in a TaskPage: an empty cursor starts the listing and next_cursor continues it\nUnder /v2 the tasks are always wrapped in a TaskList with the total count, an empty page is not an error there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Returns a list of tasks with filtering and pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task completion status (true/false)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task date (format: YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names, repeated or comma separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether a task needs any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task has open blockers (true/false)",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task is past its due time and still open (true/false)",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Priorities, repeated or comma separated",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creation time lower bound, RFC 3339 or YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title prefix",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Sort fields, repeated or comma separated, ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task query, e.g. status:open tag:backend due\u003c2025-01-01 release",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor, can not be combined with page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks, or TaskPage when paginated by cursor",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages (v2)"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of tasks matching the filters (v2)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates, updates (as PUT does), deletes and completes tasks in a single transaction, every operation is checked as by its own endpoint.\nAn atomic batch is applied as a whole: the first failing operation is reported with its status, the error param points at the operation, and nothing is changed.\nOtherwise every operation is applied or fails on its own and the response lists the results with the status each operation would get from its own endpoint.\nA task can be the target of one operation per batch only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Applies a batch of task operations",
+                "parameters": [
+                    {
+                        "description": "Operations to apply",
+                        "name": "bulkRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.BulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results of the operations",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.BulkResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON or invalid date format of an atomic batch",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write, or tasks:delete for delete operations, not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task of an atomic batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "409": {
+                        "description": "Operation of an atomic batch conflicts with the task",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "412": {
+                        "description": "Task version of an atomic batch does not match",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, too many operations, task changed more than once or invalid operation of an atomic batch",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/matrix": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Buckets open tasks by importance and urgency. Tasks of priority P0 and P1 are important,\ntasks due within urgent_days days or overdue are urgent. Every quadrant holds up to limit tasks\nordered by priority and due date along with the total number of its tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Returns the Eisenhower matrix of open tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 2,
+                        "description": "Days ahead a task is due to be urgent",
+                        "name": "urgent_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of tasks per quadrant, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks by quadrant",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.Matrix"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Full-text search in the web search syntax: quoted phrases, \"or\" and \"-\" for exclusion are supported.\nResults are ranked, matches in the title weigh more than in the description, matched words are highlighted with \u003cmark\u003e tags and the rest of the fragments is HTML-escaped.\nThe same filters and pagination as for the list of tasks apply",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Searches tasks by title and description",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task completion status (true/false)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task date (format: YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names, repeated or comma separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether a task needs any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task has open blockers (true/false)",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task is past its due time and still open (true/false)",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creation time lower bound, RFC 3339 or YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title prefix",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Sort fields, repeated or comma separated, ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching tasks, best first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query or invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves tasks in the trash, the latest deleted first. Tasks are purged for good once the retention period has passed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Returns deleted tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of deleted tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.DeletedTask"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a task in the trash permanently together with its subtasks, it can not be restored afterwards",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Purges a deleted task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully purged",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/projects/{pid}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves tasks of the project with the same filtering, pagination and v2 envelope as GET /tasks, archived projects included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects"
+                ],
+                "summary": "Returns tasks of a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task completion status (true/false)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task date (format: YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names, repeated or comma separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether a task needs any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task has open blockers (true/false)",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task is past its due time and still open (true/false)",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creation time lower bound, RFC 3339 or YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title prefix",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Sort fields, repeated or comma separated, ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task query, e.g. status:open tag:backend due\u003c2025-01-01 release",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of tasks based on status, date, tags, blockers, project, priority, due date and creation ranges, title prefix, and page for pagination.\nTasks are ordered by priority and then by due date unless sort lists due_date, title, state (workflow order), created_at or priority. Tasks of archived projects are listed only when the project is asked for.\nWith cursor or limit the tasks are wrapped in a TaskPage: an empty cursor starts the listing and next_cursor continues it\nUnder /v2 the tasks are always wrapped in a TaskList with the total count, an empty page is not an error there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Returns a list of tasks with filtering and pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task completion status (true/false)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task date (format: YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names, repeated or comma separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether a task needs any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task has open blockers (true/false)",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest due date, inclusive (format: YYYY-MM-DD)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Whether a task is past its due time and still open (true/false)",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Priorities, repeated or comma separated",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creation time lower bound, RFC 3339 or YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title prefix",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Sort fields, repeated or comma separated, ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Task query, e.g. status:open tag:backend due\u003c2025-01-01 release",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, limited by the server",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor, can not be combined with page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks, or TaskPage when paginated by cursor",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, prev, next and last pages (v2)"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of tasks matching the filters (v2)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/views/{id}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists tasks with the parameters stored in the view, the response is the same as of GET /tasks with them.\nOnly page and cursor are taken from the request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Returns tasks of a view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor, can not be combined with page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks, or TaskPage when the view has a page size",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "View or tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/views": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves saved views ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Returns views",
+                "responses": {
+                    "200": {
+                        "description": "List of views",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.View"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saves a named task listing: filters take the names and values of the GET /tasks parameters,\nsort and page_size are its sort and limit. The view is checked the way a listing is, broken views are not saved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Saves a new view",
+                "parameters": [
+                    {
+                        "description": "Data of the new view",
+                        "name": "viewRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ViewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "View successfully saved",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "409": {
+                        "description": "View already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, unknown filter or invalid filter value",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/views/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a saved view based on the provided identifier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Gets a view by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View successfully retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.View"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "View not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name, filters, sort and page size of a view, the view is checked as on creation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Replaces a view by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New data of the view",
+                        "name": "viewRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.ViewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect JSON",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:write not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "View not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "409": {
+                        "description": "View already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON, unknown filter or invalid filter value",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a saved view, tasks are not affected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Deletes a view by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View successfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:delete not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "View not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/views/{id}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists tasks with the parameters stored in the view, the response is the same as of GET /tasks with them.\nOnly page and cursor are taken from the request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Views"
+                ],
+                "summary": "Returns tasks of a view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor, can not be combined with page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks, or TaskPage when the view has a page size",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tasktodo.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "404": {
+                        "description": "View or tasks not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.MsgResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflow": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists enabled states and allowed transitions between them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workflow"
+                ],
+                "summary": "Returns the task workflow",
+                "responses": {
+                    "200": {
+                        "description": "Workflow definition",
+                        "schema": {
+                            "$ref": "#/definitions/tasktodo.WorkflowDefinition"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing credentials",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    },
+                    "403": {
+                        "description": "Scope tasks:read not granted",
+                        "schema": {
+                            "$ref": "#/definitions/httpchi.ErrResp"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "account.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/account.Scope"
+                    }
+                }
+            }
+        },
+        "account.APIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/account.Scope"
+                    }
+                }
+            }
+        },
+        "account.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/account.Scope"
+                    }
+                }
+            }
+        },
+        "account.Credentials": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                }
+            }
+        },
+        "account.Scope": {
+            "type": "string",
+            "enum": [
+                "tasks:read",
+                "tasks:write",
+                "tasks:delete",
+                "keys:manage"
+            ],
+            "x-enum-varnames": [
+                "ScopeTasksRead",
+                "ScopeTasksWrite",
+                "ScopeTasksDelete",
+                "ScopeKeysManage"
+            ]
+        },
+        "account.Tokens": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "account.User": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpchi.BulkResp": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpchi.BulkResult"
+                    }
+                }
+            }
+        },
+        "httpchi.BulkResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/tasktodo.BulkAction"
+                },
+                "error": {
+                    "$ref": "#/definitions/httpchi.ErrResp"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "task": {
+                    "$ref": "#/definitions/tasktodo.Task"
+                }
+            }
+        },
+        "httpchi.ChecklistResp": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.ChecklistItem"
+                    }
+                },
+                "progress": {
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/tasktodo.Task"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpchi.CommentPage": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.Comment"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpchi.ErrResp": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpchi.MsgResp": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string"
                 }
             }
         },
+        "httpchi.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.Action": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "revert"
+            ],
+            "x-enum-varnames": [
+                "ActionCreate",
+                "ActionUpdate",
+                "ActionDelete",
+                "ActionRestore",
+                "ActionRevert"
+            ]
+        },
+        "tasktodo.Attachment": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "uploader_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.BulkAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "complete"
+            ],
+            "x-enum-varnames": [
+                "BulkCreate",
+                "BulkUpdate",
+                "BulkDelete",
+                "BulkComplete"
+            ]
+        },
+        "tasktodo.BulkOperation": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete",
+                        "complete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.BulkAction"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/tasktodo.Request"
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "tasktodo.BulkRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "operations": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.BulkOperation"
+                    }
+                }
+            }
+        },
+        "tasktodo.Checklist": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.ChecklistItem"
+                    }
+                },
+                "progress": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.ChecklistItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "done": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.ChecklistItemRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "tasktodo.ChecklistOrder": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "tasktodo.ChecklistToggle": {
+            "type": "object",
+            "required": [
+                "done"
+            ],
+            "properties": {
+                "complete": {
+                    "type": "boolean"
+                },
+                "done": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "tasktodo.Comment": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "body": {
+                    "description": "Body is the Markdown source of the comment, HTML is its rendering.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "html": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.CommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "tasktodo.DeletedTask": {
+            "type": "object",
+            "required": [
+                "description",
+                "due_date",
+                "id",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "blocked": {
+                    "description": "Blocked is set while any of the tasks blocking this one is still open.",
+                    "type": "boolean"
+                },
+                "checklist": {
+                    "description": "Checklist holds the checklist items of the task, it is only filled for a single task having any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    ]
+                },
+                "comment_count": {
+                    "description": "CommentCount is the number of comments on the task, it is only filled for a single task.",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt, UpdatedAt and CompletedAt are kept by the storage, UpdatedAt changes with the version\nand CompletedAt is set while the task is done.",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "DueAt is the moment the task is due, it is only set for a task due at a time of day.",
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "DueDate is a date, or an RFC 3339 time which sets both the date and DueTime in TimeZone.",
+                    "type": "string"
+                },
+                "due_time": {
+                    "description": "DueTime is the time of day the task is due on its due date like 18:30, left out of an update\nthe task is due on the date only.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "ParentID left out of an update keeps the current parent, an empty string makes the task top-level.",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority left out of a new task is DefaultPriority, left out of an update it is kept.",
+                    "enum": [
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3",
+                        "P4"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Priority"
+                        }
+                    ]
+                },
+                "progress": {
+                    "description": "Progress is the completion roll-up of the subtasks, it is only filled for a single task.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Progress"
+                        }
+                    ]
+                },
+                "project_id": {
+                    "description": "ProjectID left out of an update keeps the current project, an empty string takes the task out of its project.",
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RFC 5545 RRULE of the task series, left out of an update it is kept,\nan empty string stops the recurrence of the task.",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "SeriesID links occurrences of a recurring task.",
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "Tags left out of an update keep the current ones, an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone of the due time like Europe/Moscow, UTC if there is none.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.Dependency": {
+            "type": "object",
+            "required": [
+                "blocker_id"
+            ],
+            "properties": {
+                "blocker_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.Event": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/tasktodo.Action"
+                },
+                "actor": {
+                    "description": "Actor is the user who made the change, APIKeyID is set when it was made with an API key.",
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "api_key_id": {
+                    "type": "string"
+                },
+                "before": {
+                    "description": "Before and After hold the changed fields only. A created task has all of its fields in After,\ndeletion and restoration change no fields.",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the version of the task the change resulted in.",
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.Highlights": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.Matrix": {
+            "type": "object",
+            "properties": {
+                "delegate": {
+                    "description": "Delegate holds urgent tasks which are not important.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Quadrant"
+                        }
+                    ]
+                },
+                "do": {
+                    "description": "Do holds urgent and important tasks.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Quadrant"
+                        }
+                    ]
+                },
+                "eliminate": {
+                    "description": "Eliminate holds the rest.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Quadrant"
+                        }
+                    ]
+                },
+                "schedule": {
+                    "description": "Schedule holds important tasks which are not urgent.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Quadrant"
+                        }
+                    ]
+                }
+            }
+        },
+        "tasktodo.MoveRequest": {
+            "type": "object",
+            "required": [
+                "task_ids"
+            ],
+            "properties": {
+                "task_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "tasktodo.Occurrences": {
+            "type": "object",
+            "properties": {
+                "occurrences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "series_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.Patch": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "due_time": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "enum": [
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3",
+                        "P4"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Priority"
+                        }
+                    ]
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "tasktodo.Priority": {
+            "type": "string",
+            "enum": [
+                "P0",
+                "P1",
+                "P2",
+                "P3",
+                "P4",
+                "P2",
+                "P1"
+            ],
+            "x-enum-varnames": [
+                "PriorityP0",
+                "PriorityP1",
+                "PriorityP2",
+                "PriorityP3",
+                "PriorityP4",
+                "DefaultPriority",
+                "ImportantPriority"
+            ]
+        },
+        "tasktodo.Progress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.Project": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "archived_at": {
+                    "description": "ArchivedAt is set for archived projects, their tasks are left out of listings unless the project is asked for.",
+                    "type": "string"
+                },
+                "counts": {
+                    "$ref": "#/definitions/tasktodo.ProjectCounts"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 4096
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "tasktodo.ProjectCounts": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "type": "integer"
+                },
+                "done": {
+                    "type": "integer"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.ProjectRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 4096
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "tasktodo.Quadrant": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.Task"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "tasktodo.Request": {
             "type": "object",
             "required": [
                 "description",
                 "due_date",
-                "status",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "DueDate is a date, or an RFC 3339 time which sets both the date and DueTime in TimeZone.",
+                    "type": "string"
+                },
+                "due_time": {
+                    "description": "DueTime is the time of day the task is due on its due date like 18:30, left out of an update\nthe task is due on the date only.",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "ParentID left out of an update keeps the current parent, an empty string makes the task top-level.",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority left out of a new task is DefaultPriority, left out of an update it is kept.",
+                    "enum": [
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3",
+                        "P4"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Priority"
+                        }
+                    ]
+                },
+                "project_id": {
+                    "description": "ProjectID left out of an update keeps the current project, an empty string takes the task out of its project.",
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RFC 5545 RRULE of the task series, left out of an update it is kept,\nan empty string stops the recurrence of the task.",
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "Tags left out of an update keep the current ones, an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone of the due time like Europe/Moscow, UTC if there is none.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasktodo.RevertRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "tasktodo.SearchResult": {
+            "type": "object",
+            "required": [
+                "description",
+                "due_date",
+                "id",
+                "tags",
                 "title"
             ],
             "properties": {
+                "blocked": {
+                    "description": "Blocked is set while any of the tasks blocking this one is still open.",
+                    "type": "boolean"
+                },
+                "checklist": {
+                    "description": "Checklist holds the checklist items of the task, it is only filled for a single task having any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    ]
+                },
+                "comment_count": {
+                    "description": "CommentCount is the number of comments on the task, it is only filled for a single task.",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt, UpdatedAt and CompletedAt are kept by the storage, UpdatedAt changes with the version\nand CompletedAt is set while the task is done.",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "due_at": {
+                    "description": "DueAt is the moment the task is due, it is only set for a task due at a time of day.",
+                    "type": "string"
+                },
                 "due_date": {
+                    "description": "DueDate is a date, or an RFC 3339 time which sets both the date and DueTime in TimeZone.",
+                    "type": "string"
+                },
+                "due_time": {
+                    "description": "DueTime is the time of day the task is due on its due date like 18:30, left out of an update\nthe task is due on the date only.",
+                    "type": "string"
+                },
+                "highlights": {
+                    "description": "Highlights hold fragments of the title and description with matched words wrapped in \u003cmark\u003e tags.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Highlights"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "ParentID left out of an update keeps the current parent, an empty string makes the task top-level.",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority left out of a new task is DefaultPriority, left out of an update it is kept.",
+                    "enum": [
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3",
+                        "P4"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Priority"
+                        }
+                    ]
+                },
+                "progress": {
+                    "description": "Progress is the completion roll-up of the subtasks, it is only filled for a single task.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Progress"
+                        }
+                    ]
+                },
+                "project_id": {
+                    "description": "ProjectID left out of an update keeps the current project, an empty string takes the task out of its project.",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RFC 5545 RRULE of the task series, left out of an update it is kept,\nan empty string stops the recurrence of the task.",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "SeriesID links occurrences of a recurring task.",
                     "type": "string"
                 },
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
                 "status": {
                     "type": "boolean"
                 },
+                "tags": {
+                    "description": "Tags left out of an update keep the current ones, an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone of the due time like Europe/Moscow, UTC if there is none.",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.State": {
+            "type": "string",
+            "enum": [
+                "todo",
+                "in_progress",
+                "blocked",
+                "in_review",
+                "done",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StateTodo",
+                "StateInProgress",
+                "StateBlocked",
+                "StateInReview",
+                "StateDone",
+                "StateCancelled"
+            ]
+        },
+        "tasktodo.Tag": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "tasktodo.TagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
@@ -303,31 +6124,227 @@ const docTemplate = `{
                 "description",
                 "due_date",
                 "id",
-                "status",
+                "tags",
                 "title"
             ],
             "properties": {
+                "blocked": {
+                    "description": "Blocked is set while any of the tasks blocking this one is still open.",
+                    "type": "boolean"
+                },
+                "checklist": {
+                    "description": "Checklist holds the checklist items of the task, it is only filled for a single task having any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Checklist"
+                        }
+                    ]
+                },
+                "comment_count": {
+                    "description": "CommentCount is the number of comments on the task, it is only filled for a single task.",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "CreatedAt, UpdatedAt and CompletedAt are kept by the storage, UpdatedAt changes with the version\nand CompletedAt is set while the task is done.",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "due_at": {
+                    "description": "DueAt is the moment the task is due, it is only set for a task due at a time of day.",
+                    "type": "string"
+                },
                 "due_date": {
+                    "description": "DueDate is a date, or an RFC 3339 time which sets both the date and DueTime in TimeZone.",
+                    "type": "string"
+                },
+                "due_time": {
+                    "description": "DueTime is the time of day the task is due on its due date like 18:30, left out of an update\nthe task is due on the date only.",
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "parent_id": {
+                    "description": "ParentID left out of an update keeps the current parent, an empty string makes the task top-level.",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority left out of a new task is DefaultPriority, left out of an update it is kept.",
+                    "enum": [
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3",
+                        "P4"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Priority"
+                        }
+                    ]
+                },
+                "progress": {
+                    "description": "Progress is the completion roll-up of the subtasks, it is only filled for a single task.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tasktodo.Progress"
+                        }
+                    ]
+                },
+                "project_id": {
+                    "description": "ProjectID left out of an update keeps the current project, an empty string takes the task out of its project.",
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RFC 5545 RRULE of the task series, left out of an update it is kept,\nan empty string stops the recurrence of the task.",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "SeriesID links occurrences of a recurring task.",
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
                 "status": {
                     "type": "boolean"
                 },
+                "tags": {
+                    "description": "Tags left out of an update keep the current ones, an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone of the due time like Europe/Moscow, UTC if there is none.",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tasktodo.Transition": {
+            "type": "object",
+            "required": [
+                "state"
+            ],
+            "properties": {
+                "state": {
+                    "$ref": "#/definitions/tasktodo.State"
+                }
+            }
+        },
+        "tasktodo.View": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "filters": {
+                    "description": "Filters hold listing parameters by name, e.g. {\"status\": \"false\", \"tag\": \"backend,api\", \"q\": \"overdue:true\"}.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "page_size": {
+                    "description": "PageSize is the limit parameter of the listing, 0 keeps the listing unpaginated by cursor.\nSorted views are paged by number, as sorted listings are.",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "Sort is the sort parameter of the listing, tasks are ordered by priority and due date if it is empty.",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "tasktodo.ViewRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "filters": {
+                    "description": "Filters hold listing parameters by name, e.g. {\"status\": \"false\", \"tag\": \"backend,api\", \"q\": \"overdue:true\"}.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "page_size": {
+                    "description": "PageSize is the limit parameter of the listing, 0 keeps the listing unpaginated by cursor.\nSorted views are paged by number, as sorted listings are.",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "Sort is the sort parameter of the listing, tasks are ordered by priority and due date if it is empty.",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "tasktodo.WorkflowDefinition": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
+                "initial": {
+                    "$ref": "#/definitions/tasktodo.State"
+                },
+                "states": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tasktodo.State"
+                    }
+                },
+                "transitions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/tasktodo.State"
+                        }
+                    }
                 }
             }
         }
     },
     "securityDefinitions": {
-        "BasicAuth": {
-            "type": "basic"
+        "ApiKeyAuth": {
+            "description": "API key created at /keys, it is also accepted as a bearer credential",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "\"Bearer\" followed by an access token issued by /auth/login",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,29 @@
+// Package blobstore keeps contents of attachments in a local directory or in an S3 compatible storage.
+package blobstore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+// New returns the store selected by the backend of the config.
+func New(cfg config.BlobCfg) (tasktodo.BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocal(cfg.Dir)
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unknown blob backend %q", cfg.Backend)
+	}
+}
+
+// checkKey accepts hex encoded SHA-256 sums only, so keys can not escape the directory or the bucket.
+func checkKey(key string) error {
+	if decoded, err := hex.DecodeString(key); err != nil || len(decoded) != 32 {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
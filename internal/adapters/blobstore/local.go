@@ -0,0 +1,104 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local keeps blobs as files of the directory, spread over subdirectories by the first two characters of the key.
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (Local, error) {
+	if dir == "" {
+		return Local{}, errors.New("blob directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return Local{}, fmt.Errorf("creating blob directory fail: %v", err)
+	}
+	return Local{dir: dir}, nil
+}
+
+// Put writes the content to a temporary file first, so a blob is never seen half written.
+func (l Local) Put(_ context.Context, key string, content io.Reader, size int64, _ string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating blob directory fail: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating blob fail: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing blob fail: %v", err)
+	}
+	if written != size {
+		return fmt.Errorf("blob size %d does not match %d", written, size)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("storing blob fail: %v", err)
+	}
+	return nil
+}
+
+func (l Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New(tasktodo.BlobNotFoundErr)
+		}
+		return nil, fmt.Errorf("opening blob fail: %v", err)
+	}
+	return file, nil
+}
+
+func (l Local) Exists(_ context.Context, key string) (bool, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err = os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("checking blob fail: %v", err)
+	}
+	return true, nil
+}
+
+func (l Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deleting blob fail: %v", err)
+	}
+	return nil
+}
+
+func (l Local) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.dir, key[:2], key), nil
+}
@@ -0,0 +1,119 @@
+package blobstore_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/adapters/blobstore"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	store, err := blobstore.New(config.BlobCfg{Backend: "local", Dir: t.TempDir()})
+	require.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestS3(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store, err := blobstore.New(config.BlobCfg{Backend: "s3", S3Endpoint: server.URL, S3Region: "us-east-1",
+		S3Bucket: "attachments", S3AccessKey: "minio", S3SecretKey: "minio123"})
+	require.NoError(t, err)
+	testStore(t, store)
+	assert.Empty(t, fake.objects)
+}
+
+func TestNew(t *testing.T) {
+	_, err := blobstore.New(config.BlobCfg{Backend: "ftp"})
+	assert.Error(t, err)
+	_, err = blobstore.New(config.BlobCfg{Backend: "s3", S3Endpoint: "minio:9000", S3Bucket: "b", S3Region: "r", S3AccessKey: "a", S3SecretKey: "s"})
+	assert.Error(t, err)
+	_, err = blobstore.New(config.BlobCfg{Backend: "s3", S3Endpoint: "http://minio:9000", S3Region: "r"})
+	assert.Error(t, err)
+}
+
+func testStore(t *testing.T, store tasktodo.BlobStore) {
+	ctx := context.Background()
+	content := "2024-10-20 12:00:00 ERR connection refused\n"
+	sum := sha256.Sum256([]byte(content))
+	key := hex.EncodeToString(sum[:])
+
+	exists, err := store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = store.Get(ctx, key)
+	assert.EqualError(t, err, tasktodo.BlobNotFoundErr)
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"))
+	require.NoError(t, store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"))
+	exists, err = store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	blob, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, content, string(stored))
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key))
+	exists, err = store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.Error(t, store.Put(ctx, "../../etc/passwd", strings.NewReader(content), int64(len(content)), "text/plain"))
+	_, err = store.Get(ctx, strings.ToUpper(key[:10]))
+	assert.Error(t, err)
+}
+
+// fakeS3 stands in for MinIO: it keeps objects of path-style requests in memory and
+// rejects requests without a signature or with a payload not matching the signed hash.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") ||
+		!strings.HasPrefix(r.URL.Path, "/attachments/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != r.Header.Get("X-Amz-Content-Sha256") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, "XAmzContentSHA256Mismatch")
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,97 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+)
+
+const (
+	keyColumns   = `id, name, prefix, scopes, created_at, last_used_at, revoked_at, owner_id, key_hash`
+	createKeyQry = `INSERT INTO api_keys (id, owner_id, name, prefix, key_hash, scopes, created_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	listKeysQry  = `SELECT ` + keyColumns + ` FROM api_keys WHERE owner_id = $1 ORDER BY created_at`
+	revokeKeyQry = `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND owner_id = $2 AND revoked_at IS NULL`
+	keyByHashQry = `SELECT ` + keyColumns + ` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+	// touchKeyQry records the use of a key at most once a minute to spare writes on busy clients.
+	touchKeyQry = `UPDATE api_keys SET last_used_at = NOW()
+					WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+)
+
+func (db Repo) CreateAPIKey(ctx context.Context, key account.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	_, err := db.DB.Exec(ctx, createKeyQry, key.ID, key.UserID, key.Name, key.Prefix, key.Hash, key.Scopes, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	return nil
+}
+
+// ListAPIKeys returns all keys of the user including the revoked ones.
+func (db Repo) ListAPIKeys(ctx context.Context) ([]account.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	rows, err := db.DB.Query(ctx, listKeysQry, account.UserID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]account.APIKey, 0)
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return keys, nil
+}
+
+func (db Repo) RevokeAPIKey(ctx context.Context, keyID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, revokeKeyQry, keyID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(account.InvalidKeyErr)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash returns the active key with the hash, it is called before the user is known.
+func (db Repo) GetAPIKeyByHash(ctx context.Context, hash string) (account.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	key, err := scanKey(db.DB.QueryRow(ctx, keyByHashQry, hash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return account.APIKey{}, errors.New(account.InvalidKeyErr)
+		}
+		return account.APIKey{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return key, nil
+}
+
+func (db Repo) TouchAPIKey(ctx context.Context, keyID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	if _, err := db.DB.Exec(ctx, touchKeyQry, keyID); err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	return nil
+}
+
+func scanKey(row pgx.Row) (account.APIKey, error) {
+	var key account.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt, &key.UserID, &key.Hash)
+	return key, err
+}
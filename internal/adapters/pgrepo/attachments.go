@@ -0,0 +1,189 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const InvalidAttachmentIdErr = "invalid attachment id"
+
+// blobBatch caps the number of blobs removed by one transaction of the purge, each of them is removed from the store too.
+const blobBatch = 100
+
+// attachmentColumns is selected from attachments aliased as a.
+const attachmentColumns = `a.id, a.task_id, a.name, a.content_type, a.size, a.sha256, a.uploader_id, a.created_at`
+
+const (
+	// createAttachmentQry registers the blob of the content as well. Updating an existing blob locks it,
+	// so the purge can not remove it before the attachment referencing it is committed.
+	createAttachmentQry = `WITH blob AS (
+							INSERT INTO blobs (sha256) VALUES ($4) ON CONFLICT (sha256) DO UPDATE SET used_at = NOW()
+						)
+						INSERT INTO attachments (id, task_id, name, sha256, content_type, size, uploader_id)
+						SELECT $1, id, $3, $4, $5, $6, $7 FROM tasks WHERE id = $2 AND owner_id = $7 AND deleted_at IS NULL
+						RETURNING created_at`
+	listAttachmentsQry = `SELECT ` + attachmentColumns + ` FROM attachments a WHERE a.task_id = $1 ORDER BY a.created_at, a.id`
+	getAttachmentQry   = `SELECT ` + attachmentColumns + ` FROM attachments a JOIN tasks t ON t.id = a.task_id
+						WHERE a.id = $1 AND a.task_id = $2 AND t.owner_id = $3 AND t.deleted_at IS NULL`
+	deleteAttachmentQry = `DELETE FROM attachments a USING tasks t
+						WHERE a.id = $1 AND a.task_id = $2 AND t.id = a.task_id AND t.owner_id = $3 AND t.deleted_at IS NULL`
+	// orphanBlobsQry skips blobs locked by uploads in progress.
+	orphanBlobsQry = `SELECT sha256 FROM blobs b WHERE NOT EXISTS (SELECT 1 FROM attachments a WHERE a.sha256 = b.sha256)
+						LIMIT $1 FOR UPDATE SKIP LOCKED`
+	// deleteBlobsQry checks the references again, the locked blobs may have been attached since orphanBlobsQry started.
+	deleteBlobsQry = `DELETE FROM blobs b WHERE b.sha256 = ANY($1)
+						AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.sha256 = b.sha256)
+						RETURNING b.sha256`
+)
+
+// CreateAttachment stores the metadata of the attachment of the user to the task, the content is put to the blob store separately.
+func (db Repo) CreateAttachment(ctx context.Context, attachment tasktodo.Attachment) (tasktodo.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	err := db.DB.QueryRow(ctx, createAttachmentQry, attachment.ID, attachment.TaskID, attachment.Name, attachment.SHA256,
+		attachment.ContentType, attachment.Size, attachment.UploaderID).Scan(&attachment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Attachment{}, errors.New(InvalidIdErr)
+		}
+		return tasktodo.Attachment{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return attachment, nil
+}
+
+// ListAttachments returns attachments of the task oldest first.
+func (db Repo) ListAttachments(ctx context.Context, taskID string) ([]tasktodo.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err = conn.QueryRow(ctx, existsQry, taskID, account.UserID(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("query execution fail: %v", err)
+	}
+	if !exists {
+		return nil, errors.New(InvalidIdErr)
+	}
+
+	rows, err := conn.Query(ctx, listAttachmentsQry, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	attachments := make([]tasktodo.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return attachments, nil
+}
+
+func (db Repo) GetAttachment(ctx context.Context, taskID, attachmentID string) (tasktodo.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	attachment, err := scanAttachment(db.DB.QueryRow(ctx, getAttachmentQry, attachmentID, taskID, account.UserID(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Attachment{}, errors.New(InvalidAttachmentIdErr)
+		}
+		return tasktodo.Attachment{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return attachment, nil
+}
+
+// DeleteAttachment removes the attachment, its blob is left to PurgeBlobs as other attachments may share it.
+func (db Repo) DeleteAttachment(ctx context.Context, taskID, attachmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, deleteAttachmentQry, attachmentID, taskID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidAttachmentIdErr)
+	}
+	return nil
+}
+
+// PurgeBlobs removes blobs no attachment refers to anymore: the ones of deleted attachments and of purged tasks.
+// The blobs stay locked while remove deletes their contents from the store, so a concurrent upload of the same
+// content waits and puts it again. It works in batches and returns the number of removed blobs.
+func (db Repo) PurgeBlobs(ctx context.Context, remove func(ctx context.Context, key string) error) (int64, error) {
+	var purged int64
+	for {
+		removed, full, err := db.purgeBlobBatch(ctx, remove)
+		purged += removed
+		if err != nil || !full {
+			return purged, err
+		}
+	}
+}
+
+// purgeBlobBatch reports whether the batch was full, so there may be more orphans to remove.
+func (db Repo) purgeBlobBatch(ctx context.Context, remove func(ctx context.Context, key string) error) (int64, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	var locked []string
+	if locked, err = collectKeys(tx.Query(ctx, orphanBlobsQry, blobBatch)); err != nil {
+		return 0, false, err
+	}
+	if len(locked) == 0 {
+		return 0, false, nil
+	}
+	var orphans []string
+	if orphans, err = collectKeys(tx.Query(ctx, deleteBlobsQry, locked)); err != nil {
+		return 0, false, err
+	}
+	for _, key := range orphans {
+		if err = remove(ctx, key); err != nil {
+			return 0, false, fmt.Errorf("removing blob %s fail: %v", key, err)
+		}
+	}
+	return int64(len(orphans)), len(locked) == blobBatch, nil
+}
+
+func collectKeys(rows pgx.Rows, err error) ([]string, error) {
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scanning rows fail: %v", err)
+	}
+	return keys, nil
+}
+
+func scanAttachment(row pgx.Row) (tasktodo.Attachment, error) {
+	var attachment tasktodo.Attachment
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.Name, &attachment.ContentType, &attachment.Size,
+		&attachment.SHA256, &attachment.UploaderID, &attachment.CreatedAt)
+	return attachment, err
+}
@@ -0,0 +1,62 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+// ApplyBulk applies the operations in their order in a single transaction. In an atomic batch the first
+// failing operation rolls back the whole batch and is returned as an OperationError. Otherwise every
+// operation runs under its own savepoint, a failed one is rolled back alone and reported in its outcome.
+func (db Repo) ApplyBulk(ctx context.Context, ops []tasktodo.BulkOp, atomic bool) ([]tasktodo.BulkOutcome, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	outcomes := make([]tasktodo.BulkOutcome, len(ops))
+	for i, op := range ops {
+		if atomic {
+			if outcomes[i].Task, err = db.applyOp(ctx, tx, op); err != nil {
+				err = tasktodo.OperationError{Index: i, Err: err}
+				return nil, err
+			}
+			continue
+		}
+		var savepoint pgx.Tx
+		if savepoint, err = tx.Begin(ctx); err != nil {
+			return nil, fmt.Errorf("savepoint fail: %v", err)
+		}
+		outcomes[i].Task, outcomes[i].Err = db.applyOp(ctx, savepoint, op)
+		txFinisher(ctx, savepoint, outcomes[i].Err)
+	}
+	return outcomes, nil
+}
+
+// applyOp applies a single operation of a batch within the transaction.
+func (db Repo) applyOp(ctx context.Context, tx pgx.Tx, op tasktodo.BulkOp) (tasktodo.Task, error) {
+	switch op.Action {
+	case tasktodo.BulkCreate:
+		return createTask(ctx, tx, op.Task)
+	case tasktodo.BulkUpdate:
+		return db.replaceTask(ctx, tx, op.Task, op.TaskID, op.Version, tasktodo.ActionUpdate)
+	case tasktodo.BulkDelete:
+		return tasktodo.Task{}, deleteTask(ctx, tx, op.TaskID, op.Version)
+	case tasktodo.BulkComplete:
+		return db.patchTask(ctx, tx, op.Patch, op.TaskID, op.Version)
+	}
+	return tasktodo.Task{}, fmt.Errorf("unknown bulk action %q", op.Action)
+}
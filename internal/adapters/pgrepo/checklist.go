@@ -0,0 +1,195 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	InvalidChecklistItemIdErr = "invalid checklist item id"
+	ChecklistOrderErr         = "order must list every checklist item once"
+)
+
+const (
+	listChecklistQry = `SELECT id, text, done, created_at FROM checklist_items WHERE task_id = $1 ORDER BY position, created_at, id`
+	// addChecklistItemQry puts the item at the end, the task row is locked so positions are not taken twice.
+	addChecklistItemQry = `INSERT INTO checklist_items (id, task_id, text, position)
+						SELECT $1, $2, $3, COALESCE(MAX(position), 0) + 1 FROM checklist_items WHERE task_id = $2`
+	countChecklistQry   = `SELECT COUNT(*) FROM checklist_items WHERE task_id = $1`
+	reorderChecklistQry = `UPDATE checklist_items c SET position = o.position
+						FROM unnest($2::varchar[]) WITH ORDINALITY AS o(id, position)
+						WHERE c.task_id = $1 AND c.id = o.id`
+	toggleChecklistItemQry = `UPDATE checklist_items SET done = $3 WHERE id = $1 AND task_id = $2`
+	removeChecklistItemQry = `DELETE FROM checklist_items WHERE id = $1 AND task_id = $2`
+)
+
+// GetChecklist returns the checklist of the task, it has no items if none were added.
+func (db Repo) GetChecklist(ctx context.Context, taskID string) (tasktodo.Checklist, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err = conn.QueryRow(ctx, existsQry, taskID, account.UserID(ctx)).Scan(&exists); err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	if !exists {
+		return tasktodo.Checklist{}, errors.New(InvalidIdErr)
+	}
+	return readChecklist(ctx, conn, taskID)
+}
+
+// AddChecklistItem appends an item to the checklist of the task.
+func (db Repo) AddChecklistItem(ctx context.Context, taskID string, itemReq tasktodo.ChecklistItemRequest) (tasktodo.Checklist, error) {
+	item := tasktodo.NewChecklistItem(itemReq)
+	return db.changeChecklist(ctx, taskID, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, addChecklistItemQry, item.ID, taskID, item.Text); err != nil {
+			return fmt.Errorf("query execution fail: %v", err)
+		}
+		return nil
+	})
+}
+
+// ReorderChecklist puts the items of the checklist in the order of the IDs, which have to list all of them.
+func (db Repo) ReorderChecklist(ctx context.Context, taskID string, itemIDs []string) (tasktodo.Checklist, error) {
+	return db.changeChecklist(ctx, taskID, func(tx pgx.Tx) error {
+		var count int
+		if err := tx.QueryRow(ctx, countChecklistQry, taskID).Scan(&count); err != nil {
+			return fmt.Errorf("query execution fail: %v", err)
+		}
+		if count != len(itemIDs) {
+			return errors.New(ChecklistOrderErr)
+		}
+		res, err := tx.Exec(ctx, reorderChecklistQry, taskID, itemIDs)
+		if err != nil {
+			return fmt.Errorf("query execution fail: %v", err)
+		}
+		// an unknown or repeated ID leaves some item out
+		if res.RowsAffected() != int64(count) {
+			return errors.New(ChecklistOrderErr)
+		}
+		return nil
+	})
+}
+
+// ToggleChecklistItem checks or unchecks the item. A non-zero completeVersion asks to move the task of that version
+// to the done state in the same transaction once every item is checked, the completed task is returned then.
+// A task with open blockers or changed since is left as it is, the item is toggled anyway.
+func (db Repo) ToggleChecklistItem(ctx context.Context, taskID, itemID string, done bool, completeVersion int64) (tasktodo.Checklist, *tasktodo.Task, error) {
+	var completed *tasktodo.Task
+	checklist, err := db.changeChecklist(ctx, taskID, func(tx pgx.Tx) error {
+		if err := execItem(ctx, tx, toggleChecklistItemQry, itemID, taskID, done); err != nil || completeVersion == 0 {
+			return err
+		}
+		checklist, err := readChecklist(ctx, tx, taskID)
+		if err != nil || !checklist.Checked() {
+			return err
+		}
+		completed, err = db.completeChecked(ctx, tx, taskID, completeVersion)
+		return err
+	})
+	if err != nil {
+		return tasktodo.Checklist{}, nil, err
+	}
+	return checklist, completed, nil
+}
+
+// completeChecked moves the task with every checklist item checked to the done state under a savepoint,
+// so that a task with open blockers or of another version is rolled back alone and nil is returned for it.
+func (db Repo) completeChecked(ctx context.Context, tx pgx.Tx, taskID string, version int64) (*tasktodo.Task, error) {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("savepoint fail: %v", err)
+	}
+	done, state := true, tasktodo.StateDone
+	task, err := db.patchTask(ctx, savepoint, tasktodo.Patch{Status: &done, State: &state}, taskID, version)
+	txFinisher(ctx, savepoint, err)
+	if err != nil {
+		if err.Error() == BlockedErr || err.Error() == VersionErr {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RemoveChecklistItem removes the item, the order of the rest is kept.
+func (db Repo) RemoveChecklistItem(ctx context.Context, taskID, itemID string) (tasktodo.Checklist, error) {
+	return db.changeChecklist(ctx, taskID, func(tx pgx.Tx) error {
+		return execItem(ctx, tx, removeChecklistItemQry, itemID, taskID)
+	})
+}
+
+// changeChecklist applies the change to the checklist of a live task of the user and returns the checklist
+// as it is after the change. The task row stays locked until the change is committed, so changes of the same
+// checklist are applied one by one.
+func (db Repo) changeChecklist(ctx context.Context, taskID string, change func(tx pgx.Tx) error) (tasktodo.Checklist, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	if err = lockTask(ctx, tx, taskID, 0); err != nil {
+		return tasktodo.Checklist{}, err
+	}
+	if err = change(tx); err != nil {
+		return tasktodo.Checklist{}, err
+	}
+	checklist, err := readChecklist(ctx, tx, taskID)
+	if err != nil {
+		return tasktodo.Checklist{}, err
+	}
+	return checklist, nil
+}
+
+// execItem runs a query changing a single item, the first argument is the item ID.
+func execItem(ctx context.Context, tx pgx.Tx, qry string, args ...any) error {
+	res, err := tx.Exec(ctx, qry, args...)
+	if err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidChecklistItemIdErr)
+	}
+	return nil
+}
+
+// querier is implemented by both a pooled connection and a transaction.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func readChecklist(ctx context.Context, q querier, taskID string) (tasktodo.Checklist, error) {
+	rows, err := q.Query(ctx, listChecklistQry, taskID)
+	if err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("executing query fail: %v", err)
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (tasktodo.ChecklistItem, error) {
+		var item tasktodo.ChecklistItem
+		err := row.Scan(&item.ID, &item.Text, &item.Done, &item.CreatedAt)
+		return item, err
+	})
+	if err != nil {
+		return tasktodo.Checklist{}, fmt.Errorf("scanning rows fail: %v", err)
+	}
+	return tasktodo.NewChecklist(items), nil
+}
@@ -0,0 +1,138 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const InvalidCommentIdErr = "invalid comment id"
+
+// commentColumns is selected from comments aliased as c.
+const commentColumns = `c.id, c.task_id, c.author_id, c.body, c.html, c.created_at, c.edited_at`
+
+const (
+	// createCommentQry adds the comment only to a live task of the author.
+	createCommentQry = `INSERT INTO comments (id, task_id, author_id, body, html)
+					SELECT $1, id, $3, $4, $5 FROM tasks WHERE id = $2 AND owner_id = $3 AND deleted_at IS NULL
+					RETURNING created_at`
+	listCommentsQry = `SELECT ` + commentColumns + ` FROM comments c
+					WHERE c.task_id = $1 AND c.deleted_at IS NULL AND ($2::timestamptz IS NULL OR (c.created_at, c.id) > ($2, $3))
+					ORDER BY c.created_at, c.id LIMIT $4`
+	updateCommentQry = `UPDATE comments c SET body = $1, html = $2, edited_at = NOW() FROM tasks t
+					WHERE c.id = $3 AND c.task_id = $4 AND c.author_id = $5 AND c.deleted_at IS NULL
+						AND t.id = c.task_id AND t.deleted_at IS NULL
+					RETURNING ` + commentColumns
+	deleteCommentQry = `UPDATE comments c SET deleted_at = NOW() FROM tasks t
+					WHERE c.id = $1 AND c.task_id = $2 AND c.author_id = $3 AND c.deleted_at IS NULL
+						AND t.id = c.task_id AND t.deleted_at IS NULL`
+	countCommentsQry = `SELECT COUNT(*) FROM comments c JOIN tasks t ON t.id = c.task_id
+					WHERE c.task_id = $1 AND t.owner_id = $2 AND c.deleted_at IS NULL`
+)
+
+// CreateComment adds a comment of the user to the task.
+func (db Repo) CreateComment(ctx context.Context, taskID string, commentReq tasktodo.CommentRequest) (tasktodo.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	comment := tasktodo.NewComment(commentReq, taskID, account.UserID(ctx))
+	err := db.DB.QueryRow(ctx, createCommentQry, comment.ID, taskID, comment.AuthorID, comment.Body, comment.HTML).Scan(&comment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Comment{}, errors.New(InvalidIdErr)
+		}
+		return tasktodo.Comment{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return comment, nil
+}
+
+// ListComments returns comments of the task oldest first, continuing past the cursor if there is one.
+func (db Repo) ListComments(ctx context.Context, taskID string, limit uint, after *tasktodo.CommentCursor) ([]tasktodo.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err = conn.QueryRow(ctx, existsQry, taskID, account.UserID(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("query execution fail: %v", err)
+	}
+	if !exists {
+		return nil, errors.New(InvalidIdErr)
+	}
+
+	args := []any{taskID, nil, nil, limit}
+	if after != nil {
+		args[1], args[2] = after.CreatedAt, after.ID
+	}
+	rows, err := conn.Query(ctx, listCommentsQry, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	comments := make([]tasktodo.Comment, 0, limit)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return comments, nil
+}
+
+// UpdateComment replaces the body of a comment of the user and marks the comment as edited.
+func (db Repo) UpdateComment(ctx context.Context, taskID, commentID string, commentReq tasktodo.CommentRequest) (tasktodo.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	row := db.DB.QueryRow(ctx, updateCommentQry, commentReq.Body, tasktodo.RenderMarkdown(commentReq.Body),
+		commentID, taskID, account.UserID(ctx))
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Comment{}, errors.New(InvalidCommentIdErr)
+		}
+		return tasktodo.Comment{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment marks a comment of the user as deleted, like tasks it is kept in the database.
+func (db Repo) DeleteComment(ctx context.Context, taskID, commentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, deleteCommentQry, commentID, taskID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidCommentIdErr)
+	}
+	return nil
+}
+
+// CountComments counts comments of the task which are not deleted.
+func (db Repo) CountComments(ctx context.Context, taskID string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	var count int
+	if err := db.DB.QueryRow(ctx, countCommentsQry, taskID, account.UserID(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("query execution fail: %v", err)
+	}
+	return count, nil
+}
+
+func scanComment(row pgx.Row) (tasktodo.Comment, error) {
+	var comment tasktodo.Comment
+	err := row.Scan(&comment.ID, &comment.TaskID, &comment.AuthorID, &comment.Body, &comment.HTML,
+		&comment.CreatedAt, &comment.EditedAt)
+	return comment, err
+}
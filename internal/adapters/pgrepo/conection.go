@@ -5,16 +5,28 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
 	"os"
 	"time"
 )
 
 type Repo struct {
-	DB  *pgxpool.Pool
-	ctx context.Context
+	DB       *pgxpool.Pool
+	initial  tasktodo.State
+	language string
 }
 
-func NewTasksRepo(ctx context.Context, cfg config.PostgresCfg) (Repo, error) {
+// Option configures optional settings of the Repo.
+type Option func(*Repo)
+
+// WithInitialState sets the state of occurrences generated for recurring tasks.
+func WithInitialState(state tasktodo.State) Option {
+	return func(r *Repo) {
+		r.initial = state
+	}
+}
+
+func NewTasksRepo(ctx context.Context, cfg config.PostgresCfg, opts ...Option) (Repo, error) {
 	url := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.NameDB)
 	dbPool, err := pgxpool.New(ctx, url)
 	if err != nil {
@@ -25,7 +37,10 @@ func NewTasksRepo(ctx context.Context, cfg config.PostgresCfg) (Repo, error) {
 	if err = dbPool.Ping(timeCtx); err != nil {
 		return Repo{}, fmt.Errorf("unable to ping connection pool: %v", err)
 	}
-	instance := Repo{dbPool, ctx}
+	instance := Repo{DB: dbPool, initial: tasktodo.StateTodo, language: cfg.SearchLanguage}
+	for _, opt := range opts {
+		opt(&instance)
+	}
 	if err = instance.NewTable(timeCtx, cfg); err != nil {
 		return Repo{}, err
 	}
@@ -38,6 +53,10 @@ func (db Repo) NewTable(ctx context.Context, cfg config.PostgresCfg) error {
 		if err != nil {
 			return fmt.Errorf("failed to read sql file: %v", err)
 		}
+		// the init script builds the search column with the configured language
+		if _, err = conn.Exec(ctx, searchLanguageQry, cfg.SearchLanguage); err != nil {
+			return fmt.Errorf("failed to set search language: %v", err)
+		}
 		if _, err = conn.Exec(ctx, string(query)); err != nil {
 			return fmt.Errorf("failed to init tables: %v", err)
 		}
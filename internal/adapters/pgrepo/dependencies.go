@@ -0,0 +1,122 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	BlockerErr         = "invalid blocker id"
+	DependencyErr      = "dependency not found"
+	DependencyCycleErr = "dependency cycle"
+)
+
+const (
+	// dependencyLockQry serializes adding dependencies, otherwise two concurrent inserts could close a cycle.
+	dependencyLockQry = `SELECT pg_advisory_xact_lock(hashtext('task_dependencies'))`
+	// dependsQry reports whether the task $1 transitively waits for the task $2.
+	dependsQry = `WITH RECURSIVE blockers AS (
+					SELECT blocker_id FROM task_dependencies WHERE task_id = $1
+					UNION
+					SELECT d.blocker_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.blocker_id
+				)
+				SELECT EXISTS (SELECT 1 FROM blockers WHERE blocker_id = $2)`
+	addDependencyQry    = `INSERT INTO task_dependencies (task_id, blocker_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	removeDependencyQry = `DELETE FROM task_dependencies
+					WHERE task_id = $1 AND blocker_id = $2 AND task_id IN (SELECT id FROM tasks WHERE owner_id = $3)`
+	blockersQry = `SELECT ` + taskColumns + ` FROM tasks
+					WHERE id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = $1) AND deleted_at IS NULL
+					ORDER BY due_date`
+	blockedQry = `SELECT state, ` + blockedExpr + ` FROM tasks WHERE id = $1`
+)
+
+// AddDependency makes the task wait for the blocker. Adding an existing dependency is a no-op.
+func (db Repo) AddDependency(ctx context.Context, taskID, blockerID string) error {
+	if taskID == blockerID {
+		return errors.New(DependencyCycleErr)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	if err = lockTask(ctx, tx, taskID, 0); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, dependencyLockQry); err != nil {
+		return fmt.Errorf("locking dependencies fail: %v", err)
+	}
+
+	var exists, cycle bool
+	if err = tx.QueryRow(ctx, existsQry, blockerID, account.UserID(ctx)).Scan(&exists); err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if !exists {
+		err = errors.New(BlockerErr)
+		return err
+	}
+	if err = tx.QueryRow(ctx, dependsQry, blockerID, taskID).Scan(&cycle); err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if cycle {
+		err = errors.New(DependencyCycleErr)
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, addDependencyQry, taskID, blockerID); err != nil {
+		return fmt.Errorf("exec transaction fail: %v", err)
+	}
+
+	return nil
+}
+
+func (db Repo) RemoveDependency(ctx context.Context, taskID, blockerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, removeDependencyQry, taskID, blockerID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(DependencyErr)
+	}
+	return nil
+}
+
+// ListBlockers returns the tasks the task waits for, including the ones already done.
+func (db Repo) ListBlockers(ctx context.Context, taskID string) ([]tasktodo.Task, error) {
+	return db.listRelated(ctx, taskID, blockersQry)
+}
+
+// completeTask refuses completing a task while any of its blockers is open and reports whether
+// the task is being completed now. A task which is already done is let through, so it stays editable.
+func completeTask(ctx context.Context, tx pgx.Tx, taskID string) (bool, error) {
+	var state tasktodo.State
+	var blocked bool
+	if err := tx.QueryRow(ctx, blockedQry, taskID).Scan(&state, &blocked); err != nil {
+		return false, fmt.Errorf("checking blockers fail: %v", err)
+	}
+	if state == tasktodo.StateDone {
+		return false, nil
+	}
+	if blocked {
+		return false, errors.New(BlockedErr)
+	}
+	return true, nil
+}
@@ -0,0 +1,142 @@
+package pgrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	// snapshotQry reads tasks whether they are deleted or not, the history covers both.
+	snapshotQry    = `SELECT ` + taskColumns + ` FROM tasks WHERE id = ANY($1) ORDER BY id`
+	recordEventQry = `INSERT INTO task_events (task_id, owner_id, action, version, actor, api_key_id, request_id, before, after, snapshot)
+					VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10)`
+	historyQry = `SELECT id, task_id, action, version, actor, COALESCE(api_key_id, ''), request_id, before, after, created_at
+					FROM task_events WHERE task_id = $1 AND owner_id = $2
+					ORDER BY id DESC LIMIT $3 OFFSET $4`
+	taskExistsQry = `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND owner_id = $2)`
+	// versionQry takes the latest event with the version, a deletion keeps the version of the task.
+	versionQry = `SELECT snapshot FROM task_events WHERE task_id = $1 AND owner_id = $2 AND version = $3
+					ORDER BY id DESC LIMIT 1`
+)
+
+// ListHistory returns the changes of the task, the latest first. Only the page and the limit of params apply.
+// The history of a task is kept after it is deleted and even purged.
+func (db Repo) ListHistory(ctx context.Context, taskID string, params tasktodo.ListParams) ([]tasktodo.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	limit := pageLimit(params)
+	rows, err := db.DB.Query(ctx, historyQry, taskID, account.UserID(ctx), limit, params.Page*limit)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	events := make([]tasktodo.Event, 0, limit)
+	for rows.Next() {
+		var event tasktodo.Event
+		err = rows.Scan(&event.ID, &event.TaskID, &event.Action, &event.Version, &event.Actor, &event.APIKeyID,
+			&event.RequestID, &event.Before, &event.After, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	if len(events) == 0 && params.Page == 0 {
+		// tasks created before the history was kept have none
+		var exists bool
+		if err = db.DB.QueryRow(ctx, taskExistsQry, taskID, account.UserID(ctx)).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("query execution fail: %v", err)
+		}
+		if !exists {
+			return nil, errors.New(InvalidIdErr)
+		}
+	}
+	return events, nil
+}
+
+// GetTaskVersion returns the fields the task had at the version according to its history.
+func (db Repo) GetTaskVersion(ctx context.Context, taskID string, version int64) (tasktodo.Request, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	var snapshot tasktodo.Request
+	if err := db.DB.QueryRow(ctx, versionQry, taskID, account.UserID(ctx), version).Scan(&snapshot); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Request{}, errors.New(tasktodo.HistoryVersionErr)
+		}
+		return tasktodo.Request{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return snapshot, nil
+}
+
+// RevertTask updates the task like UpdateTask, the change is recorded in the history as a revert.
+func (db Repo) RevertTask(ctx context.Context, snapshot tasktodo.Request, taskID string, version int64) (tasktodo.Task, error) {
+	return db.updateTask(ctx, snapshot, taskID, version, tasktodo.ActionRevert)
+}
+
+// snapshotTasks reads the tasks locked by the transaction as they are at the moment.
+func snapshotTasks(ctx context.Context, tx pgx.Tx, taskIDs []string) ([]tasktodo.Task, error) {
+	rows, err := tx.Query(ctx, snapshotQry, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("executing snapshot query fail: %v", err)
+	}
+	return scanTasks(rows)
+}
+
+// recordEvents appends the change of the tasks to their history in the same transaction as the change itself.
+// before holds the states of updated tasks prior to the change, an update which left the version as it was
+// changed nothing and is not recorded.
+func recordEvents(ctx context.Context, tx pgx.Tx, action tasktodo.Action, before []tasktodo.Task, after ...tasktodo.Task) error {
+	principal, _ := account.FromContext(ctx)
+	return recordOwnerEvents(ctx, tx, account.UserID(ctx), principal, action, before, after...)
+}
+
+// recordOwnerEvents is recordEvents for tasks of the owner changed by the actor, who is not necessarily the user of the context.
+func recordOwnerEvents(ctx context.Context, tx pgx.Tx, owner string, actor account.Principal, action tasktodo.Action,
+	before []tasktodo.Task, after ...tasktodo.Task) error {
+	previous := make(map[string]tasktodo.Task, len(before))
+	for _, task := range before {
+		previous[task.ID] = task
+	}
+	requestID := middleware.GetReqID(ctx)
+	for _, task := range after {
+		snapshot, err := tasktodo.Fields(task.Request)
+		if err != nil {
+			return fmt.Errorf("encoding task fail: %v", err)
+		}
+		var from, to map[string]json.RawMessage
+		switch action {
+		case tasktodo.ActionCreate:
+			to = snapshot
+		case tasktodo.ActionUpdate, tasktodo.ActionRevert:
+			old, ok := previous[task.ID]
+			if ok && old.Version == task.Version {
+				continue
+			}
+			if from, to, err = tasktodo.Diff(old.Request, task.Request); err != nil {
+				return fmt.Errorf("encoding task fail: %v", err)
+			}
+		}
+		_, err = tx.Exec(ctx, recordEventQry, task.ID, owner, action, task.Version, actor.UserID, actor.KeyID,
+			requestID, jsonArg(from), jsonArg(to), snapshot)
+		if err != nil {
+			return fmt.Errorf("recording task event fail: %v", err)
+		}
+	}
+	return nil
+}
+
+// jsonArg passes a missing set of fields as NULL rather than a JSON null.
+func jsonArg(fields map[string]json.RawMessage) any {
+	if fields == nil {
+		return nil
+	}
+	return fields
+}
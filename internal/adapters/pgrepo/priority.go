@@ -0,0 +1,57 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+// matrixQry ranks open tasks of the user within their quadrants of the Eisenhower matrix and keeps the first ones.
+// It expects the filter on the unaliased tasks table and the argument numbers of the lowest important priority,
+// the number of days making a task urgent and the limit of tasks per quadrant. Days are counted from today
+// in the time zone of the task, so overdue tasks are urgent as well.
+const matrixQry = `SELECT ` + taskColumns + `, m.important, m.urgent, m.total FROM tasks JOIN (
+				SELECT task_id, important, urgent,
+					ROW_NUMBER() OVER (PARTITION BY important, urgent ORDER BY priority, due_date, task_id) AS position,
+					COUNT(*) OVER (PARTITION BY important, urgent) AS total
+				FROM (
+					SELECT id AS task_id, priority, due_date, priority <= $%[2]d::task_priority AS important,
+						due_date <= (NOW() AT TIME ZONE COALESCE(time_zone, 'UTC'))::date + $%[3]d::int AS urgent
+					FROM tasks WHERE owner_id = $1 AND deleted_at IS NULL AND state NOT IN ('done', 'cancelled')%[1]s
+				) candidates
+			) m ON m.task_id = tasks.id
+			WHERE m.position <= $%[4]d
+			ORDER BY tasks.priority, tasks.due_date, tasks.id`
+
+// GetMatrix buckets open tasks of the user into the quadrants of the Eisenhower matrix.
+// Tasks of archived projects are left out unless the project is asked for, like in ListTasks.
+func (db Repo) GetMatrix(ctx context.Context, params tasktodo.MatrixParams) (tasktodo.Matrix, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	filter, args := db.taskFilter(tasktodo.ListParams{ProjectID: params.ProjectID}, []any{account.UserID(ctx)})
+	qry := fmt.Sprintf(matrixQry, filter, len(args)+1, len(args)+2, len(args)+3)
+	args = append(args, tasktodo.ImportantPriority, params.UrgentDays, params.Limit)
+
+	rows, err := db.DB.Query(ctx, qry, args...)
+	if err != nil {
+		return tasktodo.Matrix{}, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	matrix := tasktodo.NewMatrix()
+	for rows.Next() {
+		var important, urgent bool
+		var total int
+		task, err := scanTask(rows, &important, &urgent, &total)
+		if err != nil {
+			return tasktodo.Matrix{}, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		quadrant := matrix.Quadrant(important, urgent)
+		quadrant.Tasks = append(quadrant.Tasks, task)
+		quadrant.Total = total
+	}
+	if err = rows.Err(); err != nil {
+		return tasktodo.Matrix{}, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return matrix, nil
+}
@@ -0,0 +1,219 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	InvalidProjectIdErr = "invalid project id"
+	ProjectArchivedErr  = "project is archived"
+)
+
+// archivedExpr tells whether a row of the unaliased tasks table belongs to an archived project.
+const archivedExpr = `EXISTS (SELECT 1 FROM projects ap WHERE ap.id = tasks.project_id AND ap.archived_at IS NOT NULL)`
+
+// projectColumns is selected from projects p joined with their live tasks t and grouped by p.id.
+const projectColumns = `p.id, p.name, p.description, p.archived_at, COUNT(t.id),
+	COUNT(t.id) FILTER (WHERE t.state NOT IN ('done', 'cancelled')),
+	COUNT(t.id) FILTER (WHERE t.state = 'done'), COUNT(t.id) FILTER (WHERE t.state = 'cancelled')`
+
+const (
+	createProjectQry = `INSERT INTO projects (id, owner_id, name, description) VALUES ($1, $2, $3, $4)`
+	getProjectQry    = `SELECT ` + projectColumns + ` FROM projects p
+					LEFT JOIN tasks t ON t.project_id = p.id AND t.deleted_at IS NULL
+					WHERE p.id = $1 AND p.owner_id = $2 GROUP BY p.id`
+	listProjectsQry = `SELECT ` + projectColumns + ` FROM projects p
+					LEFT JOIN tasks t ON t.project_id = p.id AND t.deleted_at IS NULL
+					WHERE p.owner_id = $1 AND ($2 OR p.archived_at IS NULL) GROUP BY p.id ORDER BY p.name`
+	updateProjectQry  = `UPDATE projects SET name = $1, description = $2 WHERE id = $3 AND owner_id = $4`
+	archiveProjectQry = `UPDATE projects SET archived_at = CASE WHEN $3 THEN COALESCE(archived_at, NOW()) END
+					WHERE id = $1 AND owner_id = $2`
+	deleteProjectQry = `DELETE FROM projects WHERE id = $1 AND owner_id = $2`
+	// checkProjectQry keeps the project from being archived or removed until the end of the transaction.
+	checkProjectQry = `SELECT archived_at IS NOT NULL FROM projects WHERE id = $1 AND owner_id = $2 FOR SHARE`
+	moveTasksQry    = `UPDATE tasks SET project_id = $1, version = version + 1
+					WHERE id = ANY($2) AND deleted_at IS NULL
+					RETURNING ` + taskColumns
+	// lockTasksQry locks the rows in the order of their IDs, so that moves of overlapping sets of tasks do not deadlock.
+	lockTasksQry = `SELECT id FROM tasks WHERE id = ANY($1) AND owner_id = $2 AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+)
+
+func (db Repo) CreateProject(ctx context.Context, projectReq tasktodo.ProjectRequest) (tasktodo.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	project := tasktodo.NewProject(projectReq)
+	if _, err := db.DB.Exec(ctx, createProjectQry, project.ID, account.UserID(ctx), project.Name, project.Description); err != nil {
+		return tasktodo.Project{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return project, nil
+}
+
+func (db Repo) GetProject(ctx context.Context, projectID string) (tasktodo.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return db.getProject(ctx, projectID)
+}
+
+// ListProjects returns projects of the user ordered by name, archived ones only when asked for.
+func (db Repo) ListProjects(ctx context.Context, archived bool) ([]tasktodo.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	rows, err := db.DB.Query(ctx, listProjectsQry, account.UserID(ctx), archived)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	projects := make([]tasktodo.Project, 0, defaultLimit)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		projects = append(projects, project)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return projects, nil
+}
+
+func (db Repo) UpdateProject(ctx context.Context, projectReq tasktodo.ProjectRequest, projectID string) (tasktodo.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return db.execProject(ctx, projectID, updateProjectQry, projectReq.Name, projectReq.Description, projectID, account.UserID(ctx))
+}
+
+// DeleteProject removes the project, its tasks are kept without a project.
+func (db Repo) DeleteProject(ctx context.Context, projectID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, deleteProjectQry, projectID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidProjectIdErr)
+	}
+	return nil
+}
+
+// ArchiveProject archives or restores the project as a whole, archiving twice keeps the first archive time.
+func (db Repo) ArchiveProject(ctx context.Context, projectID string, archived bool) (tasktodo.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return db.execProject(ctx, projectID, archiveProjectQry, projectID, account.UserID(ctx), archived)
+}
+
+// MoveTasks moves all the tasks into the project at once, nothing is moved if any of them is missing.
+func (db Repo) MoveTasks(ctx context.Context, projectID string, taskIDs []string) ([]tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	if err = checkProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+	if err = lockTasks(ctx, tx, taskIDs); err != nil {
+		return nil, err
+	}
+	before, err := snapshotTasks(ctx, tx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, moveTasksQry, projectID, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = recordEvents(ctx, tx, tasktodo.ActionUpdate, before, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// lockTasks locks the live task rows of the user until the end of the transaction, all of them have to exist.
+func lockTasks(ctx context.Context, tx pgx.Tx, taskIDs []string) error {
+	rows, err := tx.Query(ctx, lockTasksQry, taskIDs, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("locking tasks fail: %v", err)
+	}
+	locked, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("locking tasks fail: %v", err)
+	}
+	distinct := make(map[string]bool, len(taskIDs))
+	for _, taskID := range taskIDs {
+		distinct[taskID] = true
+	}
+	if len(locked) != len(distinct) {
+		return errors.New(InvalidIdErr)
+	}
+	return nil
+}
+
+// execProject runs a statement changing the project and returns the project as it became.
+func (db Repo) execProject(ctx context.Context, projectID, qry string, args ...any) (tasktodo.Project, error) {
+	res, err := db.DB.Exec(ctx, qry, args...)
+	if err != nil {
+		return tasktodo.Project{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return tasktodo.Project{}, errors.New(InvalidProjectIdErr)
+	}
+	return db.getProject(ctx, projectID)
+}
+
+func (db Repo) getProject(ctx context.Context, projectID string) (tasktodo.Project, error) {
+	project, err := scanProject(db.DB.QueryRow(ctx, getProjectQry, projectID, account.UserID(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Project{}, errors.New(InvalidProjectIdErr)
+		}
+		return tasktodo.Project{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return project, nil
+}
+
+// checkProject makes sure the project of the user exists and accepts tasks.
+func checkProject(ctx context.Context, tx pgx.Tx, projectID string) error {
+	var archived bool
+	if err := tx.QueryRow(ctx, checkProjectQry, projectID, account.UserID(ctx)).Scan(&archived); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(InvalidProjectIdErr)
+		}
+		return fmt.Errorf("checking project fail: %v", err)
+	}
+	if archived {
+		return errors.New(ProjectArchivedErr)
+	}
+	return nil
+}
+
+func scanProject(row pgx.Row) (tasktodo.Project, error) {
+	var project tasktodo.Project
+	counts := &project.Counts
+	err := row.Scan(&project.ID, &project.Name, &project.Description, &project.ArchivedAt,
+		&counts.Total, &counts.Open, &counts.Done, &counts.Cancelled)
+	return project, err
+}
@@ -0,0 +1,102 @@
+package pgrepo
+
+import (
+	"fmt"
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"strings"
+)
+
+// queryOps maps the comparison operators of the task query to SQL.
+var queryOps = map[taskquery.Op]string{
+	taskquery.OpEq:  "=",
+	taskquery.OpLt:  "<",
+	taskquery.OpLte: "<=",
+	taskquery.OpGt:  ">",
+	taskquery.OpGte: ">=",
+}
+
+// queryFilter compiles a parsed task query into a condition on the unaliased tasks table.
+// Values never get into the SQL text, they are appended to args.
+func (db Repo) queryFilter(node taskquery.Node, args []any) (string, []any) {
+	switch node := node.(type) {
+	case taskquery.And:
+		return db.queryTerms(node.Terms, ` AND `, args)
+	case taskquery.Or:
+		return db.queryTerms(node.Terms, ` OR `, args)
+	case taskquery.Not:
+		cond, args := db.queryFilter(node.Term, args)
+		// IS NOT TRUE keeps NULL comparisons, like the one with a missing project, on the negated side
+		return `(` + cond + `) IS NOT TRUE`, args
+	case taskquery.Text:
+		tsquery := `plainto_tsquery`
+		if node.Phrase {
+			tsquery = `phraseto_tsquery`
+		}
+		return fmt.Sprintf(`search @@ %s($%d::text::regconfig, $%d)`, tsquery, len(args)+1, len(args)+2),
+			append(args, db.language, node.Value)
+	case taskquery.Match:
+		return queryMatch(node, args)
+	}
+	// the parser produces no other nodes
+	return `FALSE`, args
+}
+
+func (db Repo) queryTerms(terms []taskquery.Node, sep string, args []any) (string, []any) {
+	conds := make([]string, 0, len(terms))
+	for _, term := range terms {
+		var cond string
+		cond, args = db.queryFilter(term, args)
+		conds = append(conds, cond)
+	}
+	return `(` + strings.Join(conds, sep) + `)`, args
+}
+
+func queryMatch(match taskquery.Match, args []any) (string, []any) {
+	arg := len(args) + 1
+	switch match.Field {
+	case "status":
+		if match.Value == tasktodo.QueryStatusOpen {
+			return `state NOT IN ('done', 'cancelled')`, args
+		}
+		return `state IN ('done', 'cancelled')`, args
+	case "state":
+		return fmt.Sprintf(`state = $%d`, arg), append(args, match.Value)
+	case "priority":
+		return fmt.Sprintf(`priority = $%d`, arg), append(args, strings.ToUpper(match.Value))
+	case "tag":
+		return fmt.Sprintf(`EXISTS (`+taggedQry+`)`, "1", arg), append(args, []string{match.Value})
+	case "project":
+		if match.Value == tasktodo.NoProject {
+			return `project_id IS NULL`, args
+		}
+		return fmt.Sprintf(`project_id = $%d`, arg), append(args, match.Value)
+	case "title":
+		return fmt.Sprintf(`title ILIKE '%%' || $%d || '%%'`, arg), append(args, likeEscaper.Replace(match.Value))
+	case "due":
+		return fmt.Sprintf(`due_date %s $%d::date`, queryOps[match.Op], arg), append(args, match.Value)
+	case "created":
+		return createdMatch(match.Op, arg), append(args, match.Value)
+	case "blocked":
+		return fmt.Sprintf(`(`+blockedExpr+`) = $%d`, arg), append(args, match.Value)
+	case "overdue":
+		return fmt.Sprintf(`(`+overdueExpr+`) = $%d`, arg), append(args, match.Value)
+	}
+	return `FALSE`, args
+}
+
+// createdMatch compares the creation time with a whole day, so that created:2025-01-01 matches
+// the tasks created that day and created<=2025-01-01 includes them.
+func createdMatch(op taskquery.Op, arg int) string {
+	switch op {
+	case taskquery.OpLt:
+		return fmt.Sprintf(`created_at < $%d::date`, arg)
+	case taskquery.OpLte:
+		return fmt.Sprintf(`created_at < $%d::date + 1`, arg)
+	case taskquery.OpGt:
+		return fmt.Sprintf(`created_at >= $%d::date + 1`, arg)
+	case taskquery.OpGte:
+		return fmt.Sprintf(`created_at >= $%d::date`, arg)
+	}
+	return fmt.Sprintf(`created_at >= $%[1]d::date AND created_at < $%[1]d::date + 1`, arg)
+}
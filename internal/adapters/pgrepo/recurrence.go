@@ -0,0 +1,216 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"time"
+)
+
+const (
+	createSeriesQry = `INSERT INTO task_series (id, title, description, recurrence, owner_id) VALUES ($1, $2, $3, $4, $5)`
+	getSeriesQry    = `SELECT id, title, description, recurrence,
+						(SELECT COUNT(*) FROM tasks WHERE tasks.series_id = task_series.id)
+					FROM task_series WHERE id = $1 AND owner_id = $2`
+	taskSeriesQry = `SELECT COALESCE(series_id, '') FROM tasks WHERE id = $1`
+	detachQry     = `UPDATE tasks SET series_id = NULL WHERE id = $1`
+	// attachQry starts a series for a task which is not recurring yet, the series gets the id of the task.
+	attachQry = `WITH series AS (
+					INSERT INTO task_series (id, title, description, recurrence, owner_id)
+					SELECT id, COALESCE($2, title), COALESCE($3, description), $4, owner_id FROM tasks WHERE id = $1
+					ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description,
+						recurrence = EXCLUDED.recurrence
+					RETURNING id
+				)
+				UPDATE tasks SET series_id = (SELECT id FROM series) WHERE id = $1`
+	setSeriesQry = `UPDATE task_series
+					SET title = COALESCE($2, title), description = COALESCE($3, description), recurrence = COALESCE($4, recurrence)
+					WHERE id = $1`
+	deleteSeriesQry = `DELETE FROM task_series WHERE id = $1`
+	// occurrencesQry locks the occurrences changed by patchOccurrencesQry, their prior state goes to the history.
+	occurrencesQry = `SELECT ` + taskColumns + ` FROM tasks
+					WHERE series_id = $1 AND deleted_at IS NULL AND state NOT IN ('done', 'cancelled') ORDER BY id FOR UPDATE`
+	// patchOccurrencesQry applies series wide changes to the occurrences which are still to be done.
+	patchOccurrencesQry = `UPDATE tasks
+					SET title = COALESCE($2, title), description = COALESCE($3, description), version = version + 1
+					WHERE series_id = $1 AND deleted_at IS NULL AND state NOT IN ('done', 'cancelled')`
+	spawnQry = `INSERT INTO tasks (id, title, description, due_date, status, state, parent_id, series_id, owner_id, project_id, priority, due_time, time_zone)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, '')::time, NULLIF($13, ''))`
+	copyTagsQry = `INSERT INTO task_tags (task_id, tag_id) SELECT $1, tag_id FROM task_tags WHERE task_id = $2`
+)
+
+// GetSeries returns the template of a recurring task series.
+func (db Repo) GetSeries(ctx context.Context, seriesID string) (tasktodo.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return tasktodo.Series{}, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	series, err := getSeries(ctx, conn, seriesID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Series{}, errors.New(tasktodo.NotRecurringErr)
+		}
+		return tasktodo.Series{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return series, nil
+}
+
+// PatchSeries applies title, description and recurrence of the patch to the series of the task
+// and to all of its open occurrences. A removed recurrence ends the series.
+func (db Repo) PatchSeries(ctx context.Context, patch tasktodo.Patch, taskID string, version int64) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	if err = lockTask(ctx, tx, taskID, version); err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	var seriesID string
+	if err = tx.QueryRow(ctx, taskSeriesQry, taskID).Scan(&seriesID); err != nil {
+		return tasktodo.Task{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	if seriesID == "" {
+		err = errors.New(tasktodo.NotRecurringErr)
+		return tasktodo.Task{}, err
+	}
+
+	rows, err := tx.Query(ctx, occurrencesQry, seriesID)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("locking occurrences fail: %v", err)
+	}
+	before, err := scanTasks(rows)
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+	occurrences := make([]string, 0, len(before))
+	for _, occurrence := range before {
+		occurrences = append(occurrences, occurrence.ID)
+	}
+
+	if _, err = tx.Exec(ctx, patchOccurrencesQry, seriesID, patch.Title, patch.Description); err != nil {
+		return tasktodo.Task{}, fmt.Errorf("updating occurrences fail: %v", err)
+	}
+	if patch.Recurrence != nil && *patch.Recurrence == "" {
+		_, err = tx.Exec(ctx, deleteSeriesQry, seriesID)
+	} else {
+		_, err = tx.Exec(ctx, setSeriesQry, seriesID, patch.Title, patch.Description, patch.Recurrence)
+	}
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("updating series fail: %v", err)
+	}
+	after, err := snapshotTasks(ctx, tx, occurrences)
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+	if err = recordEvents(ctx, tx, tasktodo.ActionUpdate, before, after...); err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	task, err := scanTask(tx.QueryRow(ctx, getByIDQry, taskID, account.UserID(ctx)))
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("query execution fail: %v", err)
+	}
+
+	return task, nil
+}
+
+// setRecurrence applies the recurrence of a single occurrence update: nil keeps the series as is,
+// an empty rule detaches the task from its series and any other rule is set on the series,
+// which is started when the task is not recurring yet.
+func setRecurrence(ctx context.Context, tx pgx.Tx, taskID string, rule, title, description *string) error {
+	if rule == nil {
+		return nil
+	}
+	if *rule == "" {
+		if _, err := tx.Exec(ctx, detachQry, taskID); err != nil {
+			return fmt.Errorf("detaching series fail: %v", err)
+		}
+		return nil
+	}
+	var seriesID string
+	if err := tx.QueryRow(ctx, taskSeriesQry, taskID).Scan(&seriesID); err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	if seriesID != "" {
+		if _, err := tx.Exec(ctx, setSeriesQry, seriesID, nil, nil, *rule); err != nil {
+			return fmt.Errorf("updating series fail: %v", err)
+		}
+		return nil
+	}
+	if _, err := tx.Exec(ctx, attachQry, taskID, title, description, *rule); err != nil {
+		return fmt.Errorf("creating series fail: %v", err)
+	}
+	return nil
+}
+
+// spawnOccurrence creates the occurrence following the completed one, unless the series is over.
+// The new occurrence gets the title and description of the series, the due time, time zone, parent, project,
+// priority and tags of the completed one.
+func spawnOccurrence(ctx context.Context, tx pgx.Tx, completed tasktodo.Task, initial tasktodo.State) error {
+	series, err := getSeries(ctx, tx, completed.SeriesID)
+	if err != nil {
+		return fmt.Errorf("query execution fail: %v", err)
+	}
+	dates, err := series.NextDates(completed.DueDate, time.Now(), 1)
+	if err != nil {
+		return err
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+	next := tasktodo.New(tasktodo.Request{
+		Title:       series.Title,
+		Description: series.Description,
+		DueDate:     dates[0],
+		DueTime:     completed.DueTime,
+		TimeZone:    completed.TimeZone,
+		ParentID:    completed.ParentID,
+		ProjectID:   completed.ProjectID,
+		Priority:    completed.Priority,
+	})
+	next.SetState(initial)
+	_, err = tx.Exec(ctx, spawnQry, next.ID, next.Title, next.Description, next.DueDate, next.Status, next.State, next.ParentID, series.ID, account.UserID(ctx), next.ProjectID, next.Priority,
+		next.DueTime, next.TimeZone)
+	if err != nil {
+		return fmt.Errorf("creating occurrence fail: %v", err)
+	}
+	if _, err = tx.Exec(ctx, copyTagsQry, next.ID, completed.ID); err != nil {
+		return fmt.Errorf("copying tags fail: %v", err)
+	}
+	spawned, err := snapshotTasks(ctx, tx, []string{next.ID})
+	if err != nil {
+		return err
+	}
+	return recordEvents(ctx, tx, tasktodo.ActionCreate, nil, spawned...)
+}
+
+// rowQuerier is implemented by both a pooled connection and a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func getSeries(ctx context.Context, q rowQuerier, seriesID string) (tasktodo.Series, error) {
+	var series tasktodo.Series
+	err := q.QueryRow(ctx, getSeriesQry, seriesID, account.UserID(ctx)).Scan(&series.ID, &series.Title, &series.Description, &series.Recurrence, &series.Generated)
+	return series, err
+}
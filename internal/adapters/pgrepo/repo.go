@@ -5,41 +5,94 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rs/zerolog/log"
+	"github.com/vlasashk/task-manager/internal/models/account"
 	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"strings"
 	"time"
 )
 
 const (
 	defaultLimit   = 10
 	defaultTimeout = 10 * time.Second
-)
-
-const (
-	dateViolation = "23514"
+	dateLayout     = "2006-01-02"
 )
 
 const (
 	InvalidIdErr = "invalid task id"
-	DateErr      = "bad date"
+	VersionErr   = "version mismatch"
+	ParentErr    = "invalid parent id"
+	CycleErr     = "task hierarchy cycle"
+	BlockedErr   = "task has open blockers"
 )
 
+// blockedExpr tells whether a row of the unaliased tasks table has an open blocker.
+const blockedExpr = `EXISTS (SELECT 1 FROM task_dependencies dep JOIN tasks blocker ON blocker.id = dep.blocker_id
+	WHERE dep.task_id = tasks.id AND blocker.deleted_at IS NULL AND blocker.state NOT IN ('done', 'cancelled'))`
+
+// taggedQry selects tags of a row of the unaliased tasks table out of the names in an array argument,
+// it expects the select list and the argument number.
+const taggedQry = `SELECT %s FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	WHERE tt.task_id = tasks.id AND tg.name = ANY($%d)`
+
+// dueAtExpr is the moment a row of the unaliased tasks table is due: its due time on the due date in its time zone,
+// the end of the due date if it has no due time and UTC if it has no time zone.
+const dueAtExpr = `(due_date + COALESCE(due_time, '24:00')) AT TIME ZONE COALESCE(time_zone, 'UTC')`
+
+// overdueExpr tells whether a row of the unaliased tasks table is past its due moment and still open.
+const overdueExpr = dueAtExpr + ` < NOW() AND state NOT IN ('done', 'cancelled')`
+
+// sortColumns maps the sort fields accepted from clients to columns, nothing else gets into ORDER BY.
+var sortColumns = map[string]string{
+	"due_date":   "due_date",
+	"title":      "title",
+	"state":      "state",
+	"created_at": "created_at",
+	"priority":   "priority",
+}
+
+// likeEscaper makes user input match literally in LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// taskColumns is selected from an unaliased tasks table, tags and the blocked flag
+// are computed in the same query to avoid a round trip per task.
+const taskColumns = `id, COALESCE(series_id, '') AS series_id, title, description, due_date,
+	COALESCE(to_char(due_time, 'HH24:MI'), '') AS due_time, COALESCE(time_zone, '') AS time_zone, status, state, priority,
+	version, created_at, updated_at, completed_at, parent_id, project_id,
+	(SELECT recurrence FROM task_series WHERE task_series.id = tasks.series_id) AS recurrence,
+	ARRAY(SELECT tg.name FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+		WHERE tt.task_id = tasks.id ORDER BY tg.name) AS tags, ` + blockedExpr + ` AS blocked`
+
 const (
-	createQry  = `INSERT INTO tasks (id, title, description, due_date, status) VALUES ($1, $2, $3, $4, $5)`
-	deleteQry  = `UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	getByIDQry = `SELECT id, title, description, due_date, status 
+	createQry = `INSERT INTO tasks (id, title, description, due_date, status, state, parent_id, series_id, owner_id, project_id, priority,
+						due_time, time_zone)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, NULLIF($12, '')::time, NULLIF($13, ''))
+					RETURNING version, created_at, updated_at, completed_at`
+	deleteQry = `WITH RECURSIVE subtree AS (
+					SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL
+					UNION
+					SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+				)
+				UPDATE tasks SET deleted_at = NOW() WHERE id IN (SELECT id FROM subtree)
+				RETURNING ` + taskColumns
+	getByIDQry = `SELECT ` + taskColumns + ` 
 					FROM tasks 
-					WHERE id = $1 AND deleted_at IS NULL`
+					WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`
+	countQry  = `SELECT COUNT(*) FROM tasks WHERE owner_id = $1 AND deleted_at IS NULL`
+	lockQry   = `SELECT version FROM tasks WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL FOR UPDATE`
 	updateQry = `UPDATE tasks 
-					SET title = $1, description = $2, due_date = $3, status = $4
-        			WHERE id = $5 AND deleted_at IS NULL`
+					SET title = $1, description = $2, due_date = $3, status = $4, state = $5, version = version + 1,
+						parent_id = CASE WHEN $7::varchar IS NULL THEN parent_id ELSE NULLIF($7, '') END,
+						project_id = CASE WHEN $8::varchar IS NULL THEN project_id ELSE NULLIF($8, '') END,
+						priority = COALESCE(NULLIF($9::varchar, '')::task_priority, priority),
+						due_time = NULLIF($10, '')::time, time_zone = NULLIF($11, '')
+        			WHERE id = $6 AND deleted_at IS NULL
+        			RETURNING ` + taskColumns
 )
 
-func (db Repo) CreateTask(taskReq tasktodo.Request) (tasktodo.Task, error) {
-	ctx, cancel := context.WithTimeout(db.ctx, defaultTimeout)
+func (db Repo) CreateTask(ctx context.Context, taskReq tasktodo.Request) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
-	newTask := tasktodo.New(taskReq)
 	conn, err := db.DB.Acquire(ctx)
 	if err != nil {
 		return tasktodo.Task{}, fmt.Errorf("connection acquire fail: %v", err)
@@ -54,19 +107,75 @@ func (db Repo) CreateTask(taskReq tasktodo.Request) (tasktodo.Task, error) {
 		txFinisher(ctx, tx, err)
 	}()
 
-	if _, err = tx.Exec(ctx, createQry, newTask.ID, newTask.Title, newTask.Description, newTask.DueDate, newTask.Status); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			return tasktodo.Task{}, errorHandler(pgErr)
+	newTask, err := createTask(ctx, tx, taskReq)
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+	return newTask, nil
+}
+
+// createTask inserts the task of the user within the transaction.
+func createTask(ctx context.Context, tx pgx.Tx, taskReq tasktodo.Request) (tasktodo.Task, error) {
+	taskReq.SplitDue()
+	newTask := tasktodo.New(taskReq)
+	owner := account.UserID(ctx)
+
+	var parentID *string
+	if newTask.ParentID != nil && *newTask.ParentID != "" {
+		if err := checkParent(ctx, tx, newTask.ID, *newTask.ParentID); err != nil {
+			return tasktodo.Task{}, err
+		}
+		parentID = newTask.ParentID
+	}
+	newTask.ParentID = parentID
+
+	var projectID *string
+	if newTask.ProjectID != nil && *newTask.ProjectID != "" {
+		if err := checkProject(ctx, tx, *newTask.ProjectID); err != nil {
+			return tasktodo.Task{}, err
+		}
+		projectID = newTask.ProjectID
+	}
+	newTask.ProjectID = projectID
+
+	if newTask.Priority == "" {
+		newTask.Priority = tasktodo.DefaultPriority
+	}
+
+	if newTask.Recurrence != nil && *newTask.Recurrence != "" {
+		if _, err := tx.Exec(ctx, createSeriesQry, newTask.ID, newTask.Title, newTask.Description, *newTask.Recurrence, owner); err != nil {
+			return tasktodo.Task{}, fmt.Errorf("creating series fail: %v", err)
 		}
+		newTask.SeriesID = newTask.ID
+	} else {
+		newTask.Recurrence = nil
+	}
+
+	err := tx.QueryRow(ctx, createQry, newTask.ID, newTask.Title, newTask.Description, newTask.DueDate, newTask.Status, newTask.State, parentID, newTask.SeriesID, owner, projectID, newTask.Priority,
+		newTask.DueTime, newTask.TimeZone).Scan(&newTask.Version, &newTask.CreatedAt, &newTask.UpdatedAt, &newTask.CompletedAt)
+	if err != nil {
 		return tasktodo.Task{}, fmt.Errorf("exec transaction fail: %v", err)
 	}
 
+	if err = setTaskTags(ctx, tx, newTask.ID, newTask.Tags); err != nil {
+		return tasktodo.Task{}, err
+	}
+	if newTask.Tags == nil {
+		newTask.Tags = []string{}
+	}
+	if at, ok := newTask.Deadline(); ok {
+		newTask.DueAt = &at
+	}
+
+	if err = recordEvents(ctx, tx, tasktodo.ActionCreate, nil, newTask); err != nil {
+		return tasktodo.Task{}, err
+	}
+
 	return newTask, nil
 }
 
-func (db Repo) DeleteTask(taskID string) error {
-	ctx, cancel := context.WithTimeout(db.ctx, defaultTimeout)
+func (db Repo) DeleteTask(ctx context.Context, taskID string, version int64) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	conn, err := db.DB.Acquire(ctx)
 	if err != nil {
@@ -82,20 +191,30 @@ func (db Repo) DeleteTask(taskID string) error {
 		txFinisher(ctx, tx, err)
 	}()
 
-	res, err := tx.Exec(ctx, deleteQry, taskID)
+	err = deleteTask(ctx, tx, taskID, version)
+	return err
+}
+
+// deleteTask moves the task together with its subtasks to the trash within the transaction.
+func deleteTask(ctx context.Context, tx pgx.Tx, taskID string, version int64) error {
+	if err := lockTask(ctx, tx, taskID, version); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, deleteQry, taskID)
 	if err != nil {
 		return fmt.Errorf("exec transaction fail: %v", err)
 	}
-
-	if res.RowsAffected() == 0 {
-		return errors.New(InvalidIdErr)
+	deleted, err := scanTasks(rows)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return recordEvents(ctx, tx, tasktodo.ActionDelete, nil, deleted...)
 }
 
-func (db Repo) GetTask(taskID string) (tasktodo.Task, error) {
-	ctx, cancel := context.WithTimeout(db.ctx, defaultTimeout)
+func (db Repo) GetTask(ctx context.Context, taskID string) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	conn, err := db.DB.Acquire(ctx)
 	if err != nil {
@@ -103,27 +222,23 @@ func (db Repo) GetTask(taskID string) (tasktodo.Task, error) {
 	}
 	defer conn.Release()
 
-	row := conn.QueryRow(ctx, getByIDQry, taskID)
-
-	var task tasktodo.Task
-	var tempTime time.Time
-	err = row.Scan(&task.ID, &task.Title, &task.Description, &tempTime, &task.Status)
+	task, err := scanTask(conn.QueryRow(ctx, getByIDQry, taskID, account.UserID(ctx)))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return tasktodo.Task{}, errors.New(InvalidIdErr)
 		}
 		return tasktodo.Task{}, fmt.Errorf("query execution fail: %v", err)
 	}
-	task.DueDate = tempTime.Format("2006-01-02")
 	return task, nil
 }
 
-func (db Repo) UpdateTask(newData tasktodo.Request, taskID string) (tasktodo.Task, error) {
-	updTask := tasktodo.Task{
-		ID:      taskID,
-		Request: newData,
-	}
-	ctx, cancel := context.WithTimeout(db.ctx, defaultTimeout)
+func (db Repo) UpdateTask(ctx context.Context, newData tasktodo.Request, taskID string, version int64) (tasktodo.Task, error) {
+	return db.updateTask(ctx, newData, taskID, version, tasktodo.ActionUpdate)
+}
+
+// updateTask replaces the task fields and records the change in the history as the action.
+func (db Repo) updateTask(ctx context.Context, newData tasktodo.Request, taskID string, version int64, action tasktodo.Action) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	conn, err := db.DB.Acquire(ctx)
 	if err != nil {
@@ -139,67 +254,407 @@ func (db Repo) UpdateTask(newData tasktodo.Request, taskID string) (tasktodo.Tas
 		txFinisher(ctx, tx, err)
 	}()
 
-	res, err := tx.Exec(ctx, updateQry, newData.Title, newData.Description, newData.DueDate, newData.Status, taskID)
+	updTask, err := db.replaceTask(ctx, tx, newData, taskID, version, action)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			return tasktodo.Task{}, errorHandler(pgErr)
+		return tasktodo.Task{}, err
+	}
+	return updTask, nil
+}
+
+// replaceTask is updateTask within the transaction.
+func (db Repo) replaceTask(ctx context.Context, tx pgx.Tx, newData tasktodo.Request, taskID string, version int64, action tasktodo.Action) (tasktodo.Task, error) {
+	if err := lockTask(ctx, tx, taskID, version); err != nil {
+		return tasktodo.Task{}, err
+	}
+	before, err := snapshotTasks(ctx, tx, []string{taskID})
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	if newData.Tags != nil {
+		if err = setTaskTags(ctx, tx, taskID, newData.Tags); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	if newData.ParentID != nil && *newData.ParentID != "" {
+		if err = checkParent(ctx, tx, taskID, *newData.ParentID); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	if newData.ProjectID != nil && *newData.ProjectID != "" {
+		if err = checkProject(ctx, tx, *newData.ProjectID); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	newData.SplitDue()
+
+	var completed bool
+	if newData.State == tasktodo.StateDone {
+		if completed, err = completeTask(ctx, tx, taskID); err != nil {
+			return tasktodo.Task{}, err
 		}
+	}
+
+	if err = setRecurrence(ctx, tx, taskID, newData.Recurrence, &newData.Title, &newData.Description); err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	updTask, err := scanTask(tx.QueryRow(ctx, updateQry, newData.Title, newData.Description, newData.DueDate, newData.Status, newData.State, taskID, newData.ParentID, newData.ProjectID, newData.Priority,
+		newData.DueTime, newData.TimeZone))
+	if err != nil {
 		return tasktodo.Task{}, fmt.Errorf("executing update query fail: %v", err)
 	}
+	if err = recordEvents(ctx, tx, action, before, updTask); err != nil {
+		return tasktodo.Task{}, err
+	}
 
-	if res.RowsAffected() == 0 {
-		return tasktodo.Task{}, errors.New(InvalidIdErr)
+	if completed && updTask.SeriesID != "" {
+		if err = spawnOccurrence(ctx, tx, updTask, db.initial); err != nil {
+			return tasktodo.Task{}, err
+		}
 	}
 
 	return updTask, nil
 }
 
-func (db Repo) ListTasks(page uint, date string, status string) ([]tasktodo.Task, error) {
-	ctx, cancel := context.WithTimeout(db.ctx, defaultTimeout)
+// PatchTask applies a merge patch to the task and returns the updated row as stored in the database.
+func (db Repo) PatchTask(ctx context.Context, patch tasktodo.Patch, taskID string, version int64) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	conn, err := db.DB.Acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("connection acquire fail: %v", err)
+		return tasktodo.Task{}, fmt.Errorf("connection acquire fail: %v", err)
 	}
 	defer conn.Release()
-	var args []any
 
-	qry := `SELECT id, title, description, due_date, status FROM tasks WHERE deleted_at IS NULL`
-	args = []any{}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
 
-	if date != "" {
-		qry += fmt.Sprintf(` AND due_date = $%d`, len(args)+1)
-		args = append(args, date)
+	task, err := db.patchTask(ctx, tx, patch, taskID, version)
+	if err != nil {
+		return tasktodo.Task{}, err
 	}
-	if status != "" {
-		qry += fmt.Sprintf(` AND status = $%d`, len(args)+1)
-		args = append(args, status)
+	return task, nil
+}
+
+// patchTask is PatchTask within the transaction.
+func (db Repo) patchTask(ctx context.Context, tx pgx.Tx, patch tasktodo.Patch, taskID string, version int64) (tasktodo.Task, error) {
+	if err := lockTask(ctx, tx, taskID, version); err != nil {
+		return tasktodo.Task{}, err
+	}
+	before, err := snapshotTasks(ctx, tx, []string{taskID})
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	if patch.Tags != nil {
+		if err = setTaskTags(ctx, tx, taskID, *patch.Tags); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	if patch.ParentID != nil && *patch.ParentID != "" {
+		if err = checkParent(ctx, tx, taskID, *patch.ParentID); err != nil {
+			return tasktodo.Task{}, err
+		}
 	}
 
-	qry += fmt.Sprintf(` ORDER BY due_date LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	if patch.ProjectID != nil && *patch.ProjectID != "" {
+		if err = checkProject(ctx, tx, *patch.ProjectID); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
 
-	args = append(args, defaultLimit, page*defaultLimit)
+	if len(before) != 0 {
+		patch.SplitDue(before[0].TimeZone)
+	}
+
+	var completed bool
+	if patch.State != nil && *patch.State == tasktodo.StateDone {
+		if completed, err = completeTask(ctx, tx, taskID); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	if err = setRecurrence(ctx, tx, taskID, patch.Recurrence, patch.Title, patch.Description); err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	qry, args := patchQuery(patch, taskID, account.UserID(ctx))
+	task, err := scanTask(tx.QueryRow(ctx, qry, args...))
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("executing patch query fail: %v", err)
+	}
+	if err = recordEvents(ctx, tx, tasktodo.ActionUpdate, before, task); err != nil {
+		return tasktodo.Task{}, err
+	}
+
+	if completed && task.SeriesID != "" {
+		if err = spawnOccurrence(ctx, tx, task, db.initial); err != nil {
+			return tasktodo.Task{}, err
+		}
+	}
+
+	return task, nil
+}
+
+func (db Repo) ListTasks(ctx context.Context, params tasktodo.ListParams) ([]tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+	filter, args := db.taskFilter(params, []any{account.UserID(ctx)})
+	qry := `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id = $1 AND deleted_at IS NULL` + filter
+
+	if params.After != nil {
+		qry += fmt.Sprintf(` AND (priority, due_date, id) > ($%d::task_priority, $%d::date, $%d)`, len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, params.After.Priority, params.After.DueDate, params.After.ID)
+	}
+
+	qry += fmt.Sprintf(` ORDER BY `+orderBy(params.Sort)+` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+
+	limit := pageLimit(params)
+	args = append(args, limit+params.Lookahead, params.Page*limit)
 
 	rows, err := conn.Query(ctx, qry, args...)
 	if err != nil {
 		return nil, fmt.Errorf("executing query fail: %v", err)
 	}
-	defer rows.Close()
 
+	return scanTasks(rows)
+}
+
+// CountTasks counts tasks matching the listing filters, the page and cursor are ignored.
+func (db Repo) CountTasks(ctx context.Context, params tasktodo.ListParams) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	filter, args := db.taskFilter(params, []any{account.UserID(ctx)})
+	var total int
+	if err := db.DB.QueryRow(ctx, countQry+filter, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("query execution fail: %v", err)
+	}
+	return total, nil
+}
+
+// orderBy turns the sort fields into an ORDER BY list. id breaks ties, so the order is total
+// and cursors, which are only used with the default order, neither skip nor repeat tasks.
+func orderBy(sort []tasktodo.SortField) string {
+	if len(sort) == 0 {
+		return `priority, due_date, id`
+	}
+	terms := make([]string, 0, len(sort)+1)
+	for _, field := range sort {
+		column, ok := sortColumns[field.Field]
+		if !ok {
+			continue
+		}
+		if field.Desc {
+			column += ` DESC`
+		}
+		terms = append(terms, column)
+	}
+	return strings.Join(append(terms, `id`), `, `)
+}
+
+// pageLimit is the page size asked for, defaultLimit if none.
+func pageLimit(params tasktodo.ListParams) uint {
+	if params.Limit == 0 {
+		return defaultLimit
+	}
+	return params.Limit
+}
+
+// taskFilter turns the listing filters into conditions on the unaliased tasks table,
+// their arguments are appended to args.
+func (db Repo) taskFilter(params tasktodo.ListParams, args []any) (string, []any) {
+	var qry string
+	if params.Date != "" {
+		qry += fmt.Sprintf(` AND due_date = $%d`, len(args)+1)
+		args = append(args, params.Date)
+	}
+	if params.Status != "" {
+		qry += fmt.Sprintf(` AND status = $%d`, len(args)+1)
+		args = append(args, params.Status)
+	}
+	if len(params.Tags) != 0 {
+		qry += tagFilter(params.Tags, params.TagMode, len(args)+1)
+		args = append(args, params.Tags)
+	}
+	if params.Blocked != "" {
+		qry += fmt.Sprintf(` AND `+blockedExpr+` = $%d`, len(args)+1)
+		args = append(args, params.Blocked)
+	}
+	if params.DueFrom != "" {
+		qry += fmt.Sprintf(` AND due_date >= $%d`, len(args)+1)
+		args = append(args, params.DueFrom)
+	}
+	if params.DueTo != "" {
+		qry += fmt.Sprintf(` AND due_date <= $%d`, len(args)+1)
+		args = append(args, params.DueTo)
+	}
+	if params.Overdue != "" {
+		qry += fmt.Sprintf(` AND (`+overdueExpr+`) = $%d`, len(args)+1)
+		args = append(args, params.Overdue)
+	}
+	if len(params.Priorities) != 0 {
+		qry += fmt.Sprintf(` AND priority = ANY($%d::varchar[]::task_priority[])`, len(args)+1)
+		args = append(args, params.Priorities)
+	}
+	if params.CreatedAfter != "" {
+		qry += fmt.Sprintf(` AND created_at > $%d::timestamptz`, len(args)+1)
+		args = append(args, params.CreatedAfter)
+	}
+	if params.TitlePrefix != "" {
+		qry += fmt.Sprintf(` AND lower(title) LIKE lower($%d) || '%%'`, len(args)+1)
+		args = append(args, likeEscaper.Replace(params.TitlePrefix))
+	}
+	if params.Query != nil {
+		var cond string
+		cond, args = db.queryFilter(params.Query, args)
+		qry += ` AND ` + cond
+	}
+	switch params.ProjectID {
+	case "":
+		qry += ` AND NOT ` + archivedExpr
+	case tasktodo.NoProject:
+		qry += ` AND project_id IS NULL`
+	default:
+		qry += fmt.Sprintf(` AND project_id = $%d`, len(args)+1)
+		args = append(args, params.ProjectID)
+	}
+	return qry, args
+}
+
+// tagFilter matches tasks having any or all of the tags passed as the argument number arg.
+func tagFilter(tags []string, mode tasktodo.TagMode, arg int) string {
+	if mode == tasktodo.TagModeAll {
+		return fmt.Sprintf(` AND (`+taggedQry+`) = %d`, "COUNT(*)", arg, len(tags))
+	}
+	return fmt.Sprintf(` AND EXISTS (`+taggedQry+`)`, "1", arg)
+}
+
+// lockTask locks the live task row of the user until the end of the transaction and checks
+// that it still has the version expected by the client, 0 means any version.
+func lockTask(ctx context.Context, tx pgx.Tx, taskID string, version int64) error {
+	var current int64
+	if err := tx.QueryRow(ctx, lockQry, taskID, account.UserID(ctx)).Scan(&current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(InvalidIdErr)
+		}
+		return fmt.Errorf("locking task fail: %v", err)
+	}
+	if version != 0 && version != current {
+		return errors.New(VersionErr)
+	}
+	return nil
+}
+
+// patchQuery builds an UPDATE touching only the columns present in the patch.
+// An empty patch results in a plain select of the current row of the owner.
+func patchQuery(patch tasktodo.Patch, taskID, owner string) (string, []any) {
+	if patch.Empty() {
+		return getByIDQry, []any{taskID, owner}
+	}
+	var sets []string
+	var args []any
+	set := func(column string, val any) {
+		args = append(args, val)
+		sets = append(sets, fmt.Sprintf(`%s = $%d`, column, len(args)))
+	}
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+	if patch.DueDate != nil {
+		set("due_date", *patch.DueDate)
+	}
+	if patch.DueTime != nil {
+		var dueTime any
+		if *patch.DueTime != "" {
+			dueTime = *patch.DueTime
+		}
+		set("due_time", dueTime)
+	}
+	if patch.TimeZone != nil {
+		var timeZone any
+		if *patch.TimeZone != "" {
+			timeZone = *patch.TimeZone
+		}
+		set("time_zone", timeZone)
+	}
+	if patch.Status != nil {
+		set("status", *patch.Status)
+	}
+	if patch.State != nil {
+		set("state", *patch.State)
+	}
+	if patch.Priority != nil {
+		set("priority", *patch.Priority)
+	}
+	if patch.ParentID != nil {
+		var parentID any
+		if *patch.ParentID != "" {
+			parentID = *patch.ParentID
+		}
+		set("parent_id", parentID)
+	}
+	if patch.ProjectID != nil {
+		var projectID any
+		if *patch.ProjectID != "" {
+			projectID = *patch.ProjectID
+		}
+		set("project_id", projectID)
+	}
+	sets = append(sets, "version = version + 1")
+	args = append(args, taskID)
+	qry := fmt.Sprintf(`UPDATE tasks SET %s WHERE id = $%d AND deleted_at IS NULL RETURNING `+taskColumns,
+		strings.Join(sets, ", "), len(args))
+	return qry, args
+}
+
+// scanTask reads taskColumns, extra receives the columns selected after them.
+func scanTask(row pgx.Row, extra ...any) (tasktodo.Task, error) {
+	var task tasktodo.Task
+	var dueDate time.Time
+	dest := []any{&task.ID, &task.SeriesID, &task.Title, &task.Description, &dueDate, &task.DueTime, &task.TimeZone,
+		&task.Status, &task.State, &task.Priority, &task.Version, &task.CreatedAt, &task.UpdatedAt, &task.CompletedAt,
+		&task.ParentID, &task.ProjectID, &task.Recurrence, &task.Tags, &task.Blocked}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return tasktodo.Task{}, err
+	}
+	task.DueDate = dueDate.Format(dateLayout)
+	if at, ok := task.Deadline(); ok {
+		task.DueAt = &at
+	}
+	return task, nil
+}
+
+func scanTasks(rows pgx.Rows) ([]tasktodo.Task, error) {
+	defer rows.Close()
 	tasks := make([]tasktodo.Task, 0, defaultLimit)
 
 	for rows.Next() {
-		var task tasktodo.Task
-		var tempTime time.Time
-		if err = rows.Scan(&task.ID, &task.Title, &task.Description, &tempTime, &task.Status); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scanning rows fail: %v", err)
 		}
-		task.DueDate = tempTime.Format("2006-01-02")
 		tasks = append(tasks, task)
 	}
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %v", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	return tasks, nil
@@ -218,12 +673,3 @@ func txFinisher(ctx context.Context, tx pgx.Tx, err error) {
 		}
 	}
 }
-
-func errorHandler(pgErr *pgconn.PgError) error {
-	switch pgErr.Code {
-	case dateViolation:
-		return errors.New(DateErr)
-	default:
-		return pgErr
-	}
-}
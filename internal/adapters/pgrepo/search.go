@@ -0,0 +1,57 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	// searchLanguageQry passes the search language to the init script, see the search column in task.sql.
+	searchLanguageQry = `SELECT set_config('task_manager.search_language', $1, false)`
+	// searchQry expects the text search configuration in $1 and the user query in $2,
+	// the owner is $3 to keep the filter arguments numbered the same way as in ListTasks.
+	// Matches are delimited by the control characters of tasktodo.HighlightStart and HighlightStop rather than
+	// by tags, the fragments are raw user text and are escaped before they are turned into HTML. The delimiters
+	// are stripped from the text first, so that text typed by users can not fake or break the matches.
+	searchQry = `WITH q AS (SELECT $1::text::regconfig AS cfg, websearch_to_tsquery($1::text::regconfig, $2) AS query)
+				SELECT ` + taskColumns + `, ts_rank_cd(search, q.query) AS rank,
+					ts_headline(q.cfg, translate(title, chr(1) || chr(2), ''), q.query, 'StartSel="' || chr(1) || '", StopSel="' || chr(2) || '", HighlightAll=true'),
+					ts_headline(q.cfg, translate(description, chr(1) || chr(2), ''), q.query, 'StartSel="' || chr(1) || '", StopSel="' || chr(2) || '", MaxFragments=3')
+				FROM tasks, q
+				WHERE owner_id = $3 AND deleted_at IS NULL AND search @@ q.query`
+)
+
+// SearchTasks finds tasks of the user matching the query written in the web search syntax:
+// quoted phrases, "or" and "-" for exclusion are supported. Listing filters narrow the result
+// and the listing order breaks ties of rank.
+func (db Repo) SearchTasks(ctx context.Context, query string, params tasktodo.ListParams) ([]tasktodo.SearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	filter, args := db.taskFilter(params, []any{db.language, query, account.UserID(ctx)})
+	qry := searchQry + filter + fmt.Sprintf(` ORDER BY rank DESC, `+orderBy(params.Sort)+` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	limit := pageLimit(params)
+	args = append(args, limit, params.Page*limit)
+
+	rows, err := db.DB.Query(ctx, qry, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	results := make([]tasktodo.SearchResult, 0, defaultLimit)
+	for rows.Next() {
+		var result tasktodo.SearchResult
+		extra := []any{&result.Rank, &result.Highlights.Title, &result.Highlights.Description}
+		if result.Task, err = scanTask(rows, extra...); err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		result.Highlights.Title = tasktodo.Highlight(result.Highlights.Title)
+		result.Highlights.Description = tasktodo.Highlight(result.Highlights.Description)
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	// hierarchyLockQry serializes re-parenting, otherwise two concurrent moves could close a cycle.
+	hierarchyLockQry = `SELECT pg_advisory_xact_lock(hashtext('tasks.parent_id'))`
+	// parentQry walks up from the new parent and reports whether it exists and whether the task is among its ancestors.
+	parentQry = `WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM tasks WHERE id = $1 AND owner_id = $3 AND deleted_at IS NULL
+					UNION
+					SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+				)
+				SELECT COUNT(*) > 0, COALESCE(bool_or(id = $2), false) FROM ancestors`
+	existsQry   = `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL)`
+	childrenQry = `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY due_date`
+	subtreeQry  = `WITH RECURSIVE subtree AS (
+					SELECT id, 1 AS depth FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+					UNION ALL
+					SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+				)
+				SELECT ` + taskColumns + ` FROM tasks JOIN subtree USING (id) ORDER BY subtree.depth, due_date`
+	progressQry = `WITH RECURSIVE subtree AS (
+					SELECT id, state FROM tasks WHERE parent_id = $1 AND owner_id = $2 AND deleted_at IS NULL
+					UNION ALL
+					SELECT t.id, t.state FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+				)
+				SELECT COUNT(*) FILTER (WHERE state <> 'cancelled'), COUNT(*) FILTER (WHERE state = 'done') FROM subtree`
+)
+
+// ListChildren returns direct subtasks of the task.
+func (db Repo) ListChildren(ctx context.Context, taskID string) ([]tasktodo.Task, error) {
+	return db.listRelated(ctx, taskID, childrenQry)
+}
+
+// ListSubtree returns all descendants of the task, closest levels first.
+func (db Repo) ListSubtree(ctx context.Context, taskID string) ([]tasktodo.Task, error) {
+	return db.listRelated(ctx, taskID, subtreeQry)
+}
+
+// GetProgress rolls up completion of all descendants of the task.
+func (db Repo) GetProgress(ctx context.Context, taskID string) (tasktodo.Progress, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	var total, done int
+	if err := db.DB.QueryRow(ctx, progressQry, taskID, account.UserID(ctx)).Scan(&total, &done); err != nil {
+		return tasktodo.Progress{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return tasktodo.NewProgress(total, done), nil
+}
+
+func (db Repo) listRelated(ctx context.Context, taskID, qry string) ([]tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err = conn.QueryRow(ctx, existsQry, taskID, account.UserID(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("query execution fail: %v", err)
+	}
+	if !exists {
+		return nil, errors.New(InvalidIdErr)
+	}
+
+	rows, err := conn.Query(ctx, qry, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+
+	return scanTasks(rows)
+}
+
+// checkParent makes sure the parent is a live task of the same user and that the task is not its ancestor.
+// A new task has no descendants, but it is checked the same way for simplicity.
+func checkParent(ctx context.Context, tx pgx.Tx, taskID, parentID string) error {
+	if _, err := tx.Exec(ctx, hierarchyLockQry); err != nil {
+		return fmt.Errorf("locking hierarchy fail: %v", err)
+	}
+	var exists, cycle bool
+	if err := tx.QueryRow(ctx, parentQry, parentID, taskID, account.UserID(ctx)).Scan(&exists, &cycle); err != nil {
+		return fmt.Errorf("checking parent fail: %v", err)
+	}
+	if !exists {
+		return errors.New(ParentErr)
+	}
+	if cycle {
+		return errors.New(CycleErr)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	uniqueViolation = "23505"
+)
+
+const (
+	InvalidTagIdErr = "invalid tag id"
+	TagExistsErr    = "tag already exists"
+)
+
+const (
+	createTagQry  = `INSERT INTO tags (id, name, owner_id) VALUES ($1, $2, $3)`
+	getTagQry     = `SELECT id, name FROM tags WHERE id = $1 AND owner_id = $2`
+	listTagsQry   = `SELECT id, name FROM tags WHERE owner_id = $1 ORDER BY name`
+	updateTagQry  = `UPDATE tags SET name = $1 WHERE id = $2 AND owner_id = $3`
+	deleteTagQry  = `DELETE FROM tags WHERE id = $1 AND owner_id = $2`
+	upsertTagsQry = `INSERT INTO tags (id, name, owner_id) SELECT id, name, $3 FROM unnest($1::text[], $2::text[]) AS t (id, name)
+					ON CONFLICT (owner_id, name) DO NOTHING`
+	unlinkTagsQry = `DELETE FROM task_tags WHERE task_id = $1`
+	linkTagsQry   = `INSERT INTO task_tags (task_id, tag_id) SELECT $1, id FROM tags WHERE owner_id = $3 AND name = ANY($2)`
+)
+
+func (db Repo) CreateTag(ctx context.Context, tagReq tasktodo.TagRequest) (tasktodo.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	newTag := tasktodo.NewTag(tagReq)
+	if _, err := db.DB.Exec(ctx, createTagQry, newTag.ID, newTag.Name, account.UserID(ctx)); err != nil {
+		return tasktodo.Tag{}, tagErrorHandler(err)
+	}
+	return newTag, nil
+}
+
+func (db Repo) GetTag(ctx context.Context, tagID string) (tasktodo.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	var tag tasktodo.Tag
+	if err := db.DB.QueryRow(ctx, getTagQry, tagID, account.UserID(ctx)).Scan(&tag.ID, &tag.Name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Tag{}, errors.New(InvalidTagIdErr)
+		}
+		return tasktodo.Tag{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return tag, nil
+}
+
+func (db Repo) ListTags(ctx context.Context) ([]tasktodo.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	rows, err := db.DB.Query(ctx, listTagsQry, account.UserID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+
+	tags := make([]tasktodo.Tag, 0)
+	for rows.Next() {
+		var tag tasktodo.Tag
+		if err = rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return tags, nil
+}
+
+func (db Repo) UpdateTag(ctx context.Context, tagReq tasktodo.TagRequest, tagID string) (tasktodo.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, updateTagQry, tagReq.Name, tagID, account.UserID(ctx))
+	if err != nil {
+		return tasktodo.Tag{}, tagErrorHandler(err)
+	}
+	if res.RowsAffected() == 0 {
+		return tasktodo.Tag{}, errors.New(InvalidTagIdErr)
+	}
+	return tasktodo.Tag{ID: tagID, TagRequest: tagReq}, nil
+}
+
+func (db Repo) DeleteTag(ctx context.Context, tagID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, deleteTagQry, tagID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidTagIdErr)
+	}
+	return nil
+}
+
+// setTaskTags replaces the tags of a task, tags the user does not have yet are created.
+func setTaskTags(ctx context.Context, tx pgx.Tx, taskID string, names []string) error {
+	if _, err := tx.Exec(ctx, unlinkTagsQry, taskID); err != nil {
+		return fmt.Errorf("unlinking tags fail: %v", err)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(names))
+	for range names {
+		ids = append(ids, uuid.New().String())
+	}
+	if _, err := tx.Exec(ctx, upsertTagsQry, ids, names, account.UserID(ctx)); err != nil {
+		return fmt.Errorf("creating tags fail: %v", err)
+	}
+	if _, err := tx.Exec(ctx, linkTagsQry, taskID, names, account.UserID(ctx)); err != nil {
+		return fmt.Errorf("linking tags fail: %v", err)
+	}
+	return nil
+}
+
+func tagErrorHandler(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return errors.New(TagExistsErr)
+	}
+	return fmt.Errorf("exec query fail: %v", err)
+}
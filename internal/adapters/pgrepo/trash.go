@@ -0,0 +1,241 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"time"
+)
+
+const ParentDeletedErr = "parent task is deleted"
+
+// purgeBatch caps the number of tasks removed by one statement of the purge,
+// so that it never holds locks on a large part of the table.
+const purgeBatch = 1000
+
+const (
+	trashQry = `SELECT ` + taskColumns + `, deleted_at FROM tasks
+					WHERE owner_id = $1 AND deleted_at IS NOT NULL
+					ORDER BY deleted_at DESC, id LIMIT $2 OFFSET $3`
+	// trashedQry locks the deleted task and reports whether its parent is deleted too.
+	trashedQry = `SELECT p.deleted_at IS NOT NULL FROM tasks t LEFT JOIN tasks p ON p.id = t.parent_id
+					WHERE t.id = $1 AND t.owner_id = $2 AND t.deleted_at IS NOT NULL FOR UPDATE OF t`
+	// restoreQry brings back the task with the subtasks deleted together with it,
+	// subtasks deleted on their own before stay in the trash.
+	restoreQry = `WITH RECURSIVE subtree AS (
+					SELECT id, deleted_at FROM tasks WHERE id = $1
+					UNION
+					SELECT t.id, t.deleted_at FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = s.deleted_at
+				)
+				UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id IN (SELECT id FROM subtree)
+				RETURNING ` + taskColumns
+	// purgeableQry locks the deleted task of the user to be purged.
+	purgeableQry = `SELECT id FROM tasks WHERE id = $1 AND owner_id = $2 AND deleted_at IS NOT NULL FOR UPDATE`
+	// expiredQry locks a batch of tasks to be purged by the retention period. It compares with NOW() as deleteQry
+	// sets deleted_at, so the clocks of the application do not matter.
+	expiredQry = `SELECT id FROM tasks WHERE deleted_at < NOW() - $1::interval LIMIT $2 FOR UPDATE`
+	// purgedQry reads the tasks with their subtasks as they are before the purge, along with their owners.
+	purgedQry = `WITH RECURSIVE subtree AS (
+					SELECT id FROM tasks WHERE id = ANY($1)
+					UNION
+					SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+				)
+				SELECT ` + taskColumns + `, owner_id FROM tasks WHERE id IN (SELECT id FROM subtree) ORDER BY id FOR UPDATE`
+	// purgeQry removes the deleted tasks for good, their subtasks go with them by the parent_id foreign key.
+	purgeQry = `DELETE FROM tasks WHERE id = ANY($1)`
+)
+
+// purgeActor is recorded in the history of the tasks purged by the retention period.
+const purgeActor = "purger"
+
+// ListTrash returns deleted tasks of the user, the latest deleted first. Only the page and the limit of params apply.
+func (db Repo) ListTrash(ctx context.Context, params tasktodo.ListParams) ([]tasktodo.DeletedTask, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	limit := pageLimit(params)
+	rows, err := db.DB.Query(ctx, trashQry, account.UserID(ctx), limit, params.Page*limit)
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	tasks := make([]tasktodo.DeletedTask, 0, limit)
+	for rows.Next() {
+		var task tasktodo.DeletedTask
+		if task.Task, err = scanTask(rows, &task.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return tasks, nil
+}
+
+// RestoreTask takes the task out of the trash. A subtask can not be restored while its parent is deleted.
+func (db Repo) RestoreTask(ctx context.Context, taskID string) (tasktodo.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	// the parent must not be deleted or re-parented while the task is being restored
+	if _, err = tx.Exec(ctx, hierarchyLockQry); err != nil {
+		return tasktodo.Task{}, fmt.Errorf("locking hierarchy fail: %v", err)
+	}
+	var parentDeleted bool
+	if err = tx.QueryRow(ctx, trashedQry, taskID, account.UserID(ctx)).Scan(&parentDeleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.Task{}, errors.New(InvalidIdErr)
+		}
+		return tasktodo.Task{}, fmt.Errorf("checking deleted task fail: %v", err)
+	}
+	if parentDeleted {
+		err = errors.New(ParentDeletedErr)
+		return tasktodo.Task{}, err
+	}
+	rows, err := tx.Query(ctx, restoreQry, taskID)
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("exec transaction fail: %v", err)
+	}
+	restored, err := scanTasks(rows)
+	if err != nil {
+		return tasktodo.Task{}, err
+	}
+	if err = recordEvents(ctx, tx, tasktodo.ActionRestore, nil, restored...); err != nil {
+		return tasktodo.Task{}, err
+	}
+	task, err := scanTask(tx.QueryRow(ctx, getByIDQry, taskID, account.UserID(ctx)))
+	if err != nil {
+		return tasktodo.Task{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return task, nil
+}
+
+// PurgeTask deletes the task from the trash for good, the purge is recorded in the history of the task and its subtasks.
+func (db Repo) PurgeTask(ctx context.Context, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	if err = tx.QueryRow(ctx, purgeableQry, taskID, account.UserID(ctx)).Scan(&taskID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(InvalidIdErr)
+		}
+		return fmt.Errorf("locking deleted task fail: %v", err)
+	}
+	principal, _ := account.FromContext(ctx)
+	_, err = purgeTasks(ctx, tx, []string{taskID}, principal)
+	return err
+}
+
+// PurgeTasks deletes for good the tasks of all users which have been in the trash longer than the retention period.
+// It works in batches and returns the number of removed tasks, not counting subtasks removed with their parents.
+// The purges are recorded in the history of the tasks with the purger as the actor.
+func (db Repo) PurgeTasks(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged int64
+	for {
+		removed, err := db.purgeExpired(ctx, retention)
+		if err != nil {
+			return purged, err
+		}
+		purged += removed
+		if removed < purgeBatch {
+			return purged, nil
+		}
+	}
+}
+
+// purgeExpired purges a batch of tasks expired by the retention period in a transaction of its own.
+func (db Repo) purgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	conn, err := db.DB.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("connection acquire fail: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction fail: %v", err)
+	}
+	defer func() {
+		txFinisher(ctx, tx, err)
+	}()
+
+	rows, err := tx.Query(ctx, expiredQry, retention, purgeBatch)
+	if err != nil {
+		return 0, fmt.Errorf("exec query fail: %v", err)
+	}
+	expired, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("scanning rows fail: %v", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	purged, err := purgeTasks(ctx, tx, expired, account.Principal{UserID: purgeActor})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// purgeTasks records the purge of the locked deleted tasks and their subtasks in their histories and removes them.
+// It returns the number of removed tasks, not counting the subtasks.
+func purgeTasks(ctx context.Context, tx pgx.Tx, taskIDs []string, actor account.Principal) (int64, error) {
+	rows, err := tx.Query(ctx, purgedQry, taskIDs)
+	if err != nil {
+		return 0, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	owned := make(map[string][]tasktodo.Task)
+	for rows.Next() {
+		var owner string
+		task, err := scanTask(rows, &owner)
+		if err != nil {
+			return 0, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		owned[owner] = append(owned[owner], task)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %v", err)
+	}
+	for owner, tasks := range owned {
+		if err = recordOwnerEvents(ctx, tx, owner, actor, tasktodo.ActionPurge, nil, tasks...); err != nil {
+			return 0, err
+		}
+	}
+	res, err := tx.Exec(ctx, purgeQry, taskIDs)
+	if err != nil {
+		return 0, fmt.Errorf("exec query fail: %v", err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,59 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vlasashk/task-manager/internal/models/account"
+)
+
+const (
+	InvalidUserIdErr = "invalid user id"
+)
+
+const (
+	createUserQry     = `INSERT INTO users (id, email, password_hash) VALUES ($1, $2, $3)`
+	getUserQry        = `SELECT id, email, password_hash FROM users WHERE id = $1`
+	getUserByEmailQry = `SELECT id, email, password_hash FROM users WHERE email = $1`
+)
+
+func (db Repo) CreateUser(ctx context.Context, user account.User) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	if _, err := db.DB.Exec(ctx, createUserQry, user.ID, user.Email, user.PasswordHash); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return errors.New(account.EmailTakenErr)
+		}
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	return nil
+}
+
+func (db Repo) GetUser(ctx context.Context, userID string) (account.User, error) {
+	return db.getUser(ctx, getUserQry, userID)
+}
+
+// GetUserByEmail looks the user up by the normalized email, an unknown email is reported as bad credentials.
+func (db Repo) GetUserByEmail(ctx context.Context, email string) (account.User, error) {
+	user, err := db.getUser(ctx, getUserByEmailQry, account.NormalizeEmail(email))
+	if err != nil && err.Error() == InvalidUserIdErr {
+		return account.User{}, errors.New(account.CredentialsErr)
+	}
+	return user, err
+}
+
+func (db Repo) getUser(ctx context.Context, qry, arg string) (account.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	var user account.User
+	if err := db.DB.QueryRow(ctx, qry, arg).Scan(&user.ID, &user.Email, &user.PasswordHash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return account.User{}, errors.New(InvalidUserIdErr)
+		}
+		return account.User{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return user, nil
+}
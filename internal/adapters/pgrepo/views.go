@@ -0,0 +1,112 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+)
+
+const (
+	InvalidViewIdErr = "invalid view id"
+	ViewExistsErr    = "view already exists"
+)
+
+const (
+	createViewQry = `INSERT INTO saved_views (id, owner_id, name, filters, sort, page_size) VALUES ($1, $2, $3, $4, $5, $6)`
+	getViewQry    = `SELECT id, name, filters, sort, page_size FROM saved_views WHERE id = $1 AND owner_id = $2`
+	listViewsQry  = `SELECT id, name, filters, sort, page_size FROM saved_views WHERE owner_id = $1 ORDER BY name`
+	updateViewQry = `UPDATE saved_views SET name = $1, filters = $2, sort = $3, page_size = $4 WHERE id = $5 AND owner_id = $6`
+	deleteViewQry = `DELETE FROM saved_views WHERE id = $1 AND owner_id = $2`
+)
+
+func (db Repo) CreateView(ctx context.Context, viewReq tasktodo.ViewRequest) (tasktodo.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	view := tasktodo.NewView(viewReq)
+	if _, err := db.DB.Exec(ctx, createViewQry, view.ID, account.UserID(ctx), view.Name, view.Filters, view.Sort, view.PageSize); err != nil {
+		return tasktodo.View{}, viewErrorHandler(err)
+	}
+	return view, nil
+}
+
+func (db Repo) GetView(ctx context.Context, viewID string) (tasktodo.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	view, err := scanView(db.DB.QueryRow(ctx, getViewQry, viewID, account.UserID(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tasktodo.View{}, errors.New(InvalidViewIdErr)
+		}
+		return tasktodo.View{}, fmt.Errorf("query execution fail: %v", err)
+	}
+	return view, nil
+}
+
+// ListViews returns views of the user ordered by name.
+func (db Repo) ListViews(ctx context.Context) ([]tasktodo.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	rows, err := db.DB.Query(ctx, listViewsQry, account.UserID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("executing query fail: %v", err)
+	}
+	defer rows.Close()
+	views := make([]tasktodo.View, 0)
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows fail: %v", err)
+		}
+		views = append(views, view)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return views, nil
+}
+
+func (db Repo) UpdateView(ctx context.Context, viewReq tasktodo.ViewRequest, viewID string) (tasktodo.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	view := tasktodo.NewView(viewReq)
+	view.ID = viewID
+	res, err := db.DB.Exec(ctx, updateViewQry, view.Name, view.Filters, view.Sort, view.PageSize, viewID, account.UserID(ctx))
+	if err != nil {
+		return tasktodo.View{}, viewErrorHandler(err)
+	}
+	if res.RowsAffected() == 0 {
+		return tasktodo.View{}, errors.New(InvalidViewIdErr)
+	}
+	return view, nil
+}
+
+func (db Repo) DeleteView(ctx context.Context, viewID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	res, err := db.DB.Exec(ctx, deleteViewQry, viewID, account.UserID(ctx))
+	if err != nil {
+		return fmt.Errorf("exec query fail: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New(InvalidViewIdErr)
+	}
+	return nil
+}
+
+func scanView(row pgx.Row) (tasktodo.View, error) {
+	var view tasktodo.View
+	err := row.Scan(&view.ID, &view.Name, &view.Filters, &view.Sort, &view.PageSize)
+	return view, err
+}
+
+func viewErrorHandler(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return errors.New(ViewExistsErr)
+	}
+	return fmt.Errorf("exec query fail: %v", err)
+}
@@ -0,0 +1,124 @@
+package account
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vlasashk/task-manager/config"
+	"time"
+)
+
+// TokenType tells access tokens, accepted by the API, from refresh tokens, only exchanged for new pairs.
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
+// Tokens is a pair of tokens issued on login or refresh.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type claims struct {
+	Type TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// Issuer signs and verifies tokens of a single algorithm, HS256 or EdDSA.
+type Issuer struct {
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewIssuer prefers an Ed25519 key over the shared secret. Without either a random secret is generated,
+// so tokens do not survive a restart.
+func NewIssuer(cfg config.AuthCfg) (Issuer, error) {
+	issuer := Issuer{
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+	}
+	switch {
+	case cfg.PrivateKey != "":
+		seed, err := base64.StdEncoding.DecodeString(cfg.PrivateKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return Issuer{}, errors.New("ed25519 key must be a base64 encoded 32 byte seed")
+		}
+		key := ed25519.NewKeyFromSeed(seed)
+		issuer.method = jwt.SigningMethodEdDSA
+		issuer.signKey = key
+		issuer.verifyKey = key.Public()
+	default:
+		secret := []byte(cfg.Secret)
+		if len(secret) == 0 {
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return Issuer{}, fmt.Errorf("generating jwt secret fail: %v", err)
+			}
+		}
+		issuer.method = jwt.SigningMethodHS256
+		issuer.signKey = secret
+		issuer.verifyKey = secret
+	}
+	return issuer, nil
+}
+
+// Issue returns a new pair of tokens for the user.
+func (i Issuer) Issue(userID string) (Tokens, error) {
+	access, err := i.sign(userID, AccessToken, i.accessTTL)
+	if err != nil {
+		return Tokens{}, err
+	}
+	refresh, err := i.sign(userID, RefreshToken, i.refreshTTL)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return Tokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(i.accessTTL.Seconds()),
+	}, nil
+}
+
+// Parse verifies the token and returns the id of the user it was issued to.
+func (i Issuer) Parse(token string, typ TokenType) (string, error) {
+	if i.method == nil {
+		return "", errors.New(TokenErr)
+	}
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (any, error) {
+		return i.verifyKey, nil
+	}, jwt.WithValidMethods([]string{i.method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || c.Type != typ || c.Subject == "" {
+		return "", errors.New(TokenErr)
+	}
+	return c.Subject, nil
+}
+
+func (i Issuer) sign(userID string, typ TokenType, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(i.method, claims{
+		Type: typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	})
+	signed, err := token.SignedString(i.signKey)
+	if err != nil {
+		return "", fmt.Errorf("signing token fail: %v", err)
+	}
+	return signed, nil
+}
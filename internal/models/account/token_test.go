@@ -0,0 +1,99 @@
+package account_test
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssuer(t *testing.T) {
+	seed := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	testCases := []struct {
+		name string
+		cfg  config.AuthCfg
+		alg  string
+	}{
+		{name: "hs256", cfg: config.AuthCfg{Secret: "secret", AccessTTL: time.Minute, RefreshTTL: time.Hour}, alg: "HS256"},
+		{name: "eddsa", cfg: config.AuthCfg{Secret: "secret", PrivateKey: seed, AccessTTL: time.Minute, RefreshTTL: time.Hour}, alg: "EdDSA"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer, err := account.NewIssuer(tc.cfg)
+			require.NoError(t, err)
+			tokens, err := issuer.Issue("user")
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", tokens.TokenType)
+			assert.Equal(t, int64(60), tokens.ExpiresIn)
+
+			header, err := base64.RawURLEncoding.DecodeString(strings.Split(tokens.AccessToken, ".")[0])
+			require.NoError(t, err)
+			assert.Contains(t, string(header), `"alg":"`+tc.alg+`"`)
+
+			userID, err := issuer.Parse(tokens.AccessToken, account.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user", userID)
+			userID, err = issuer.Parse(tokens.RefreshToken, account.RefreshToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user", userID)
+
+			_, err = issuer.Parse(tokens.RefreshToken, account.AccessToken)
+			assert.EqualError(t, err, account.TokenErr)
+			_, err = issuer.Parse(tokens.AccessToken+"x", account.AccessToken)
+			assert.EqualError(t, err, account.TokenErr)
+		})
+	}
+}
+
+func TestIssuerRejectsForeignTokens(t *testing.T) {
+	issuer, err := account.NewIssuer(config.AuthCfg{Secret: "secret", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	other, err := account.NewIssuer(config.AuthCfg{Secret: "other", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	expired, err := account.NewIssuer(config.AuthCfg{Secret: "secret", AccessTTL: -time.Minute})
+	require.NoError(t, err)
+
+	for _, source := range []account.Issuer{other, expired} {
+		tokens, err := source.Issue("user")
+		require.NoError(t, err)
+		_, err = issuer.Parse(tokens.AccessToken, account.AccessToken)
+		assert.EqualError(t, err, account.TokenErr)
+	}
+	_, err = account.Issuer{}.Parse("token", account.AccessToken)
+	assert.EqualError(t, err, account.TokenErr)
+}
+
+func TestNewIssuerConfig(t *testing.T) {
+	issuer, err := account.NewIssuer(config.AuthCfg{AccessTTL: time.Minute})
+	require.NoError(t, err)
+	other, err := account.NewIssuer(config.AuthCfg{AccessTTL: time.Minute})
+	require.NoError(t, err)
+	tokens, err := issuer.Issue("user")
+	require.NoError(t, err)
+	_, err = issuer.Parse(tokens.AccessToken, account.AccessToken)
+	assert.NoError(t, err)
+	// every generated secret is different
+	_, err = other.Parse(tokens.AccessToken, account.AccessToken)
+	assert.EqualError(t, err, account.TokenErr)
+	_, err = account.NewIssuer(config.AuthCfg{PrivateKey: "c2hvcnQ="})
+	assert.Error(t, err)
+}
+
+func TestPassword(t *testing.T) {
+	user, err := account.NewUser(account.Credentials{Email: " User@Example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", user.Email)
+	assert.NotEqual(t, "password", user.PasswordHash)
+	assert.NoError(t, user.CheckPassword("password"))
+	assert.EqualError(t, user.CheckPassword("Password"), account.CredentialsErr)
+	assert.EqualError(t, account.UnknownUser.CheckPassword("password"), account.CredentialsErr)
+	// the check against an unknown user costs as much as against a registered one
+	cost, err := bcrypt.Cost([]byte(account.UnknownUser.PasswordHash))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+}
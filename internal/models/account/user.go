@@ -0,0 +1,112 @@
+// Package account describes users of the API and their authentication.
+package account
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+const (
+	EmailTakenErr  = "email already registered"
+	CredentialsErr = "invalid email or password"
+	TokenErr       = "invalid token"
+)
+
+type User struct {
+	ID           string `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+}
+
+// Credentials are used both for registration and login. Bcrypt ignores everything
+// past 72 bytes of a password, so longer ones are rejected instead of being truncated.
+type Credentials struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type Repo interface {
+	CreateUser(ctx context.Context, user User) error
+	GetUser(ctx context.Context, userID string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	CreateAPIKey(ctx context.Context, key APIKey) error
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID string) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID string) error
+}
+
+// NewUser creates a user with the hashed password of the credentials.
+func NewUser(cred Credentials) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(cred.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, err
+	}
+	return User{
+		ID:           uuid.New().String(),
+		Email:        NormalizeEmail(cred.Email),
+		PasswordHash: string(hash),
+	}, nil
+}
+
+// UnknownUser stands in for a user not found by email: checking a password against it takes as long as for
+// a registered user, so the time of a failed login does not tell whether the email is registered.
+// Its hash is of a random password of the default cost, no password of valid credentials matches it.
+var UnknownUser = User{PasswordHash: "$2a$10$n8WMKmlZS3i3oJSw2PKCcOgEUATEn.g/.vf9WKVrKwqy7oNbemRba"}
+
+// CheckPassword compares the password with the stored hash in constant time.
+func (u User) CheckPassword(password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return errors.New(CredentialsErr)
+	}
+	return nil
+}
+
+// NormalizeEmail makes emails differing only in case or surrounding spaces the same account.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type ctxKey struct{}
+
+// Principal is the authenticated caller of a request, either a user session or an API key of the user.
+type Principal struct {
+	UserID string
+	KeyID  string
+	Scopes []Scope
+}
+
+// Allows tells whether the principal was granted the scope.
+func (p Principal) Allows(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrincipal returns a copy of the context carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+// WithUser returns a copy of the context carrying a session of the user, sessions are granted every scope.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return WithPrincipal(ctx, Principal{UserID: userID, Scopes: SessionScopes})
+}
+
+// FromContext returns the authenticated caller of the context.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(ctxKey{}).(Principal)
+	return principal, ok
+}
+
+// UserID returns the authenticated user id of the context, an empty string when there is none.
+func UserID(ctx context.Context) string {
+	principal, _ := FromContext(ctx)
+	return principal.UserID
+}
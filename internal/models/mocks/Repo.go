@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	todo "github.com/vlasashk/task-manager/internal/models/tasktodo"
 )
@@ -12,9 +14,281 @@ type Repo struct {
 	mock.Mock
 }
 
-// CreateTask provides a mock function with given fields: task
-func (_m *Repo) CreateTask(task todo.Request) (todo.Task, error) {
-	ret := _m.Called(task)
+// AddChecklistItem provides a mock function with given fields: ctx, taskID, item
+func (_m *Repo) AddChecklistItem(ctx context.Context, taskID string, item todo.ChecklistItemRequest) (todo.Checklist, error) {
+	ret := _m.Called(ctx, taskID, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddChecklistItem")
+	}
+
+	var r0 todo.Checklist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ChecklistItemRequest) (todo.Checklist, error)); ok {
+		return rf(ctx, taskID, item)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ChecklistItemRequest) todo.Checklist); ok {
+		r0 = rf(ctx, taskID, item)
+	} else {
+		r0 = ret.Get(0).(todo.Checklist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, todo.ChecklistItemRequest) error); ok {
+		r1 = rf(ctx, taskID, item)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddDependency provides a mock function with given fields: ctx, taskID, blockerID
+func (_m *Repo) AddDependency(ctx context.Context, taskID string, blockerID string) error {
+	ret := _m.Called(ctx, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, taskID, blockerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ApplyBulk provides a mock function with given fields: ctx, ops, atomic
+func (_m *Repo) ApplyBulk(ctx context.Context, ops []todo.BulkOp, atomic bool) ([]todo.BulkOutcome, error) {
+	ret := _m.Called(ctx, ops, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBulk")
+	}
+
+	var r0 []todo.BulkOutcome
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []todo.BulkOp, bool) ([]todo.BulkOutcome, error)); ok {
+		return rf(ctx, ops, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []todo.BulkOp, bool) []todo.BulkOutcome); ok {
+		r0 = rf(ctx, ops, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.BulkOutcome)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []todo.BulkOp, bool) error); ok {
+		r1 = rf(ctx, ops, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArchiveProject provides a mock function with given fields: ctx, projectID, archived
+func (_m *Repo) ArchiveProject(ctx context.Context, projectID string, archived bool) (todo.Project, error) {
+	ret := _m.Called(ctx, projectID, archived)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveProject")
+	}
+
+	var r0 todo.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (todo.Project, error)); ok {
+		return rf(ctx, projectID, archived)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) todo.Project); ok {
+		r0 = rf(ctx, projectID, archived)
+	} else {
+		r0 = ret.Get(0).(todo.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, projectID, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountComments provides a mock function with given fields: ctx, taskID
+func (_m *Repo) CountComments(ctx context.Context, taskID string) (int, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountComments")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountTasks provides a mock function with given fields: ctx, params
+func (_m *Repo) CountTasks(ctx context.Context, params todo.ListParams) (int, error) {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTasks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) (int, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) int); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ListParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAttachment provides a mock function with given fields: ctx, attachment
+func (_m *Repo) CreateAttachment(ctx context.Context, attachment todo.Attachment) (todo.Attachment, error) {
+	ret := _m.Called(ctx, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAttachment")
+	}
+
+	var r0 todo.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Attachment) (todo.Attachment, error)); ok {
+		return rf(ctx, attachment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Attachment) todo.Attachment); ok {
+		r0 = rf(ctx, attachment)
+	} else {
+		r0 = ret.Get(0).(todo.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Attachment) error); ok {
+		r1 = rf(ctx, attachment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateComment provides a mock function with given fields: ctx, taskID, comment
+func (_m *Repo) CreateComment(ctx context.Context, taskID string, comment todo.CommentRequest) (todo.Comment, error) {
+	ret := _m.Called(ctx, taskID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 todo.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.CommentRequest) (todo.Comment, error)); ok {
+		return rf(ctx, taskID, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.CommentRequest) todo.Comment); ok {
+		r0 = rf(ctx, taskID, comment)
+	} else {
+		r0 = ret.Get(0).(todo.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, todo.CommentRequest) error); ok {
+		r1 = rf(ctx, taskID, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateProject provides a mock function with given fields: ctx, project
+func (_m *Repo) CreateProject(ctx context.Context, project todo.ProjectRequest) (todo.Project, error) {
+	ret := _m.Called(ctx, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProject")
+	}
+
+	var r0 todo.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ProjectRequest) (todo.Project, error)); ok {
+		return rf(ctx, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ProjectRequest) todo.Project); ok {
+		r0 = rf(ctx, project)
+	} else {
+		r0 = ret.Get(0).(todo.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ProjectRequest) error); ok {
+		r1 = rf(ctx, project)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTag provides a mock function with given fields: ctx, tag
+func (_m *Repo) CreateTag(ctx context.Context, tag todo.TagRequest) (todo.Tag, error) {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTag")
+	}
+
+	var r0 todo.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.TagRequest) (todo.Tag, error)); ok {
+		return rf(ctx, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.TagRequest) todo.Tag); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Get(0).(todo.Tag)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.TagRequest) error); ok {
+		r1 = rf(ctx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTask provides a mock function with given fields: ctx, task
+func (_m *Repo) CreateTask(ctx context.Context, task todo.Request) (todo.Task, error) {
+	ret := _m.Called(ctx, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTask")
@@ -22,17 +296,45 @@ func (_m *Repo) CreateTask(task todo.Request) (todo.Task, error) {
 
 	var r0 todo.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(todo.Request) (todo.Task, error)); ok {
-		return rf(task)
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request) (todo.Task, error)); ok {
+		return rf(ctx, task)
 	}
-	if rf, ok := ret.Get(0).(func(todo.Request) todo.Task); ok {
-		r0 = rf(task)
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request) todo.Task); ok {
+		r0 = rf(ctx, task)
 	} else {
 		r0 = ret.Get(0).(todo.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(todo.Request) error); ok {
-		r1 = rf(task)
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Request) error); ok {
+		r1 = rf(ctx, task)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateView provides a mock function with given fields: ctx, view
+func (_m *Repo) CreateView(ctx context.Context, view todo.ViewRequest) (todo.View, error) {
+	ret := _m.Called(ctx, view)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateView")
+	}
+
+	var r0 todo.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ViewRequest) (todo.View, error)); ok {
+		return rf(ctx, view)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ViewRequest) todo.View); ok {
+		r0 = rf(ctx, view)
+	} else {
+		r0 = ret.Get(0).(todo.View)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ViewRequest) error); ok {
+		r1 = rf(ctx, view)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,17 +342,89 @@ func (_m *Repo) CreateTask(task todo.Request) (todo.Task, error) {
 	return r0, r1
 }
 
-// DeleteTask provides a mock function with given fields: taskID
-func (_m *Repo) DeleteTask(taskID string) error {
-	ret := _m.Called(taskID)
+// DeleteAttachment provides a mock function with given fields: ctx, taskID, attachmentID
+func (_m *Repo) DeleteAttachment(ctx context.Context, taskID string, attachmentID string) error {
+	ret := _m.Called(ctx, taskID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAttachment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, taskID, attachmentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteComment provides a mock function with given fields: ctx, taskID, commentID
+func (_m *Repo) DeleteComment(ctx context.Context, taskID string, commentID string) error {
+	ret := _m.Called(ctx, taskID, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, taskID, commentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProject provides a mock function with given fields: ctx, projectID
+func (_m *Repo) DeleteProject(ctx context.Context, projectID string) error {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, projectID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTag provides a mock function with given fields: ctx, tagID
+func (_m *Repo) DeleteTag(ctx context.Context, tagID string) error {
+	ret := _m.Called(ctx, tagID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, tagID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTask provides a mock function with given fields: ctx, taskID, version
+func (_m *Repo) DeleteTask(ctx context.Context, taskID string, version int64) error {
+	ret := _m.Called(ctx, taskID, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, taskID, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -58,27 +432,45 @@ func (_m *Repo) DeleteTask(taskID string) error {
 	return r0
 }
 
-// GetTask provides a mock function with given fields: taskID
-func (_m *Repo) GetTask(taskID string) (todo.Task, error) {
-	ret := _m.Called(taskID)
+// DeleteView provides a mock function with given fields: ctx, viewID
+func (_m *Repo) DeleteView(ctx context.Context, viewID string) error {
+	ret := _m.Called(ctx, viewID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTask")
+		panic("no return value specified for DeleteView")
 	}
 
-	var r0 todo.Task
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, viewID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAttachment provides a mock function with given fields: ctx, taskID, attachmentID
+func (_m *Repo) GetAttachment(ctx context.Context, taskID string, attachmentID string) (todo.Attachment, error) {
+	ret := _m.Called(ctx, taskID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachment")
+	}
+
+	var r0 todo.Attachment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (todo.Task, error)); ok {
-		return rf(taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (todo.Attachment, error)); ok {
+		return rf(ctx, taskID, attachmentID)
 	}
-	if rf, ok := ret.Get(0).(func(string) todo.Task); ok {
-		r0 = rf(taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) todo.Attachment); ok {
+		r0 = rf(ctx, taskID, attachmentID)
 	} else {
-		r0 = ret.Get(0).(todo.Task)
+		r0 = ret.Get(0).(todo.Attachment)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(taskID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, taskID, attachmentID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,29 +478,27 @@ func (_m *Repo) GetTask(taskID string) (todo.Task, error) {
 	return r0, r1
 }
 
-// ListTasks provides a mock function with given fields: page, date, status
-func (_m *Repo) ListTasks(page uint, date string, status string) ([]todo.Task, error) {
-	ret := _m.Called(page, date, status)
+// GetChecklist provides a mock function with given fields: ctx, taskID
+func (_m *Repo) GetChecklist(ctx context.Context, taskID string) (todo.Checklist, error) {
+	ret := _m.Called(ctx, taskID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListTasks")
+		panic("no return value specified for GetChecklist")
 	}
 
-	var r0 []todo.Task
+	var r0 todo.Checklist
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, string, string) ([]todo.Task, error)); ok {
-		return rf(page, date, status)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Checklist, error)); ok {
+		return rf(ctx, taskID)
 	}
-	if rf, ok := ret.Get(0).(func(uint, string, string) []todo.Task); ok {
-		r0 = rf(page, date, status)
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Checklist); ok {
+		r0 = rf(ctx, taskID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]todo.Task)
-		}
+		r0 = ret.Get(0).(todo.Checklist)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, string, string) error); ok {
-		r1 = rf(page, date, status)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -116,27 +506,994 @@ func (_m *Repo) ListTasks(page uint, date string, status string) ([]todo.Task, e
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: task, taskID
-func (_m *Repo) UpdateTask(task todo.Request, taskID string) (todo.Task, error) {
-	ret := _m.Called(task, taskID)
+// GetMatrix provides a mock function with given fields: ctx, params
+func (_m *Repo) GetMatrix(ctx context.Context, params todo.MatrixParams) (todo.Matrix, error) {
+	ret := _m.Called(ctx, params)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTask")
+		panic("no return value specified for GetMatrix")
 	}
 
-	var r0 todo.Task
+	var r0 todo.Matrix
 	var r1 error
-	if rf, ok := ret.Get(0).(func(todo.Request, string) (todo.Task, error)); ok {
-		return rf(task, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, todo.MatrixParams) (todo.Matrix, error)); ok {
+		return rf(ctx, params)
 	}
-	if rf, ok := ret.Get(0).(func(todo.Request, string) todo.Task); ok {
-		r0 = rf(task, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, todo.MatrixParams) todo.Matrix); ok {
+		r0 = rf(ctx, params)
 	} else {
-		r0 = ret.Get(0).(todo.Task)
+		r0 = ret.Get(0).(todo.Matrix)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.MatrixParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProgress provides a mock function with given fields: ctx, taskID
+func (_m *Repo) GetProgress(ctx context.Context, taskID string) (todo.Progress, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProgress")
+	}
+
+	var r0 todo.Progress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Progress, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Progress); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Get(0).(todo.Progress)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProject provides a mock function with given fields: ctx, projectID
+func (_m *Repo) GetProject(ctx context.Context, projectID string) (todo.Project, error) {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProject")
+	}
+
+	var r0 todo.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Project, error)); ok {
+		return rf(ctx, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Project); ok {
+		r0 = rf(ctx, projectID)
+	} else {
+		r0 = ret.Get(0).(todo.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: ctx, seriesID
+func (_m *Repo) GetSeries(ctx context.Context, seriesID string) (todo.Series, error) {
+	ret := _m.Called(ctx, seriesID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeries")
+	}
+
+	var r0 todo.Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Series, error)); ok {
+		return rf(ctx, seriesID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Series); ok {
+		r0 = rf(ctx, seriesID)
+	} else {
+		r0 = ret.Get(0).(todo.Series)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, seriesID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTag provides a mock function with given fields: ctx, tagID
+func (_m *Repo) GetTag(ctx context.Context, tagID string) (todo.Tag, error) {
+	ret := _m.Called(ctx, tagID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTag")
+	}
+
+	var r0 todo.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Tag, error)); ok {
+		return rf(ctx, tagID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Tag); ok {
+		r0 = rf(ctx, tagID)
+	} else {
+		r0 = ret.Get(0).(todo.Tag)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTask provides a mock function with given fields: ctx, taskID
+func (_m *Repo) GetTask(ctx context.Context, taskID string) (todo.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTask")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskVersion provides a mock function with given fields: ctx, taskID, version
+func (_m *Repo) GetTaskVersion(ctx context.Context, taskID string, version int64) (todo.Request, error) {
+	ret := _m.Called(ctx, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskVersion")
+	}
+
+	var r0 todo.Request
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (todo.Request, error)); ok {
+		return rf(ctx, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) todo.Request); ok {
+		r0 = rf(ctx, taskID, version)
+	} else {
+		r0 = ret.Get(0).(todo.Request)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetView provides a mock function with given fields: ctx, viewID
+func (_m *Repo) GetView(ctx context.Context, viewID string) (todo.View, error) {
+	ret := _m.Called(ctx, viewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetView")
+	}
+
+	var r0 todo.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.View, error)); ok {
+		return rf(ctx, viewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.View); ok {
+		r0 = rf(ctx, viewID)
+	} else {
+		r0 = ret.Get(0).(todo.View)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, viewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAttachments provides a mock function with given fields: ctx, taskID
+func (_m *Repo) ListAttachments(ctx context.Context, taskID string) ([]todo.Attachment, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAttachments")
+	}
+
+	var r0 []todo.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]todo.Attachment, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []todo.Attachment); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBlockers provides a mock function with given fields: ctx, taskID
+func (_m *Repo) ListBlockers(ctx context.Context, taskID string) ([]todo.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBlockers")
+	}
+
+	var r0 []todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]todo.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []todo.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListChildren provides a mock function with given fields: ctx, taskID
+func (_m *Repo) ListChildren(ctx context.Context, taskID string) ([]todo.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListChildren")
+	}
+
+	var r0 []todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]todo.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []todo.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListComments provides a mock function with given fields: ctx, taskID, limit, after
+func (_m *Repo) ListComments(ctx context.Context, taskID string, limit uint, after *todo.CommentCursor) ([]todo.Comment, error) {
+	ret := _m.Called(ctx, taskID, limit, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListComments")
+	}
+
+	var r0 []todo.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, *todo.CommentCursor) ([]todo.Comment, error)); ok {
+		return rf(ctx, taskID, limit, after)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, *todo.CommentCursor) []todo.Comment); ok {
+		r0 = rf(ctx, taskID, limit, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, *todo.CommentCursor) error); ok {
+		r1 = rf(ctx, taskID, limit, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHistory provides a mock function with given fields: ctx, taskID, params
+func (_m *Repo) ListHistory(ctx context.Context, taskID string, params todo.ListParams) ([]todo.Event, error) {
+	ret := _m.Called(ctx, taskID, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListHistory")
+	}
+
+	var r0 []todo.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ListParams) ([]todo.Event, error)); ok {
+		return rf(ctx, taskID, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ListParams) []todo.Event); ok {
+		r0 = rf(ctx, taskID, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, todo.ListParams) error); ok {
+		r1 = rf(ctx, taskID, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProjects provides a mock function with given fields: ctx, archived
+func (_m *Repo) ListProjects(ctx context.Context, archived bool) ([]todo.Project, error) {
+	ret := _m.Called(ctx, archived)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListProjects")
+	}
+
+	var r0 []todo.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]todo.Project, error)); ok {
+		return rf(ctx, archived)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []todo.Project); ok {
+		r0 = rf(ctx, archived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, archived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSubtree provides a mock function with given fields: ctx, taskID
+func (_m *Repo) ListSubtree(ctx context.Context, taskID string) ([]todo.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubtree")
+	}
+
+	var r0 []todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]todo.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []todo.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTags provides a mock function with given fields: ctx
+func (_m *Repo) ListTags(ctx context.Context) ([]todo.Tag, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTags")
+	}
+
+	var r0 []todo.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]todo.Tag, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []todo.Tag); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTasks provides a mock function with given fields: ctx, params
+func (_m *Repo) ListTasks(ctx context.Context, params todo.ListParams) ([]todo.Task, error) {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTasks")
+	}
+
+	var r0 []todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) ([]todo.Task, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) []todo.Task); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ListParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrash provides a mock function with given fields: ctx, params
+func (_m *Repo) ListTrash(ctx context.Context, params todo.ListParams) ([]todo.DeletedTask, error) {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrash")
+	}
+
+	var r0 []todo.DeletedTask
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) ([]todo.DeletedTask, error)); ok {
+		return rf(ctx, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ListParams) []todo.DeletedTask); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.DeletedTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ListParams) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListViews provides a mock function with given fields: ctx
+func (_m *Repo) ListViews(ctx context.Context) ([]todo.View, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListViews")
+	}
+
+	var r0 []todo.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]todo.View, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []todo.View); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveTasks provides a mock function with given fields: ctx, projectID, taskIDs
+func (_m *Repo) MoveTasks(ctx context.Context, projectID string, taskIDs []string) ([]todo.Task, error) {
+	ret := _m.Called(ctx, projectID, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveTasks")
+	}
+
+	var r0 []todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]todo.Task, error)); ok {
+		return rf(ctx, projectID, taskIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []todo.Task); ok {
+		r0 = rf(ctx, projectID, taskIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, projectID, taskIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchSeries provides a mock function with given fields: ctx, patch, taskID, version
+func (_m *Repo) PatchSeries(ctx context.Context, patch todo.Patch, taskID string, version int64) (todo.Task, error) {
+	ret := _m.Called(ctx, patch, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchSeries")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Patch, string, int64) (todo.Task, error)); ok {
+		return rf(ctx, patch, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Patch, string, int64) todo.Task); ok {
+		r0 = rf(ctx, patch, taskID, version)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Patch, string, int64) error); ok {
+		r1 = rf(ctx, patch, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchTask provides a mock function with given fields: ctx, patch, taskID, version
+func (_m *Repo) PatchTask(ctx context.Context, patch todo.Patch, taskID string, version int64) (todo.Task, error) {
+	ret := _m.Called(ctx, patch, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTask")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Patch, string, int64) (todo.Task, error)); ok {
+		return rf(ctx, patch, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Patch, string, int64) todo.Task); ok {
+		r0 = rf(ctx, patch, taskID, version)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Patch, string, int64) error); ok {
+		r1 = rf(ctx, patch, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeTask provides a mock function with given fields: ctx, taskID
+func (_m *Repo) PurgeTask(ctx context.Context, taskID string) error {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveChecklistItem provides a mock function with given fields: ctx, taskID, itemID
+func (_m *Repo) RemoveChecklistItem(ctx context.Context, taskID string, itemID string) (todo.Checklist, error) {
+	ret := _m.Called(ctx, taskID, itemID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveChecklistItem")
+	}
+
+	var r0 todo.Checklist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (todo.Checklist, error)); ok {
+		return rf(ctx, taskID, itemID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) todo.Checklist); ok {
+		r0 = rf(ctx, taskID, itemID)
+	} else {
+		r0 = ret.Get(0).(todo.Checklist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, taskID, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: ctx, taskID, blockerID
+func (_m *Repo) RemoveDependency(ctx context.Context, taskID string, blockerID string) error {
+	ret := _m.Called(ctx, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDependency")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, taskID, blockerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderChecklist provides a mock function with given fields: ctx, taskID, itemIDs
+func (_m *Repo) ReorderChecklist(ctx context.Context, taskID string, itemIDs []string) (todo.Checklist, error) {
+	ret := _m.Called(ctx, taskID, itemIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderChecklist")
+	}
+
+	var r0 todo.Checklist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (todo.Checklist, error)); ok {
+		return rf(ctx, taskID, itemIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) todo.Checklist); ok {
+		r0 = rf(ctx, taskID, itemIDs)
+	} else {
+		r0 = ret.Get(0).(todo.Checklist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, taskID, itemIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTask provides a mock function with given fields: ctx, taskID
+func (_m *Repo) RestoreTask(ctx context.Context, taskID string) (todo.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTask")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (todo.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) todo.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertTask provides a mock function with given fields: ctx, snapshot, taskID, version
+func (_m *Repo) RevertTask(ctx context.Context, snapshot todo.Request, taskID string, version int64) (todo.Task, error) {
+	ret := _m.Called(ctx, snapshot, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertTask")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request, string, int64) (todo.Task, error)); ok {
+		return rf(ctx, snapshot, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request, string, int64) todo.Task); ok {
+		r0 = rf(ctx, snapshot, taskID, version)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Request, string, int64) error); ok {
+		r1 = rf(ctx, snapshot, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTasks provides a mock function with given fields: ctx, query, params
+func (_m *Repo) SearchTasks(ctx context.Context, query string, params todo.ListParams) ([]todo.SearchResult, error) {
+	ret := _m.Called(ctx, query, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []todo.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ListParams) ([]todo.SearchResult, error)); ok {
+		return rf(ctx, query, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, todo.ListParams) []todo.SearchResult); ok {
+		r0 = rf(ctx, query, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]todo.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, todo.ListParams) error); ok {
+		r1 = rf(ctx, query, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToggleChecklistItem provides a mock function with given fields: ctx, taskID, itemID, done, completeVersion
+func (_m *Repo) ToggleChecklistItem(ctx context.Context, taskID string, itemID string, done bool, completeVersion int64) (todo.Checklist, *todo.Task, error) {
+	ret := _m.Called(ctx, taskID, itemID, done, completeVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ToggleChecklistItem")
+	}
+
+	var r0 todo.Checklist
+	var r1 *todo.Task
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, int64) (todo.Checklist, *todo.Task, error)); ok {
+		return rf(ctx, taskID, itemID, done, completeVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, int64) todo.Checklist); ok {
+		r0 = rf(ctx, taskID, itemID, done, completeVersion)
+	} else {
+		r0 = ret.Get(0).(todo.Checklist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool, int64) *todo.Task); ok {
+		r1 = rf(ctx, taskID, itemID, done, completeVersion)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*todo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, bool, int64) error); ok {
+		r2 = rf(ctx, taskID, itemID, done, completeVersion)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateComment provides a mock function with given fields: ctx, taskID, commentID, comment
+func (_m *Repo) UpdateComment(ctx context.Context, taskID string, commentID string, comment todo.CommentRequest) (todo.Comment, error) {
+	ret := _m.Called(ctx, taskID, commentID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComment")
+	}
+
+	var r0 todo.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, todo.CommentRequest) (todo.Comment, error)); ok {
+		return rf(ctx, taskID, commentID, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, todo.CommentRequest) todo.Comment); ok {
+		r0 = rf(ctx, taskID, commentID, comment)
+	} else {
+		r0 = ret.Get(0).(todo.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, todo.CommentRequest) error); ok {
+		r1 = rf(ctx, taskID, commentID, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProject provides a mock function with given fields: ctx, project, projectID
+func (_m *Repo) UpdateProject(ctx context.Context, project todo.ProjectRequest, projectID string) (todo.Project, error) {
+	ret := _m.Called(ctx, project, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProject")
+	}
+
+	var r0 todo.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ProjectRequest, string) (todo.Project, error)); ok {
+		return rf(ctx, project, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ProjectRequest, string) todo.Project); ok {
+		r0 = rf(ctx, project, projectID)
+	} else {
+		r0 = ret.Get(0).(todo.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ProjectRequest, string) error); ok {
+		r1 = rf(ctx, project, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTag provides a mock function with given fields: ctx, tag, tagID
+func (_m *Repo) UpdateTag(ctx context.Context, tag todo.TagRequest, tagID string) (todo.Tag, error) {
+	ret := _m.Called(ctx, tag, tagID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTag")
+	}
+
+	var r0 todo.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.TagRequest, string) (todo.Tag, error)); ok {
+		return rf(ctx, tag, tagID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.TagRequest, string) todo.Tag); ok {
+		r0 = rf(ctx, tag, tagID)
+	} else {
+		r0 = ret.Get(0).(todo.Tag)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.TagRequest, string) error); ok {
+		r1 = rf(ctx, tag, tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTask provides a mock function with given fields: ctx, task, taskID, version
+func (_m *Repo) UpdateTask(ctx context.Context, task todo.Request, taskID string, version int64) (todo.Task, error) {
+	ret := _m.Called(ctx, task, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 todo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request, string, int64) (todo.Task, error)); ok {
+		return rf(ctx, task, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.Request, string, int64) todo.Task); ok {
+		r0 = rf(ctx, task, taskID, version)
+	} else {
+		r0 = ret.Get(0).(todo.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, todo.Request, string, int64) error); ok {
+		r1 = rf(ctx, task, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateView provides a mock function with given fields: ctx, view, viewID
+func (_m *Repo) UpdateView(ctx context.Context, view todo.ViewRequest, viewID string) (todo.View, error) {
+	ret := _m.Called(ctx, view, viewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateView")
+	}
+
+	var r0 todo.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ViewRequest, string) (todo.View, error)); ok {
+		return rf(ctx, view, viewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, todo.ViewRequest, string) todo.View); ok {
+		r0 = rf(ctx, view, viewID)
+	} else {
+		r0 = ret.Get(0).(todo.View)
 	}
 
-	if rf, ok := ret.Get(1).(func(todo.Request, string) error); ok {
-		r1 = rf(task, taskID)
+	if rf, ok := ret.Get(1).(func(context.Context, todo.ViewRequest, string) error); ok {
+		r1 = rf(ctx, view, viewID)
 	} else {
 		r1 = ret.Error(1)
 	}
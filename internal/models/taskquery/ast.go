@@ -0,0 +1,98 @@
+// Package taskquery parses the task query language, e.g. `status:open tag:backend due<2025-01-01 "release notes"`.
+//
+// A query is a list of terms which all have to match, OR between terms makes either of them enough,
+// a leading "-" or NOT negates a term and parentheses group terms. A term is a field match
+// like tag:backend or due<2025-01-01, a bare word or a quoted phrase. Field values can be quoted too.
+// The fields, their kinds and allowed values come from the Schema the query is parsed with.
+package taskquery
+
+import "fmt"
+
+// MaxTerms caps the number of field matches, words and phrases in a query.
+const MaxTerms = 32
+
+// Op compares a field with a value.
+type Op string
+
+const (
+	OpEq  Op = ":"
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// Kind tells how the value of a field is read and which operators the field accepts.
+type Kind int
+
+const (
+	// KindText accepts any value with ":".
+	KindText Kind = iota
+	// KindEnum accepts one of the field values with ":", regardless of case.
+	KindEnum
+	// KindBool accepts true or false with ":".
+	KindBool
+	// KindDate accepts a date in the YYYY-MM-DD format with any operator.
+	KindDate
+)
+
+type Field struct {
+	Kind   Kind
+	Values []string
+}
+
+// Schema maps lowercase field names to their definitions.
+type Schema map[string]Field
+
+// Node is an element of a parsed query. Pos is the 1-based position of its first character in runes.
+type Node interface {
+	Pos() int
+}
+
+// And matches when all of its terms do.
+type And struct {
+	Terms []Node
+}
+
+// Or matches when any of its terms does.
+type Or struct {
+	Terms []Node
+}
+
+// Not matches when its term does not.
+type Not struct {
+	Term Node
+	At   int
+}
+
+// Match compares a field with a value. Enum values are lowercased, bool values are "true" or "false".
+type Match struct {
+	Field string
+	Op    Op
+	Value string
+	At    int
+}
+
+// Text matches a bare word or, when Phrase is set, the words of a quoted phrase in this order.
+type Text struct {
+	Value  string
+	Phrase bool
+	At     int
+}
+
+func (n And) Pos() int   { return n.Terms[0].Pos() }
+func (n Or) Pos() int    { return n.Terms[0].Pos() }
+func (n Not) Pos() int   { return n.At }
+func (n Match) Pos() int { return n.At }
+func (n Text) Pos() int  { return n.At }
+
+// Error points at the token of the query which could not be parsed.
+type Error struct {
+	Pos   int
+	Token string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
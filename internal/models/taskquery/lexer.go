@@ -0,0 +1,78 @@
+package taskquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokPhrase
+	tokOp
+	tokMinus
+	tokLParen
+	tokRParen
+)
+
+// token is a lexeme of the query, pos and end are 1-based rune positions of its first
+// character and of the character right after it.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// lex splits the query into tokens. A "-" is only read as negation at the start of a term,
+// inside a word like 2025-01-01 it is a part of the word.
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i + 1, end: i + 2})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i + 1, end: i + 2})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: i + 1, end: i + 2})
+			i++
+		case r == '"':
+			closing := i + 1
+			for closing < len(runes) && runes[closing] != '"' {
+				closing++
+			}
+			if closing == len(runes) {
+				return nil, &Error{Pos: i + 1, Token: string(runes[i:]), Msg: "unterminated quote"}
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: string(runes[i+1 : closing]), pos: i + 1, end: closing + 2})
+			i = closing + 1
+		case r == ':' || r == '<' || r == '>':
+			op := string(r)
+			if r != ':' && i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i + 1, end: i + len(op) + 1})
+			i += len(op)
+		default:
+			start := i
+			for i < len(runes) && !isDelimiter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[start:i]), pos: start + 1, end: i + 1})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes) + 1, end: len(runes) + 1}), nil
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()":<>`, r)
+}
@@ -0,0 +1,216 @@
+package taskquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type parser struct {
+	tokens []token
+	next   int
+	schema Schema
+	terms  int
+}
+
+// Parse reads the query into a tree of nodes checked against the schema.
+// Errors are of the *Error type and point at the offending token.
+func Parse(query string, schema Schema) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, schema: schema}
+	if p.peek().kind == tokEOF {
+		return nil, &Error{Pos: 1, Msg: "empty query"}
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, unexpected(tok)
+	}
+	return node, nil
+}
+
+// parseOr reads terms joined by OR, it binds looser than the implicit AND.
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Node{node}
+	for isKeyword(p.peek(), "OR") {
+		p.take()
+		if node, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		terms = append(terms, node)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return Or{Terms: terms}, nil
+}
+
+// parseAnd reads terms up to OR, a closing parenthesis or the end of the query,
+// the AND keyword between them is optional.
+func (p *parser) parseAnd() (Node, error) {
+	var terms []Node
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || tok.kind == tokRParen || isKeyword(tok, "OR") {
+			break
+		}
+		if len(terms) != 0 && isKeyword(tok, "AND") {
+			p.take()
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, node)
+	}
+	switch len(terms) {
+	case 0:
+		return nil, unexpected(p.peek())
+	case 1:
+		return terms[0], nil
+	}
+	return And{Terms: terms}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if tok.kind == tokMinus || isKeyword(tok, "NOT") {
+		p.take()
+		if next := p.peek(); tok.kind == tokMinus && next.pos != tok.end {
+			return nil, &Error{Pos: tok.pos, Token: tok.text, Msg: "expected a term right after -"}
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Term: node, At: tok.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.take()
+	switch tok.kind {
+	case tokLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, &Error{Pos: tok.pos, Token: tok.text, Msg: "missing closing parenthesis"}
+		}
+		p.take()
+		return node, nil
+	case tokPhrase:
+		if strings.TrimSpace(tok.text) == "" {
+			return nil, &Error{Pos: tok.pos, Token: `""`, Msg: "empty phrase"}
+		}
+		return p.count(Text{Value: tok.text, Phrase: true, At: tok.pos})
+	case tokWord:
+		if op := p.peek(); op.kind == tokOp && op.pos == tok.end {
+			return p.parseMatch(tok)
+		}
+		if isKeyword(tok, "AND") || isKeyword(tok, "OR") || isKeyword(tok, "NOT") {
+			return nil, unexpected(tok)
+		}
+		return p.count(Text{Value: tok.text, At: tok.pos})
+	}
+	return nil, unexpected(tok)
+}
+
+// parseMatch reads the operator and the value following the field name.
+func (p *parser) parseMatch(name token) (Node, error) {
+	fieldName := strings.ToLower(name.text)
+	field, ok := p.schema[fieldName]
+	if !ok {
+		return nil, &Error{Pos: name.pos, Token: name.text, Msg: "unknown field"}
+	}
+	op := p.take()
+	value := p.peek()
+	if (value.kind != tokWord && value.kind != tokPhrase) || value.pos != op.end {
+		return nil, &Error{Pos: op.pos, Token: name.text + op.text, Msg: "missing value of " + fieldName}
+	}
+	p.take()
+	match := Match{Field: fieldName, Op: Op(op.text), Value: value.text, At: name.pos}
+	if field.Kind != KindDate && match.Op != OpEq {
+		return nil, &Error{Pos: op.pos, Token: op.text, Msg: fmt.Sprintf("operator %s is not supported by %s", op.text, fieldName)}
+	}
+	invalid := func(msg string) error {
+		return &Error{Pos: value.pos, Token: value.text, Msg: msg}
+	}
+	switch field.Kind {
+	case KindText:
+		if strings.TrimSpace(match.Value) == "" {
+			return nil, invalid("missing value of " + fieldName)
+		}
+	case KindEnum:
+		match.Value = strings.ToLower(match.Value)
+		if !contains(field.Values, match.Value) {
+			return nil, invalid(fmt.Sprintf("%s must be one of %s", fieldName, strings.Join(field.Values, ", ")))
+		}
+	case KindBool:
+		parsed, err := strconv.ParseBool(match.Value)
+		if err != nil {
+			return nil, invalid(fieldName + " must be true or false")
+		}
+		match.Value = strconv.FormatBool(parsed)
+	case KindDate:
+		if _, err := time.Parse(time.DateOnly, match.Value); err != nil {
+			return nil, invalid(fieldName + " must be a date (YYYY-MM-DD)")
+		}
+	}
+	return p.count(match)
+}
+
+// count keeps the query within MaxTerms.
+func (p *parser) count(node Node) (Node, error) {
+	if p.terms++; p.terms > MaxTerms {
+		return nil, &Error{Pos: node.Pos(), Msg: fmt.Sprintf("more than %d terms", MaxTerms)}
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// take consumes the current token, the final EOF token is never consumed.
+func (p *parser) take() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+// isKeyword reports whether the token is an operator keyword, keywords are uppercase
+// so that the lowercase words stay searchable.
+func isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokWord && tok.text == keyword
+}
+
+func unexpected(tok token) error {
+	if tok.kind == tokEOF {
+		return &Error{Pos: tok.pos, Msg: "unexpected end of query"}
+	}
+	return &Error{Pos: tok.pos, Token: tok.text, Msg: "unexpected " + tok.text}
+}
+
+func contains(values []string, value string) bool {
+	for _, known := range values {
+		if known == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package taskquery_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
+	"strings"
+	"testing"
+)
+
+var schema = taskquery.Schema{
+	"status": {Kind: taskquery.KindEnum, Values: []string{"open", "closed"}},
+	"tag":    {Kind: taskquery.KindText},
+	"due":    {Kind: taskquery.KindDate},
+	"done":   {Kind: taskquery.KindBool},
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected taskquery.Node
+	}{
+		{
+			query: `status:open tag:backend due<2025-01-01 "release notes"`,
+			expected: taskquery.And{Terms: []taskquery.Node{
+				taskquery.Match{Field: "status", Op: taskquery.OpEq, Value: "open", At: 1},
+				taskquery.Match{Field: "tag", Op: taskquery.OpEq, Value: "backend", At: 13},
+				taskquery.Match{Field: "due", Op: taskquery.OpLt, Value: "2025-01-01", At: 25},
+				taskquery.Text{Value: "release notes", Phrase: true, At: 40},
+			}},
+		},
+		{
+			query: `Status:OPEN AND (tag:"two words" OR -tag:db) NOT done:1`,
+			expected: taskquery.And{Terms: []taskquery.Node{
+				taskquery.Match{Field: "status", Op: taskquery.OpEq, Value: "open", At: 1},
+				taskquery.Or{Terms: []taskquery.Node{
+					taskquery.Match{Field: "tag", Op: taskquery.OpEq, Value: "two words", At: 18},
+					taskquery.Not{Term: taskquery.Match{Field: "tag", Op: taskquery.OpEq, Value: "db", At: 38}, At: 37},
+				}},
+				taskquery.Not{Term: taskquery.Match{Field: "done", Op: taskquery.OpEq, Value: "true", At: 50}, At: 46},
+			}},
+		},
+		{
+			query: `due>=2025-01-01 due<=2025-01-31 re-release or fix`,
+			expected: taskquery.And{Terms: []taskquery.Node{
+				taskquery.Match{Field: "due", Op: taskquery.OpGte, Value: "2025-01-01", At: 1},
+				taskquery.Match{Field: "due", Op: taskquery.OpLte, Value: "2025-01-31", At: 17},
+				taskquery.Text{Value: "re-release", At: 33},
+				taskquery.Text{Value: "or", At: 44},
+				taskquery.Text{Value: "fix", At: 47},
+			}},
+		},
+		{
+			query:    `  ((задача))  `,
+			expected: taskquery.Text{Value: "задача", At: 5},
+		},
+	}
+	for _, tc := range testCases {
+		node, err := taskquery.Parse(tc.query, schema)
+		require.NoError(t, err, tc.query)
+		assert.Equal(t, tc.expected, node, tc.query)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected taskquery.Error
+	}{
+		{query: "   ", expected: taskquery.Error{Pos: 1, Msg: "empty query"}},
+		{query: `tag:a "notes`, expected: taskquery.Error{Pos: 7, Token: `"notes`, Msg: "unterminated quote"}},
+		{query: `tag:a priority:high`, expected: taskquery.Error{Pos: 7, Token: "priority", Msg: "unknown field"}},
+		{query: `status:done`, expected: taskquery.Error{Pos: 8, Token: "done", Msg: "status must be one of open, closed"}},
+		{query: `status<open`, expected: taskquery.Error{Pos: 7, Token: "<", Msg: "operator < is not supported by status"}},
+		{query: `due<2025-13-01`, expected: taskquery.Error{Pos: 5, Token: "2025-13-01", Msg: "due must be a date (YYYY-MM-DD)"}},
+		{query: `done:maybe`, expected: taskquery.Error{Pos: 6, Token: "maybe", Msg: "done must be true or false"}},
+		{query: `tag: backend`, expected: taskquery.Error{Pos: 4, Token: "tag:", Msg: "missing value of tag"}},
+		{query: `tag:""`, expected: taskquery.Error{Pos: 5, Msg: "missing value of tag"}},
+		{query: `a ""`, expected: taskquery.Error{Pos: 3, Token: `""`, Msg: "empty phrase"}},
+		{query: `(a OR b`, expected: taskquery.Error{Pos: 1, Token: "(", Msg: "missing closing parenthesis"}},
+		{query: `a) b`, expected: taskquery.Error{Pos: 2, Token: ")", Msg: "unexpected )"}},
+		{query: `a OR`, expected: taskquery.Error{Pos: 5, Msg: "unexpected end of query"}},
+		{query: `OR a`, expected: taskquery.Error{Pos: 1, Token: "OR", Msg: "unexpected OR"}},
+		{query: `a AND AND b`, expected: taskquery.Error{Pos: 7, Token: "AND", Msg: "unexpected AND"}},
+		{query: `a - b`, expected: taskquery.Error{Pos: 3, Token: "-", Msg: "expected a term right after -"}},
+		{query: `a :b`, expected: taskquery.Error{Pos: 3, Token: ":", Msg: "unexpected :"}},
+		{query: strings.Repeat("a ", taskquery.MaxTerms+1), expected: taskquery.Error{Pos: 2*taskquery.MaxTerms + 1, Msg: "more than 32 terms"}},
+	}
+	for _, tc := range testCases {
+		_, err := taskquery.Parse(tc.query, schema)
+		var queryErr *taskquery.Error
+		require.ErrorAs(t, err, &queryErr, tc.query)
+		assert.Equal(t, tc.expected, *queryErr, tc.query)
+	}
+	assert.EqualError(t, &taskquery.Error{Pos: 3, Token: "x", Msg: "unknown field"}, "unknown field at position 3")
+}
@@ -0,0 +1,123 @@
+package tasktodo
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vlasashk/task-manager/config"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	AttachmentSizeErr = "attachment is too large"
+	AttachmentTypeErr = "attachment type is not allowed"
+	BlobNotFoundErr   = "blob not found"
+)
+
+// Attachment is a file attached to a task. Its content is kept in the blob store under the SHA-256 of the content,
+// so the same file attached many times is stored once.
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	UploaderID  string    `json:"uploader_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BlobStore keeps contents of attachments, keys are hex encoded SHA-256 sums of the contents.
+// Putting a key again replaces the content, deleting a missing key is not an error.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func NewAttachment(taskID, uploaderID, name, contentType string, size int64, sum string) Attachment {
+	return Attachment{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      sum,
+		UploaderID:  uploaderID,
+	}
+}
+
+// AttachmentName keeps the base name of the uploaded file, as sent by Windows clients too, without control characters.
+// The name is cut to 255 bytes, an empty name is returned when nothing is left.
+func AttachmentName(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename))
+	if filename == "." || filename == ".." || filename == "/" {
+		return ""
+	}
+	for len(filename) > 255 {
+		_, size := utf8.DecodeLastRuneInString(filename)
+		filename = filename[:len(filename)-size]
+	}
+	return filename
+}
+
+// AttachmentPolicy limits the size and the media types of attachments.
+type AttachmentPolicy struct {
+	MaxSize int64
+	types   map[string]bool
+}
+
+// DefaultAttachmentPolicy allows images, plain text, PDF and archives up to 10 MiB.
+func DefaultAttachmentPolicy() AttachmentPolicy {
+	policy, err := NewAttachmentPolicy(config.AttachmentCfg{
+		MaxSize: 10 << 20,
+		Types:   []string{"image/png", "image/jpeg", "image/gif", "image/webp", "text/plain", "application/pdf", "application/zip", "application/x-gzip"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+func NewAttachmentPolicy(cfg config.AttachmentCfg) (AttachmentPolicy, error) {
+	if cfg.MaxSize <= 0 {
+		return AttachmentPolicy{}, fmt.Errorf("attachment size limit %d must be positive", cfg.MaxSize)
+	}
+	types := make(map[string]bool, len(cfg.Types))
+	for _, contentType := range cfg.Types {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(contentType))
+		if err != nil {
+			return AttachmentPolicy{}, fmt.Errorf("attachment type %q: %v", contentType, err)
+		}
+		types[mediaType] = true
+	}
+	if len(types) == 0 {
+		return AttachmentPolicy{}, fmt.Errorf("no attachment types allowed")
+	}
+	return AttachmentPolicy{MaxSize: cfg.MaxSize, types: types}, nil
+}
+
+// DetectType sniffs the media type from the head of the content, the type declared by the client is not trusted.
+// It returns the type without parameters and whether the type is allowed.
+func (p AttachmentPolicy) DetectType(head []byte) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream", false
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	return mediaType, p.types[mediaType] || p.types[major+"/*"]
+}
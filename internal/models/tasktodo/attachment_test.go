@@ -0,0 +1,60 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentPolicyDetectType(t *testing.T) {
+	policy, err := tasktodo.NewAttachmentPolicy(config.AttachmentCfg{MaxSize: 1024, Types: []string{"image/*", " text/plain; charset=utf-8"}})
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		head     []byte
+		expected string
+		allowed  bool
+	}{
+		{name: "png by wildcard", head: []byte("\x89PNG\x0D\x0A\x1A\x0A"), expected: "image/png", allowed: true},
+		{name: "log file", head: []byte("2024-10-20 12:00:00 INF started\n"), expected: "text/plain", allowed: true},
+		{name: "html is not text", head: []byte("<html><body>hi</body></html>"), expected: "text/html"},
+		{name: "pdf is not listed", head: []byte("%PDF-1.7"), expected: "application/pdf"},
+		{name: "binary", head: []byte{0, 1, 2, 3}, expected: "application/octet-stream"},
+	}
+	for _, tc := range testCases {
+		contentType, allowed := policy.DetectType(tc.head)
+		assert.Equal(t, tc.expected, contentType, tc.name)
+		assert.Equal(t, tc.allowed, allowed, tc.name)
+	}
+}
+
+func TestNewAttachmentPolicy(t *testing.T) {
+	_, err := tasktodo.NewAttachmentPolicy(config.AttachmentCfg{Types: []string{"text/plain"}})
+	assert.Error(t, err)
+	_, err = tasktodo.NewAttachmentPolicy(config.AttachmentCfg{MaxSize: 1, Types: []string{"text/"}})
+	assert.Error(t, err)
+	_, err = tasktodo.NewAttachmentPolicy(config.AttachmentCfg{MaxSize: 1})
+	assert.Error(t, err)
+	assert.Equal(t, int64(10<<20), tasktodo.DefaultAttachmentPolicy().MaxSize)
+}
+
+func TestAttachmentName(t *testing.T) {
+	testCases := []struct {
+		filename string
+		expected string
+	}{
+		{filename: "screenshot.png", expected: "screenshot.png"},
+		{filename: "../../etc/passwd", expected: "passwd"},
+		{filename: `C:\Users\me\app.log`, expected: "app.log"},
+		{filename: " report\x00\n.pdf ", expected: "report.pdf"},
+		{filename: "..", expected: ""},
+		{filename: "", expected: ""},
+		{filename: strings.Repeat("я", 200), expected: strings.Repeat("я", 127)},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tasktodo.AttachmentName(tc.filename), tc.filename)
+	}
+}
@@ -0,0 +1,67 @@
+package tasktodo
+
+import "fmt"
+
+// BulkAction is the kind of an operation of a bulk request.
+type BulkAction string
+
+const (
+	BulkCreate   BulkAction = "create"
+	BulkUpdate   BulkAction = "update"
+	BulkDelete   BulkAction = "delete"
+	BulkComplete BulkAction = "complete"
+)
+
+// DefaultBulkLimit is the number of operations a bulk request may hold unless configured otherwise.
+const DefaultBulkLimit = 100
+
+const (
+	BulkLimitErr     = "too many operations"
+	BulkDuplicateErr = "task changed more than once"
+)
+
+// BulkRequest is a batch of task operations applied in a single transaction. An atomic batch is applied
+// as a whole or not at all, otherwise every operation succeeds or fails on its own.
+type BulkRequest struct {
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations" validate:"required,min=1"`
+}
+
+// BulkOperation creates a task, replaces the task with the ID the way PUT does, deletes or completes it.
+// Version is the expected version of the task, as in If-Match, 0 means any.
+type BulkOperation struct {
+	Action  BulkAction `json:"action" validate:"oneof=create update delete complete"`
+	ID      string     `json:"id,omitempty" validate:"required_unless=Action create,excluded_if=Action create"`
+	Version int64      `json:"version,omitempty" validate:"min=0"`
+	Task    *Request   `json:"task,omitempty" validate:"required_if=Action create,required_if=Action update,excluded_if=Action delete,excluded_if=Action complete"`
+}
+
+// BulkOp is an operation of a batch checked by the service and ready to be applied.
+// Task is used to create and update tasks, Patch to complete them.
+type BulkOp struct {
+	Action  BulkAction
+	TaskID  string
+	Version int64
+	Task    Request
+	Patch   Patch
+}
+
+// BulkOutcome is the result of an applied operation, Task is left empty for deleted tasks.
+type BulkOutcome struct {
+	Task Task
+	Err  error
+}
+
+// OperationError tells which operation of an atomic batch failed, nothing of the batch is applied then.
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e OperationError) Unwrap() error {
+	return e.Err
+}
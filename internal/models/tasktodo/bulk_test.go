@@ -0,0 +1,41 @@
+package tasktodo_test
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestBulkOperationRules(t *testing.T) {
+	task := &tasktodo.Request{Title: "test", Description: "test", DueDate: "2024-10-26", State: tasktodo.StateTodo}
+	testCases := []struct {
+		name  string
+		op    tasktodo.BulkOperation
+		valid bool
+	}{
+		{name: "create", op: tasktodo.BulkOperation{Action: tasktodo.BulkCreate, Task: task}, valid: true},
+		{name: "create with id", op: tasktodo.BulkOperation{Action: tasktodo.BulkCreate, ID: "a", Task: task}},
+		{name: "create without task", op: tasktodo.BulkOperation{Action: tasktodo.BulkCreate}},
+		{name: "update", op: tasktodo.BulkOperation{Action: tasktodo.BulkUpdate, ID: "a", Version: 2, Task: task}, valid: true},
+		{name: "update without id", op: tasktodo.BulkOperation{Action: tasktodo.BulkUpdate, Task: task}},
+		{name: "update without task", op: tasktodo.BulkOperation{Action: tasktodo.BulkUpdate, ID: "a"}},
+		{name: "delete", op: tasktodo.BulkOperation{Action: tasktodo.BulkDelete, ID: "a"}, valid: true},
+		{name: "delete with task", op: tasktodo.BulkOperation{Action: tasktodo.BulkDelete, ID: "a", Task: task}},
+		{name: "complete", op: tasktodo.BulkOperation{Action: tasktodo.BulkComplete, ID: "a"}, valid: true},
+		{name: "negative version", op: tasktodo.BulkOperation{Action: tasktodo.BulkComplete, ID: "a", Version: -1}},
+		{name: "unknown action", op: tasktodo.BulkOperation{Action: "archive", ID: "a"}},
+	}
+	for _, tc := range testCases {
+		err := validator.New().Struct(tc.op)
+		assert.Equal(t, tc.valid, err == nil, tc.name)
+	}
+}
+
+func TestOperationError(t *testing.T) {
+	cause := errors.New("version mismatch")
+	var err error = tasktodo.OperationError{Index: 2, Err: cause}
+	assert.EqualError(t, err, "operation 2: version mismatch")
+	assert.ErrorIs(t, err, cause)
+}
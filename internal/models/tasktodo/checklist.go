@@ -0,0 +1,66 @@
+package tasktodo
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ChecklistItem is a step of a task too small to be a subtask.
+type ChecklistItem struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChecklistItemRequest struct {
+	Text string `json:"text" validate:"required,max=500"`
+}
+
+// ChecklistOrder lists every item of the checklist once in the new order.
+type ChecklistOrder struct {
+	IDs []string `json:"ids" validate:"required,unique,dive,required"`
+}
+
+// ChecklistToggle checks or unchecks an item. With Complete set the task is completed too
+// once every item of its checklist is checked, if the workflow and the blockers of the task allow it.
+type ChecklistToggle struct {
+	Done     *bool `json:"done" validate:"required"`
+	Complete bool  `json:"complete,omitempty"`
+}
+
+// Checklist holds the items of a task in their order, Progress is the number of checked items
+// out of all of them like "3/5".
+type Checklist struct {
+	Items    []ChecklistItem `json:"items"`
+	Done     int             `json:"done"`
+	Total    int             `json:"total"`
+	Progress string          `json:"progress"`
+}
+
+func NewChecklistItem(req ChecklistItemRequest) ChecklistItem {
+	return ChecklistItem{
+		ID:   uuid.New().String(),
+		Text: req.Text,
+	}
+}
+
+func NewChecklist(items []ChecklistItem) Checklist {
+	checklist := Checklist{Items: items, Total: len(items)}
+	if checklist.Items == nil {
+		checklist.Items = make([]ChecklistItem, 0)
+	}
+	for _, item := range items {
+		if item.Done {
+			checklist.Done++
+		}
+	}
+	checklist.Progress = fmt.Sprintf("%d/%d", checklist.Done, checklist.Total)
+	return checklist
+}
+
+// Checked reports whether the checklist has items and all of them are checked.
+func (c Checklist) Checked() bool {
+	return c.Total != 0 && c.Done == c.Total
+}
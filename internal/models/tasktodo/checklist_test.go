@@ -0,0 +1,26 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestNewChecklist(t *testing.T) {
+	testCases := []struct {
+		name     string
+		items    []tasktodo.ChecklistItem
+		progress string
+		checked  bool
+	}{
+		{name: "empty", progress: "0/0"},
+		{name: "partly checked", items: []tasktodo.ChecklistItem{{Done: true}, {}, {Done: true}}, progress: "2/3"},
+		{name: "all checked", items: []tasktodo.ChecklistItem{{Done: true}, {Done: true}}, progress: "2/2", checked: true},
+	}
+	for _, tc := range testCases {
+		checklist := tasktodo.NewChecklist(tc.items)
+		assert.NotNil(t, checklist.Items, tc.name)
+		assert.Equal(t, tc.progress, checklist.Progress, tc.name)
+		assert.Equal(t, tc.checked, checklist.Checked(), tc.name)
+	}
+}
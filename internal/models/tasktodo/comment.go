@@ -0,0 +1,47 @@
+package tasktodo
+
+import (
+	"bytes"
+	"github.com/google/uuid"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"time"
+)
+
+// markdown renders GitHub Flavored Markdown. Raw HTML and links with unsafe schemes like javascript:
+// are left out of the output, so it can be embedded into pages as is.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// Comment is a message in the discussion of a task.
+type Comment struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"task_id"`
+	AuthorID string `json:"author_id"`
+	// Body is the Markdown source of the comment, HTML is its rendering.
+	Body      string     `json:"body"`
+	HTML      string     `json:"html"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+func NewComment(req CommentRequest, taskID, authorID string) Comment {
+	return Comment{
+		ID:       uuid.New().String(),
+		TaskID:   taskID,
+		AuthorID: authorID,
+		Body:     req.Body,
+		HTML:     RenderMarkdown(req.Body),
+	}
+}
+
+// RenderMarkdown converts the Markdown source into safe HTML.
+func RenderMarkdown(source string) string {
+	var buf bytes.Buffer
+	// writing into a buffer does not fail
+	_ = markdown.Convert([]byte(source), &buf)
+	return buf.String()
+}
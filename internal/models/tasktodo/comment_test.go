@@ -0,0 +1,33 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "emphasis", source: "**done** in `main`", expected: "<p><strong>done</strong> in <code>main</code></p>\n"},
+		{name: "strikethrough", source: "~~later~~", expected: "<p><del>later</del></p>\n"},
+		{name: "link", source: "[spec](https://example.com)", expected: "<p><a href=\"https://example.com\">spec</a></p>\n"},
+		{name: "raw html is dropped", source: "<script>alert(1)</script>", expected: "<!-- raw HTML omitted -->\n"},
+		{name: "unsafe link is dropped", source: "[x](javascript:alert(1))", expected: "<p><a href=\"\">x</a></p>\n"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tasktodo.RenderMarkdown(tc.source), tc.name)
+	}
+}
+
+func TestNewComment(t *testing.T) {
+	comment := tasktodo.NewComment(tasktodo.CommentRequest{Body: "*hi*"}, "task", "user")
+	assert.NotEmpty(t, comment.ID)
+	assert.Equal(t, "task", comment.TaskID)
+	assert.Equal(t, "user", comment.AuthorID)
+	assert.Equal(t, "*hi*", comment.Body)
+	assert.Equal(t, "<p><em>hi</em></p>\n", comment.HTML)
+}
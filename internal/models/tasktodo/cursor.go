@@ -0,0 +1,121 @@
+package tasktodo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vlasashk/task-manager/config"
+	"strings"
+	"time"
+)
+
+const CursorErr = "invalid cursor"
+
+// Cursor points at the last task of a page, the next page starts right after it in (priority, due_date, id) order.
+type Cursor struct {
+	Priority Priority `json:"p"`
+	DueDate  string   `json:"d"`
+	ID       string   `json:"i"`
+}
+
+// CommentCursor points at the last comment of a page, the next page starts right after it in (created_at, id) order.
+type CommentCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// Paginator limits page sizes and signs cursors, so clients can not forge them.
+type Paginator struct {
+	key          []byte
+	DefaultLimit uint
+	MaxLimit     uint
+}
+
+// DefaultPaginator serves pages of 10 tasks and up to 100 on demand, cursors are signed with a random key.
+func DefaultPaginator() Paginator {
+	pages, err := NewPaginator(config.PaginationCfg{DefaultLimit: 10, MaxLimit: 100})
+	if err != nil {
+		panic(err)
+	}
+	return pages
+}
+
+func NewPaginator(cfg config.PaginationCfg) (Paginator, error) {
+	if cfg.DefaultLimit == 0 || cfg.DefaultLimit > cfg.MaxLimit {
+		return Paginator{}, fmt.Errorf("default page limit %d must be between 1 and %d", cfg.DefaultLimit, cfg.MaxLimit)
+	}
+	key := []byte(cfg.CursorSecret)
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		if _, err := rand.Read(key); err != nil {
+			return Paginator{}, fmt.Errorf("generating cursor key fail: %v", err)
+		}
+	}
+	return Paginator{key: key, DefaultLimit: cfg.DefaultLimit, MaxLimit: cfg.MaxLimit}, nil
+}
+
+// Encode returns an opaque token of the cursor: its base64 encoded JSON and signature separated by a dot.
+func (p Paginator) Encode(cursor Cursor) string {
+	return p.encode(cursor)
+}
+
+// Decode checks the signature of the token and returns the cursor it carries.
+// Cursors issued before tasks were ordered by priority have none and are rejected.
+func (p Paginator) Decode(token string) (Cursor, error) {
+	var cursor Cursor
+	if err := p.decode(token, &cursor); err != nil || !cursor.Priority.Valid() || cursor.DueDate == "" || cursor.ID == "" {
+		return Cursor{}, errors.New(CursorErr)
+	}
+	return cursor, nil
+}
+
+// NewCursor points at the task.
+func NewCursor(task Task) Cursor {
+	return Cursor{Priority: task.Priority, DueDate: task.DueDate, ID: task.ID}
+}
+
+// EncodeComment returns an opaque token of the comment cursor, see Encode.
+func (p Paginator) EncodeComment(cursor CommentCursor) string {
+	return p.encode(cursor)
+}
+
+// DecodeComment checks the signature of the token and returns the comment cursor it carries.
+func (p Paginator) DecodeComment(token string) (CommentCursor, error) {
+	var cursor CommentCursor
+	if err := p.decode(token, &cursor); err != nil || cursor.CreatedAt.IsZero() || cursor.ID == "" {
+		return CommentCursor{}, errors.New(CursorErr)
+	}
+	return cursor, nil
+}
+
+func (p Paginator) encode(cursor any) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(p.sign(encoded))
+}
+
+func (p Paginator) decode(token string, cursor any) error {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return errors.New(CursorErr)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, p.sign(encoded)) {
+		return errors.New(CursorErr)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errors.New(CursorErr)
+	}
+	return json.Unmarshal(payload, cursor)
+}
+
+func (p Paginator) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
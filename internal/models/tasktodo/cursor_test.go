@@ -0,0 +1,67 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaginatorCursor(t *testing.T) {
+	pages, err := tasktodo.NewPaginator(config.PaginationCfg{CursorSecret: "secret", DefaultLimit: 10, MaxLimit: 100})
+	require.NoError(t, err)
+	cursor := tasktodo.Cursor{Priority: tasktodo.PriorityP2, DueDate: "2024-10-26", ID: "test"}
+
+	token := pages.Encode(cursor)
+	decoded, err := pages.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := pages.Encode(tasktodo.Cursor{Priority: tasktodo.PriorityP2, DueDate: "2024-10-27", ID: "test"})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	other, err := tasktodo.NewPaginator(config.PaginationCfg{CursorSecret: "other", DefaultLimit: 10, MaxLimit: 100})
+	require.NoError(t, err)
+	// cursors issued before the listing was ordered by priority
+	legacy := pages.Encode(tasktodo.Cursor{DueDate: "2024-10-26", ID: "test"})
+	for _, bad := range []string{"", "test", payload, payload + ".", forgedPayload + "." + signature, other.Encode(cursor), token + "x", legacy} {
+		_, err = pages.Decode(bad)
+		assert.EqualError(t, err, tasktodo.CursorErr, bad)
+	}
+}
+
+func TestPaginatorCommentCursor(t *testing.T) {
+	pages, err := tasktodo.NewPaginator(config.PaginationCfg{CursorSecret: "secret", DefaultLimit: 10, MaxLimit: 100})
+	require.NoError(t, err)
+	cursor := tasktodo.CommentCursor{CreatedAt: time.Date(2024, 10, 26, 12, 30, 0, 123456000, time.UTC), ID: "test"}
+
+	decoded, err := pages.DecodeComment(pages.EncodeComment(cursor))
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	// cursors of tasks and comments are not interchangeable
+	_, err = pages.DecodeComment(pages.Encode(tasktodo.Cursor{Priority: tasktodo.PriorityP2, DueDate: "2024-10-26", ID: "test"}))
+	assert.EqualError(t, err, tasktodo.CursorErr)
+	_, err = pages.Decode(pages.EncodeComment(cursor))
+	assert.EqualError(t, err, tasktodo.CursorErr)
+}
+
+func TestNewPaginator(t *testing.T) {
+	for _, cfg := range []config.PaginationCfg{
+		{DefaultLimit: 0, MaxLimit: 100},
+		{DefaultLimit: 101, MaxLimit: 100},
+	} {
+		_, err := tasktodo.NewPaginator(cfg)
+		assert.Error(t, err)
+	}
+	// cursors signed with a random key are still accepted by the same paginator
+	pages := tasktodo.DefaultPaginator()
+	cursor := tasktodo.Cursor{Priority: tasktodo.PriorityP2, DueDate: "2024-10-26", ID: "test"}
+	decoded, err := pages.Decode(pages.Encode(cursor))
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
@@ -0,0 +1,6 @@
+package tasktodo
+
+// Dependency says that a task cannot be done until the blocker is done or cancelled.
+type Dependency struct {
+	BlockerID string `json:"blocker_id" validate:"required"`
+}
@@ -0,0 +1,114 @@
+package tasktodo
+
+import (
+	"errors"
+	"github.com/vlasashk/task-manager/config"
+	"strings"
+	"time"
+)
+
+// dueTimeLayout is the layout of due times, they are kept to the minute.
+const dueTimeLayout = "15:04"
+
+const (
+	DueDatePastErr = "due date is in the past"
+	ImportErr      = "only admins may import tasks"
+)
+
+// Deadline returns the moment the task is due, its due time on the due date in the time zone of the task,
+// UTC if it has none. A task due on a date without a time has no deadline.
+func (r Request) Deadline() (time.Time, bool) {
+	if r.DueTime == "" {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	at, err := time.ParseInLocation(dateLayout+" "+dueTimeLayout, r.DueDate+" "+r.DueTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// SplitDue turns a due date given as an RFC 3339 time into the date and the time of day in the time zone
+// of the task. A due date given as a date is left as it is.
+func (r *Request) SplitDue() {
+	if date, clock, ok := splitDue(r.DueDate, r.TimeZone); ok {
+		r.DueDate, r.DueTime = date, clock
+	}
+}
+
+// SplitDue turns a due date given as an RFC 3339 time into the date and the time of day. The time zone
+// set by the patch is used if there is one, otherwise zone, the time zone of the patched task.
+func (p *Patch) SplitDue(zone string) {
+	if p.DueDate == nil {
+		return
+	}
+	if p.TimeZone != nil {
+		zone = *p.TimeZone
+	}
+	if date, clock, ok := splitDue(*p.DueDate, zone); ok {
+		p.DueDate, p.DueTime = &date, &clock
+	}
+}
+
+func splitDue(due, zone string) (string, string, bool) {
+	at, err := time.Parse(time.RFC3339, due)
+	if err != nil {
+		return "", "", false
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return "", "", false
+	}
+	at = at.In(loc)
+	return at.Format(dateLayout), at.Format(dueTimeLayout), true
+}
+
+// DueDatePolicy keeps new due dates from being too far in the past. It applies to new tasks and to changed
+// due dates only, so overdue tasks can still be edited and completed.
+type DueDatePolicy struct {
+	// PastDays is how many days before today a due date may be, a negative value allows any date.
+	PastDays int
+	admins   map[string]bool
+}
+
+// DefaultDueDatePolicy allows due dates from today on and has no admins.
+func DefaultDueDatePolicy() DueDatePolicy {
+	return NewDueDatePolicy(config.DueDateCfg{})
+}
+
+func NewDueDatePolicy(cfg config.DueDateCfg) DueDatePolicy {
+	admins := make(map[string]bool, len(cfg.Admins))
+	for _, userID := range cfg.Admins {
+		admins[strings.TrimSpace(userID)] = true
+	}
+	return DueDatePolicy{PastDays: cfg.PastDays, admins: admins}
+}
+
+// Check rejects the due date, a date or an RFC 3339 time, when it is too far before today in the time zone.
+// current is the due date of the changed task and passes as it is, it is empty for a new task.
+func (p DueDatePolicy) Check(due, zone, current string, now time.Time) error {
+	if date, _, ok := splitDue(due, zone); ok {
+		due = date
+	}
+	if p.PastDays < 0 || due == current {
+		return nil
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		loc = time.UTC
+	}
+	// dates in the same layout compare as strings
+	if due < now.In(loc).AddDate(0, 0, -p.PastDays).Format(dateLayout) {
+		return errors.New(DueDatePastErr)
+	}
+	return nil
+}
+
+// CanImport reports whether the user may import tasks regardless of their due dates.
+func (p DueDatePolicy) CanImport(userID string) bool {
+	return p.admins[userID]
+}
@@ -0,0 +1,114 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+	"time"
+)
+
+func TestRequestSplitDue(t *testing.T) {
+	testCases := []struct {
+		name  string
+		req   tasktodo.Request
+		date  string
+		clock string
+	}{
+		{
+			name:  "date only",
+			req:   tasktodo.Request{DueDate: "2024-10-26", DueTime: "09:15"},
+			date:  "2024-10-26",
+			clock: "09:15",
+		},
+		{
+			name:  "utc without time zone",
+			req:   tasktodo.Request{DueDate: "2024-10-26T18:30:45+03:00"},
+			date:  "2024-10-26",
+			clock: "15:30",
+		},
+		{
+			name:  "next day in time zone",
+			req:   tasktodo.Request{DueDate: "2024-10-26T18:30:00Z", DueTime: "09:15", TimeZone: "Asia/Tokyo"},
+			date:  "2024-10-27",
+			clock: "03:30",
+		},
+	}
+	for _, tc := range testCases {
+		tc.req.SplitDue()
+		assert.Equal(t, tc.date, tc.req.DueDate, tc.name)
+		assert.Equal(t, tc.clock, tc.req.DueTime, tc.name)
+	}
+}
+
+func TestPatchSplitDue(t *testing.T) {
+	due, zone := "2024-10-26T18:30:00Z", "Europe/Moscow"
+	patch := tasktodo.Patch{DueDate: &due}
+	patch.SplitDue(zone)
+	require.NotNil(t, patch.DueTime)
+	assert.Equal(t, "2024-10-26", *patch.DueDate)
+	assert.Equal(t, "21:30", *patch.DueTime)
+
+	due, utc := "2024-10-26T18:30:00Z", ""
+	patch = tasktodo.Patch{DueDate: &due, TimeZone: &utc}
+	patch.SplitDue(zone)
+	assert.Equal(t, "18:30", *patch.DueTime)
+
+	date := "2024-10-26"
+	patch = tasktodo.Patch{DueDate: &date}
+	patch.SplitDue(zone)
+	assert.Nil(t, patch.DueTime)
+}
+
+func TestDeadline(t *testing.T) {
+	_, ok := tasktodo.Request{DueDate: "2024-10-26"}.Deadline()
+	assert.False(t, ok)
+
+	at, ok := tasktodo.Request{DueDate: "2024-10-26", DueTime: "18:30", TimeZone: "Europe/Moscow"}.Deadline()
+	require.True(t, ok)
+	assert.True(t, at.Equal(time.Date(2024, 10, 26, 15, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-10-26T18:30:00+03:00", at.Format(time.RFC3339))
+
+	at, ok = tasktodo.Request{DueDate: "2024-10-26", DueTime: "18:30"}.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, "2024-10-26T18:30:00Z", at.Format(time.RFC3339))
+}
+
+func TestDueDatePolicy(t *testing.T) {
+	now := time.Date(2024, 10, 20, 22, 0, 0, 0, time.UTC)
+	policy := tasktodo.NewDueDatePolicy(config.DueDateCfg{PastDays: 1, Admins: []string{"alice", " bob"}})
+	testCases := []struct {
+		name    string
+		due     string
+		zone    string
+		current string
+		fail    bool
+	}{
+		{name: "today", due: "2024-10-20"},
+		{name: "within past days", due: "2024-10-19"},
+		{name: "too old", due: "2024-10-18", fail: true},
+		{name: "unchanged", due: "2024-10-01", current: "2024-10-01"},
+		{name: "changed", due: "2024-10-02", current: "2024-10-01", fail: true},
+		{name: "today is later in the time zone", due: "2024-10-19", zone: "Asia/Tokyo", fail: true},
+		{name: "rfc 3339 in the time zone", due: "2024-10-19T15:30:00Z", zone: "Asia/Tokyo"},
+		{name: "rfc 3339 too old", due: "2024-10-18T14:30:00Z", fail: true},
+	}
+	for _, tc := range testCases {
+		err := policy.Check(tc.due, tc.zone, tc.current, now)
+		if tc.fail {
+			assert.EqualError(t, err, tasktodo.DueDatePastErr, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+
+	anyDate := tasktodo.NewDueDatePolicy(config.DueDateCfg{PastDays: -1})
+	assert.NoError(t, anyDate.Check("2000-01-01", "", "", now))
+	assert.EqualError(t, tasktodo.DefaultDueDatePolicy().Check("2024-10-19", "", "", now), tasktodo.DueDatePastErr)
+
+	assert.True(t, policy.CanImport("alice"))
+	assert.True(t, policy.CanImport("bob"))
+	assert.False(t, policy.CanImport("carol"))
+	assert.False(t, anyDate.CanImport(""))
+}
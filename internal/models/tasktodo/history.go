@@ -0,0 +1,104 @@
+package tasktodo
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Action is the kind of change recorded in the history of a task.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+	ActionRevert  Action = "revert"
+	// ActionPurge removes a deleted task for good, its subtasks go with it.
+	ActionPurge Action = "purge"
+)
+
+const HistoryVersionErr = "version is not in the task history"
+
+// Event is a change of a task recorded in its history.
+type Event struct {
+	ID     int64  `json:"id"`
+	TaskID string `json:"task_id"`
+	Action Action `json:"action"`
+	// Version is the version of the task the change resulted in.
+	Version int64 `json:"version"`
+	// Actor is the user who made the change, APIKeyID is set when it was made with an API key.
+	Actor     string `json:"actor"`
+	APIKeyID  string `json:"api_key_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Before and After hold the changed fields only. A created task has all of its fields in After,
+	// deletion, restoration and purging change no fields.
+	Before    map[string]json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After     map[string]json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+// RevertRequest asks to bring a task back to a version from its history.
+type RevertRequest struct {
+	Version int64 `json:"version" validate:"required,min=1"`
+}
+
+// Fields returns the task fields as they are recorded in the history.
+func Fields(task Request) (map[string]json.RawMessage, error) {
+	if task.Tags == nil {
+		task.Tags = []string{}
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// Diff returns the fields which differ between the two states of a task, as they were before and after the change.
+// A field missing on one side, like the parent of a top-level task, is null there.
+func Diff(before, after Request) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	from, err := Fields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := Fields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	null := json.RawMessage("null")
+	for field, val := range from {
+		if _, ok := to[field]; !ok {
+			to[field] = null
+		}
+		if bytes.Equal(val, to[field]) {
+			delete(from, field)
+			delete(to, field)
+		}
+	}
+	for field := range to {
+		if _, ok := from[field]; !ok {
+			from[field] = null
+		}
+	}
+	return from, to, nil
+}
+
+// Reverted turns a task state recorded in the history into an update restoring it. The recurrence is shared
+// by the whole series and is kept as it is, a missing parent or project is set explicitly.
+func Reverted(snapshot Request) Request {
+	snapshot.Recurrence = nil
+	if snapshot.Tags == nil {
+		snapshot.Tags = []string{}
+	}
+	if snapshot.ParentID == nil {
+		snapshot.ParentID = new(string)
+	}
+	if snapshot.ProjectID == nil {
+		snapshot.ProjectID = new(string)
+	}
+	return snapshot
+}
@@ -0,0 +1,51 @@
+package tasktodo_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	open, parent, rule := false, "parent", "FREQ=DAILY"
+	before := tasktodo.Request{Title: "old", Description: "same", DueDate: "2024-10-26", Status: &open,
+		State: tasktodo.StateTodo, Tags: []string{"a"}, ParentID: &parent}
+	after := tasktodo.Request{Title: "new", Description: "same", DueDate: "2024-10-26", Status: &open,
+		State: tasktodo.StateTodo, Tags: []string{"a", "b"}, Recurrence: &rule}
+
+	from, to, err := tasktodo.Diff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"title":      json.RawMessage(`"old"`),
+		"tags":       json.RawMessage(`["a"]`),
+		"parent_id":  json.RawMessage(`"parent"`),
+		"recurrence": json.RawMessage(`null`),
+	}, from)
+	assert.Equal(t, map[string]json.RawMessage{
+		"title":      json.RawMessage(`"new"`),
+		"tags":       json.RawMessage(`["a","b"]`),
+		"parent_id":  json.RawMessage(`null`),
+		"recurrence": json.RawMessage(`"FREQ=DAILY"`),
+	}, to)
+
+	// missing tags are recorded as an empty list, not as a change
+	after.Tags, before.Tags = nil, []string{}
+	after.Title, after.ParentID, after.Recurrence = "old", &parent, nil
+	from, to, err = tasktodo.Diff(before, after)
+	require.NoError(t, err)
+	assert.Empty(t, from)
+	assert.Empty(t, to)
+}
+
+func TestReverted(t *testing.T) {
+	rule := "FREQ=DAILY"
+	reverted := tasktodo.Reverted(tasktodo.Request{Title: "old", Recurrence: &rule})
+	assert.Nil(t, reverted.Recurrence)
+	assert.Equal(t, []string{}, reverted.Tags)
+	require.NotNil(t, reverted.ParentID)
+	assert.Empty(t, *reverted.ParentID)
+	require.NotNil(t, reverted.ProjectID)
+	assert.Empty(t, *reverted.ProjectID)
+}
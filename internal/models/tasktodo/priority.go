@@ -0,0 +1,109 @@
+package tasktodo
+
+import (
+	"errors"
+	"strings"
+)
+
+// Priority tells how urgent a task is, from P0 the most urgent to P4. The set of priorities is fixed
+// by the task_priority enum in the database, which orders them the same way.
+type Priority string
+
+const (
+	PriorityP0 Priority = "P0"
+	PriorityP1 Priority = "P1"
+	PriorityP2 Priority = "P2"
+	PriorityP3 Priority = "P3"
+	PriorityP4 Priority = "P4"
+)
+
+const PriorityErr = "unknown priority"
+
+// Priorities lists every priority, the most urgent first.
+var Priorities = []Priority{PriorityP0, PriorityP1, PriorityP2, PriorityP3, PriorityP4}
+
+// DefaultPriority is given to tasks created without a priority.
+const DefaultPriority = PriorityP2
+
+// ImportantPriority is the lowest priority the Eisenhower matrix counts as important.
+const ImportantPriority = PriorityP1
+
+// DefaultUrgentDays is how many days ahead a task is due to be urgent in the Eisenhower matrix.
+const DefaultUrgentDays = 2
+
+// ParsePriorities reads priorities given as a comma separated list like "P0,P1", lower case is accepted too.
+// Repeated priorities are kept once.
+func ParsePriorities(list string) ([]Priority, error) {
+	var priorities []Priority
+	seen := make(map[Priority]bool, len(Priorities))
+	for _, name := range strings.Split(list, ",") {
+		priority := Priority(strings.ToUpper(strings.TrimSpace(name)))
+		if !priority.Valid() {
+			return nil, errors.New(PriorityErr)
+		}
+		if !seen[priority] {
+			seen[priority] = true
+			priorities = append(priorities, priority)
+		}
+	}
+	return priorities, nil
+}
+
+func (p Priority) Valid() bool {
+	for _, known := range Priorities {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MatrixParams narrows the Eisenhower matrix. Tasks due within UrgentDays days or overdue are urgent,
+// Limit caps the number of tasks in each quadrant.
+type MatrixParams struct {
+	ProjectID  string
+	UrgentDays int
+	Limit      uint
+}
+
+// Quadrant holds the most urgent tasks of a quadrant of the Eisenhower matrix, Total counts all of them.
+type Quadrant struct {
+	Tasks []Task `json:"tasks"`
+	Total int    `json:"total"`
+}
+
+// Matrix buckets open tasks by importance, which comes from the priority, and urgency, which comes
+// from the due date. Tasks of each quadrant are ordered by priority and then by due date.
+type Matrix struct {
+	// Do holds urgent and important tasks.
+	Do Quadrant `json:"do"`
+	// Schedule holds important tasks which are not urgent.
+	Schedule Quadrant `json:"schedule"`
+	// Delegate holds urgent tasks which are not important.
+	Delegate Quadrant `json:"delegate"`
+	// Eliminate holds the rest.
+	Eliminate Quadrant `json:"eliminate"`
+}
+
+func NewMatrix() Matrix {
+	return Matrix{
+		Do:        Quadrant{Tasks: make([]Task, 0)},
+		Schedule:  Quadrant{Tasks: make([]Task, 0)},
+		Delegate:  Quadrant{Tasks: make([]Task, 0)},
+		Eliminate: Quadrant{Tasks: make([]Task, 0)},
+	}
+}
+
+// Quadrant returns the quadrant of tasks of the importance and urgency.
+func (m *Matrix) Quadrant(important, urgent bool) *Quadrant {
+	switch {
+	case important && urgent:
+		return &m.Do
+	case important:
+		return &m.Schedule
+	case urgent:
+		return &m.Delegate
+	default:
+		return &m.Eliminate
+	}
+}
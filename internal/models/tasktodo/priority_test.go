@@ -0,0 +1,39 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestParsePriorities(t *testing.T) {
+	testCases := []struct {
+		list     string
+		expected []tasktodo.Priority
+		fail     bool
+	}{
+		{list: "P0", expected: []tasktodo.Priority{tasktodo.PriorityP0}},
+		{list: " p3 ,P1,P3", expected: []tasktodo.Priority{tasktodo.PriorityP3, tasktodo.PriorityP1}},
+		{list: "P5", fail: true},
+		{list: "high", fail: true},
+		{list: "P1,", fail: true},
+		{list: "", fail: true},
+	}
+	for _, tc := range testCases {
+		priorities, err := tasktodo.ParsePriorities(tc.list)
+		if tc.fail {
+			assert.EqualError(t, err, tasktodo.PriorityErr, tc.list)
+			continue
+		}
+		assert.NoError(t, err, tc.list)
+		assert.Equal(t, tc.expected, priorities, tc.list)
+	}
+}
+
+func TestMatrixQuadrant(t *testing.T) {
+	matrix := tasktodo.NewMatrix()
+	assert.Same(t, &matrix.Do, matrix.Quadrant(true, true))
+	assert.Same(t, &matrix.Schedule, matrix.Quadrant(true, false))
+	assert.Same(t, &matrix.Delegate, matrix.Quadrant(false, true))
+	assert.Same(t, &matrix.Eliminate, matrix.Quadrant(false, false))
+}
@@ -0,0 +1,43 @@
+package tasktodo
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// NoProject filters tasks which do not belong to any project.
+const NoProject = "none"
+
+// Project groups tasks of a user, a task belongs to at most one project.
+type Project struct {
+	ID string `json:"id"`
+	ProjectRequest
+	// ArchivedAt is set for archived projects, their tasks are left out of listings unless the project is asked for.
+	ArchivedAt *time.Time    `json:"archived_at,omitempty"`
+	Counts     ProjectCounts `json:"counts"`
+}
+
+type ProjectRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=4096"`
+}
+
+// ProjectCounts sums up live tasks of a project by their workflow state.
+type ProjectCounts struct {
+	Total     int `json:"total"`
+	Open      int `json:"open"`
+	Done      int `json:"done"`
+	Cancelled int `json:"cancelled"`
+}
+
+// MoveRequest lists tasks to be moved into a project together.
+type MoveRequest struct {
+	TaskIDs []string `json:"task_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+func NewProject(req ProjectRequest) Project {
+	return Project{
+		ID:             uuid.New().String(),
+		ProjectRequest: req,
+	}
+}
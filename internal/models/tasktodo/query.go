@@ -0,0 +1,53 @@
+package tasktodo
+
+import (
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
+	"strings"
+)
+
+// QueryLimit caps the length of a task query.
+const QueryLimit = 512
+
+// Status values of the task query, open tasks are neither done nor cancelled.
+const (
+	QueryStatusOpen   = "open"
+	QueryStatusClosed = "closed"
+)
+
+// queryFields is the vocabulary of the task query language.
+var queryFields = taskquery.Schema{
+	"status":   {Kind: taskquery.KindEnum, Values: []string{QueryStatusOpen, QueryStatusClosed}},
+	"state":    {Kind: taskquery.KindEnum, Values: stateNames()},
+	"priority": {Kind: taskquery.KindEnum, Values: priorityNames()},
+	"tag":      {Kind: taskquery.KindText},
+	"project":  {Kind: taskquery.KindText},
+	"title":    {Kind: taskquery.KindText},
+	"due":      {Kind: taskquery.KindDate},
+	"created":  {Kind: taskquery.KindDate},
+	"blocked":  {Kind: taskquery.KindBool},
+	"overdue":  {Kind: taskquery.KindBool},
+}
+
+// ParseQuery reads a task query like `status:open tag:backend due<2025-01-01 "release notes"`.
+// Words and phrases are looked up in titles and descriptions the way the full-text search does,
+// title: matches a part of the title, project:none matches tasks outside of any project.
+func ParseQuery(query string) (taskquery.Node, error) {
+	return taskquery.Parse(query, queryFields)
+}
+
+// priorityNames are lower case, as values of enum fields are matched in lower case.
+func priorityNames() []string {
+	names := make([]string, 0, len(Priorities))
+	for _, priority := range Priorities {
+		names = append(names, strings.ToLower(string(priority)))
+	}
+	return names
+}
+
+func stateNames() []string {
+	names := make([]string, 0, len(States))
+	for _, state := range States {
+		names = append(names, string(state))
+	}
+	return names
+}
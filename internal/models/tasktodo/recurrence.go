@@ -0,0 +1,138 @@
+package tasktodo
+
+import (
+	"errors"
+	"github.com/teambition/rrule-go"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceErr   = "bad recurrence rule"
+	NotRecurringErr = "task is not recurring"
+)
+
+const dateLayout = "2006-01-02"
+
+// Scope tells whether a change is applied to a single occurrence or to the whole series.
+type Scope string
+
+const (
+	ScopeOccurrence Scope = "occurrence"
+	ScopeSeries     Scope = "series"
+)
+
+// Series is the template occurrences of a recurring task are generated from.
+type Series struct {
+	ID          string
+	Title       string
+	Description string
+	Recurrence  string
+	// Generated is the number of tasks created for the series so far, it is matched against COUNT.
+	Generated int
+}
+
+// Occurrences is a preview of upcoming due dates of a series.
+type Occurrences struct {
+	SeriesID    string   `json:"series_id"`
+	Recurrence  string   `json:"recurrence"`
+	Occurrences []string `json:"occurrences"`
+}
+
+// ValidateRecurrence checks an RFC 5545 RRULE value, e.g. "FREQ=WEEKLY;BYDAY=MO".
+// The start of the rule is always the task due date, so DTSTART is not accepted,
+// neither are frequencies finer than a day.
+func ValidateRecurrence(rule string) error {
+	_, err := parseRecurrence(rule)
+	return err
+}
+
+// Next returns up to n due dates following the occurrence due on the given date.
+// The occurrences missed by today are skipped, the next one is due today at the earliest,
+// so spawned tasks pass the DueDatePolicy whatever number of past days it allows.
+func (s Series) Next(due, today time.Time, n int) ([]time.Time, error) {
+	opt, err := parseRecurrence(s.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Count != 0 {
+		n = min(n, opt.Count-s.Generated)
+	}
+	opt.Count = 0
+	opt.Dtstart = due
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, errors.New(RecurrenceErr)
+	}
+	after := due
+	if yesterday := today.AddDate(0, 0, -1); yesterday.After(after) {
+		after = yesterday
+	}
+	dates := make([]time.Time, 0, max(n, 0))
+	for len(dates) < n {
+		next := rule.After(after, false)
+		if next.IsZero() {
+			break
+		}
+		dates = append(dates, next)
+		after = next
+	}
+	return dates, nil
+}
+
+// NextDates is Next for dates in the API format.
+func (s Series) NextDates(due string, today time.Time, n int) ([]string, error) {
+	dueDate, err := time.Parse(dateLayout, due)
+	if err != nil {
+		return nil, err
+	}
+	dates, err := s.Next(dueDate, today.UTC().Truncate(24*time.Hour), n)
+	if err != nil {
+		return nil, err
+	}
+	formatted := make([]string, 0, len(dates))
+	for _, date := range dates {
+		formatted = append(formatted, date.Format(dateLayout))
+	}
+	return formatted, nil
+}
+
+func parseRecurrence(rule string) (*rrule.ROption, error) {
+	if strings.ContainsAny(rule, "\r\n") {
+		return nil, errors.New(RecurrenceErr)
+	}
+	opt, err := rrule.StrToROption(rule)
+	if err != nil || !opt.Dtstart.IsZero() || opt.Freq > rrule.DAILY || opt.Count < 0 {
+		return nil, errors.New(RecurrenceErr)
+	}
+	if _, err = rrule.NewRRule(*opt); err != nil {
+		return nil, errors.New(RecurrenceErr)
+	}
+	return opt, nil
+}
+
+// SeriesField returns a member of the patch which cannot be applied to a whole series,
+// only title, description and recurrence are shared by occurrences.
+func (p Patch) SeriesField() string {
+	switch {
+	case p.DueDate != nil:
+		return "due_date"
+	case p.DueTime != nil:
+		return "due_time"
+	case p.TimeZone != nil:
+		return "time_zone"
+	case p.Status != nil:
+		return "status"
+	case p.State != nil:
+		return "state"
+	case p.Priority != nil:
+		return "priority"
+	case p.Tags != nil:
+		return "tags"
+	case p.ParentID != nil:
+		return "parent_id"
+	case p.ProjectID != nil:
+		return "project_id"
+	}
+	return ""
+}
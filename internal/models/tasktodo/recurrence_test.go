@@ -0,0 +1,78 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+	"time"
+)
+
+func TestValidateRecurrence(t *testing.T) {
+	assert.NoError(t, tasktodo.ValidateRecurrence("FREQ=WEEKLY;BYDAY=MO,TH"))
+	assert.NoError(t, tasktodo.ValidateRecurrence("RRULE:FREQ=MONTHLY;COUNT=3"))
+	for _, rule := range []string{
+		"WEEKLY",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=-1",
+		"DTSTART:20240101T000000Z\nRRULE:FREQ=DAILY",
+		"FREQ=DAILY;DTSTART=20240101T000000Z",
+	} {
+		assert.EqualError(t, tasktodo.ValidateRecurrence(rule), tasktodo.RecurrenceErr, rule)
+	}
+}
+
+func TestSeriesNextDates(t *testing.T) {
+	today := time.Date(2024, 10, 20, 15, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		series   tasktodo.Series
+		due      string
+		n        int
+		expected []string
+	}{
+		{
+			name:     "weekly",
+			series:   tasktodo.Series{Recurrence: "FREQ=WEEKLY", Generated: 1},
+			due:      "2024-10-21",
+			n:        3,
+			expected: []string{"2024-10-28", "2024-11-04", "2024-11-11"},
+		},
+		{
+			name:     "count limits occurrences",
+			series:   tasktodo.Series{Recurrence: "FREQ=MONTHLY;COUNT=3", Generated: 2},
+			due:      "2024-10-31",
+			n:        5,
+			expected: []string{"2024-12-31"},
+		},
+		{
+			name:     "count exhausted",
+			series:   tasktodo.Series{Recurrence: "FREQ=DAILY;COUNT=2", Generated: 2},
+			due:      "2024-10-21",
+			n:        1,
+			expected: []string{},
+		},
+		{
+			name:     "missed occurrences are skipped",
+			series:   tasktodo.Series{Recurrence: "FREQ=WEEKLY;BYDAY=MO,FR", Generated: 1},
+			due:      "2024-09-30",
+			n:        2,
+			expected: []string{"2024-10-21", "2024-10-25"},
+		},
+		{
+			name:     "until",
+			series:   tasktodo.Series{Recurrence: "FREQ=DAILY;UNTIL=20241023T000000Z", Generated: 1},
+			due:      "2024-10-21",
+			n:        5,
+			expected: []string{"2024-10-22", "2024-10-23"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dates, err := tc.series.NextDates(tc.due, today, tc.n)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, dates)
+		})
+	}
+}
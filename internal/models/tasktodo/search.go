@@ -0,0 +1,61 @@
+package tasktodo
+
+import (
+	"html"
+	"strings"
+)
+
+// SearchQueryLimit caps the length of a full-text query.
+const SearchQueryLimit = 256
+
+// HighlightStart and HighlightStop delimit matched words in fragments found by the storage.
+// They are control characters, so they pass through HTML escaping as they are. The storage strips them
+// from the text before it delimits the matches.
+const (
+	HighlightStart = '\x01'
+	HighlightStop  = '\x02'
+)
+
+// SearchResult is a task matching a full-text query, the best matches come first.
+type SearchResult struct {
+	Task
+	Rank float32 `json:"rank"`
+	// Highlights hold fragments of the title and description with matched words wrapped in <mark> tags.
+	Highlights Highlights `json:"highlights"`
+}
+
+type Highlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Highlight turns a fragment with delimited matches into HTML: the text is escaped,
+// so markup typed by users is shown as text, and the matches are wrapped in <mark> tags.
+// Delimiters out of place are dropped and an open match is closed, so the tags are always balanced.
+func Highlight(fragment string) string {
+	escaped := html.EscapeString(fragment)
+	var b strings.Builder
+	b.Grow(len(escaped))
+	var open bool
+	// both delimiters are single bytes which never occur within a multibyte UTF-8 sequence
+	for i := 0; i < len(escaped); i++ {
+		switch escaped[i] {
+		case HighlightStart:
+			if !open {
+				b.WriteString("<mark>")
+			}
+			open = true
+		case HighlightStop:
+			if open {
+				b.WriteString("</mark>")
+			}
+			open = false
+		default:
+			b.WriteByte(escaped[i])
+		}
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fragment string
+		expected string
+	}{
+		{name: "match", fragment: "fix the \x01release\x02 notes", expected: "fix the <mark>release</mark> notes"},
+		{name: "script in title", fragment: "<script>alert(1)</script> \x01release\x02",
+			expected: "&lt;script&gt;alert(1)&lt;/script&gt; <mark>release</mark>"},
+		{name: "markup inside a match", fragment: "\x01<img src=x onerror=\"alert(1)\">\x02",
+			expected: "<mark>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</mark>"},
+		{name: "no match", fragment: "a & b", expected: "a &amp; b"},
+		{name: "stray stop", fragment: "a\x02b \x01c\x02", expected: "ab <mark>c</mark>"},
+		{name: "nested start", fragment: "\x01a\x01b\x02", expected: "<mark>ab</mark>"},
+		{name: "unclosed start", fragment: "a \x01b", expected: "a <mark>b</mark>"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tasktodo.Highlight(tc.fragment), tc.name)
+	}
+}
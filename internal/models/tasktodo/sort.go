@@ -0,0 +1,44 @@
+package tasktodo
+
+import (
+	"errors"
+	"strings"
+)
+
+const SortErr = "bad sort"
+
+// SortFields lists the fields a task listing can be ordered by, state is ordered by its position in the workflow
+// and priority from P0 to P4.
+var SortFields = []string{"due_date", "title", "state", "created_at", "priority"}
+
+// SortField orders a listing by one field, ties are broken by the next field and finally by task ID.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort reads comma separated fields, each optionally prefixed with "-" for descending
+// or "+" for ascending order, e.g. "-due_date,title". Unknown and repeated fields are rejected.
+func ParseSort(sort string) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool, len(SortFields))
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimLeft(part, "+-"), Desc: strings.HasPrefix(part, "-")}
+		if len(part)-len(field.Field) > 1 || seen[field.Field] || !isSortField(field.Field) {
+			return nil, errors.New(SortErr)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func isSortField(field string) bool {
+	for _, known := range SortFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	fields, err := tasktodo.ParseSort(" -due_date, +title,state ,-created_at")
+	require.NoError(t, err)
+	assert.Equal(t, []tasktodo.SortField{
+		{Field: "due_date", Desc: true},
+		{Field: "title"},
+		{Field: "state"},
+		{Field: "created_at", Desc: true},
+	}, fields)
+
+	for _, bad := range []string{"", "id", "title,", "title,-title", "--title", "+-title", "due_date DESC", "title;DROP TABLE tasks"} {
+		_, err = tasktodo.ParseSort(bad)
+		assert.EqualError(t, err, tasktodo.SortErr, bad)
+	}
+}
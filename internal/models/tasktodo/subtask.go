@@ -0,0 +1,17 @@
+package tasktodo
+
+// Progress is the completion roll-up of all subtasks of a task.
+// Cancelled subtasks are left out, since they are never going to be done.
+type Progress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
+}
+
+func NewProgress(total, done int) Progress {
+	progress := Progress{Total: total, Done: done}
+	if total != 0 {
+		progress.Percent = done * 100 / total
+	}
+	return progress
+}
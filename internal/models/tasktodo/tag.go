@@ -0,0 +1,51 @@
+package tasktodo
+
+import (
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+)
+
+// TagMode selects how several tag filters are combined.
+type TagMode string
+
+const (
+	TagModeAny TagMode = "any"
+	TagModeAll TagMode = "all"
+)
+
+type Tag struct {
+	ID string `json:"id"`
+	TagRequest
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=64,excludes=0x2C"`
+}
+
+func NewTag(req TagRequest) Tag {
+	return Tag{
+		ID:         uuid.New().String(),
+		TagRequest: req,
+	}
+}
+
+// NormalizeTags trims, deduplicates and sorts tag names the same way they are returned from storage.
+// A nil slice stays nil, so "not provided" can be told apart from "no tags".
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -1,19 +1,124 @@
 package tasktodo
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
+	"time"
 )
 
 type Task struct {
 	ID string `json:"id,omitempty" validate:"required"`
+	// SeriesID links occurrences of a recurring task.
+	SeriesID string `json:"series_id,omitempty"`
 	Request
+	Version int64 `json:"version,omitempty"`
+	// DueAt is the moment the task is due, it is only set for a task due at a time of day.
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// CreatedAt, UpdatedAt and CompletedAt are kept by the storage, UpdatedAt changes with the version
+	// and CompletedAt is set while the task is done.
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Blocked is set while any of the tasks blocking this one is still open.
+	Blocked bool `json:"blocked,omitempty"`
+	// Progress is the completion roll-up of the subtasks, it is only filled for a single task.
+	Progress *Progress `json:"progress,omitempty"`
+	// CommentCount is the number of comments on the task, it is only filled for a single task.
+	CommentCount int `json:"comment_count,omitempty"`
+	// Checklist holds the checklist items of the task, it is only filled for a single task having any.
+	Checklist *Checklist `json:"checklist,omitempty"`
 }
 
 type Request struct {
 	Title       string `json:"title" validate:"required"`
 	Description string `json:"description" validate:"required"`
-	DueDate     string `json:"due_date" validate:"required"`
-	Status      *bool  `json:"status" validate:"required"`
+	// DueDate is a date, or an RFC 3339 time which sets both the date and DueTime in TimeZone.
+	DueDate string `json:"due_date" validate:"required"`
+	// DueTime is the time of day the task is due on its due date like 18:30, left out of an update
+	// the task is due on the date only.
+	DueTime string `json:"due_time,omitempty" validate:"omitempty,datetime=15:04"`
+	// TimeZone is the IANA time zone of the due time like Europe/Moscow, UTC if there is none.
+	TimeZone string `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+	Status   *bool  `json:"status" validate:"required_without=State"`
+	State    State  `json:"state,omitempty"`
+	// Priority left out of a new task is DefaultPriority, left out of an update it is kept.
+	Priority Priority `json:"priority,omitempty" validate:"omitempty,oneof=P0 P1 P2 P3 P4"`
+	// Tags left out of an update keep the current ones, an empty list removes them.
+	Tags []string `json:"tags" validate:"omitempty,dive,required,max=64,excludes=0x2C"`
+	// ParentID left out of an update keeps the current parent, an empty string makes the task top-level.
+	ParentID *string `json:"parent_id,omitempty"`
+	// Recurrence is an RFC 5545 RRULE of the task series, left out of an update it is kept,
+	// an empty string stops the recurrence of the task.
+	Recurrence *string `json:"recurrence,omitempty"`
+	// ProjectID left out of an update keeps the current project, an empty string takes the task out of its project.
+	ProjectID *string `json:"project_id,omitempty"`
+}
+
+// ListParams holds filters and pagination of a task listing.
+type ListParams struct {
+	Page    uint
+	Date    string
+	Status  string
+	Tags    []string
+	TagMode TagMode
+	Blocked string
+	// ProjectID limits the listing to a project, "none" to tasks outside of any project.
+	// Tasks of archived projects are only listed when their project is asked for.
+	ProjectID string
+	// Limit is the page size, After continues the listing past the cursor instead of skipping Page pages.
+	Limit uint
+	After *Cursor
+	// Lookahead is the number of tasks fetched past the page to tell whether another page follows,
+	// the offset of the page does not count them.
+	Lookahead uint
+	// DueFrom and DueTo bound the due date inclusively. Overdue is "true" or "false" like Blocked,
+	// a task is overdue when its due time, or the end of its due date, has passed in its time zone
+	// and it is neither done nor cancelled.
+	DueFrom string
+	DueTo   string
+	Overdue string
+	// Priorities limits the listing to tasks of any of the priorities.
+	Priorities []Priority
+	// CreatedAfter is an RFC 3339 time or a date.
+	CreatedAfter string
+	// TitlePrefix matches the beginning of the title regardless of case.
+	TitlePrefix string
+	// Sort orders the listing, by priority and due date if empty.
+	Sort []SortField
+	// Query is a parsed task query narrowing the listing further, nil if there is none.
+	Query taskquery.Node
+}
+
+// Patch is a JSON Merge Patch (RFC 7396) document for a task.
+// Only the members present in the document are applied, nil fields are left untouched.
+// Tags are replaced as a whole, null removes all of them. A null parent_id makes the task top-level,
+// a null recurrence stops the recurrence, a null project_id takes the task out of its project.
+// A null due_time leaves the task due on the date only, a null time_zone makes the due time UTC.
+type Patch struct {
+	Title       *string   `json:"title,omitempty" validate:"omitnil,min=1"`
+	Description *string   `json:"description,omitempty" validate:"omitnil,min=1"`
+	DueDate     *string   `json:"due_date,omitempty"`
+	DueTime     *string   `json:"due_time,omitempty" validate:"omitnil,len=0|datetime=15:04"`
+	TimeZone    *string   `json:"time_zone,omitempty" validate:"omitnil,len=0|timezone"`
+	Status      *bool     `json:"status,omitempty"`
+	State       *State    `json:"state,omitempty"`
+	Priority    *Priority `json:"priority,omitempty" validate:"omitnil,oneof=P0 P1 P2 P3 P4"`
+	Tags        *[]string `json:"tags,omitempty" validate:"omitnil,dive,required,max=64,excludes=0x2C"`
+	ParentID    *string   `json:"parent_id,omitempty"`
+	Recurrence  *string   `json:"recurrence,omitempty"`
+	ProjectID   *string   `json:"project_id,omitempty"`
+}
+
+// NullFieldError is returned when a merge patch tries to remove a required task field.
+type NullFieldError struct {
+	Field string
+}
+
+func (e NullFieldError) Error() string {
+	return fmt.Sprintf("field %q cannot be removed", e.Field)
 }
 
 func New(req Request) Task {
@@ -22,3 +127,53 @@ func New(req Request) Task {
 		Request: req,
 	}
 }
+
+// SetState sets the workflow state and keeps the legacy status flag in sync with it.
+func (r *Request) SetState(state State) {
+	done := state == StateDone
+	r.State = state
+	r.Status = &done
+}
+
+// Empty reports whether the patch does not change anything.
+func (p Patch) Empty() bool {
+	return p.Title == nil && p.Description == nil && p.DueDate == nil && p.DueTime == nil && p.TimeZone == nil && p.Status == nil && p.State == nil &&
+		p.Priority == nil && p.Tags == nil && p.ParentID == nil && p.Recurrence == nil && p.ProjectID == nil
+}
+
+// UnmarshalJSON decodes a merge patch document. In terms of RFC 7396 a null member
+// means removal, which is not allowed for any of the task fields.
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, field := range []string{"title", "description", "due_date", "status", "state", "priority"} {
+		if val, ok := members[field]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+			return NullFieldError{Field: field}
+		}
+	}
+	type plain Patch
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	if val, ok := members["tags"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.Tags = &[]string{}
+	}
+	if val, ok := members["due_time"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.DueTime = new(string)
+	}
+	if val, ok := members["time_zone"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.TimeZone = new(string)
+	}
+	if val, ok := members["parent_id"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.ParentID = new(string)
+	}
+	if val, ok := members["recurrence"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.Recurrence = new(string)
+	}
+	if val, ok := members["project_id"]; ok && bytes.Equal(bytes.TrimSpace(val), []byte("null")) {
+		p.ProjectID = new(string)
+	}
+	return nil
+}
@@ -1,9 +1,61 @@
 package tasktodo
 
+import "context"
+
 type Repo interface {
-	CreateTask(task Request) (Task, error)
-	DeleteTask(taskID string) error
-	GetTask(taskID string) (Task, error)
-	ListTasks(page uint, date string, status string) ([]Task, error)
-	UpdateTask(task Request, taskID string) (Task, error)
+	CreateTask(ctx context.Context, task Request) (Task, error)
+	DeleteTask(ctx context.Context, taskID string, version int64) error
+	GetTask(ctx context.Context, taskID string) (Task, error)
+	ListTasks(ctx context.Context, params ListParams) ([]Task, error)
+	CountTasks(ctx context.Context, params ListParams) (int, error)
+	GetMatrix(ctx context.Context, params MatrixParams) (Matrix, error)
+	SearchTasks(ctx context.Context, query string, params ListParams) ([]SearchResult, error)
+	ListTrash(ctx context.Context, params ListParams) ([]DeletedTask, error)
+	RestoreTask(ctx context.Context, taskID string) (Task, error)
+	PurgeTask(ctx context.Context, taskID string) error
+	UpdateTask(ctx context.Context, task Request, taskID string, version int64) (Task, error)
+	PatchTask(ctx context.Context, patch Patch, taskID string, version int64) (Task, error)
+	ApplyBulk(ctx context.Context, ops []BulkOp, atomic bool) ([]BulkOutcome, error)
+	ListHistory(ctx context.Context, taskID string, params ListParams) ([]Event, error)
+	GetTaskVersion(ctx context.Context, taskID string, version int64) (Request, error)
+	RevertTask(ctx context.Context, snapshot Request, taskID string, version int64) (Task, error)
+	ListChildren(ctx context.Context, taskID string) ([]Task, error)
+	ListSubtree(ctx context.Context, taskID string) ([]Task, error)
+	GetProgress(ctx context.Context, taskID string) (Progress, error)
+	CreateComment(ctx context.Context, taskID string, comment CommentRequest) (Comment, error)
+	ListComments(ctx context.Context, taskID string, limit uint, after *CommentCursor) ([]Comment, error)
+	UpdateComment(ctx context.Context, taskID, commentID string, comment CommentRequest) (Comment, error)
+	DeleteComment(ctx context.Context, taskID, commentID string) error
+	CountComments(ctx context.Context, taskID string) (int, error)
+	CreateAttachment(ctx context.Context, attachment Attachment) (Attachment, error)
+	ListAttachments(ctx context.Context, taskID string) ([]Attachment, error)
+	GetAttachment(ctx context.Context, taskID, attachmentID string) (Attachment, error)
+	DeleteAttachment(ctx context.Context, taskID, attachmentID string) error
+	GetChecklist(ctx context.Context, taskID string) (Checklist, error)
+	AddChecklistItem(ctx context.Context, taskID string, item ChecklistItemRequest) (Checklist, error)
+	ReorderChecklist(ctx context.Context, taskID string, itemIDs []string) (Checklist, error)
+	ToggleChecklistItem(ctx context.Context, taskID, itemID string, done bool, completeVersion int64) (Checklist, *Task, error)
+	RemoveChecklistItem(ctx context.Context, taskID, itemID string) (Checklist, error)
+	AddDependency(ctx context.Context, taskID, blockerID string) error
+	RemoveDependency(ctx context.Context, taskID, blockerID string) error
+	ListBlockers(ctx context.Context, taskID string) ([]Task, error)
+	GetSeries(ctx context.Context, seriesID string) (Series, error)
+	PatchSeries(ctx context.Context, patch Patch, taskID string, version int64) (Task, error)
+	CreateTag(ctx context.Context, tag TagRequest) (Tag, error)
+	GetTag(ctx context.Context, tagID string) (Tag, error)
+	ListTags(ctx context.Context) ([]Tag, error)
+	UpdateTag(ctx context.Context, tag TagRequest, tagID string) (Tag, error)
+	DeleteTag(ctx context.Context, tagID string) error
+	CreateProject(ctx context.Context, project ProjectRequest) (Project, error)
+	GetProject(ctx context.Context, projectID string) (Project, error)
+	ListProjects(ctx context.Context, archived bool) ([]Project, error)
+	UpdateProject(ctx context.Context, project ProjectRequest, projectID string) (Project, error)
+	DeleteProject(ctx context.Context, projectID string) error
+	ArchiveProject(ctx context.Context, projectID string, archived bool) (Project, error)
+	MoveTasks(ctx context.Context, projectID string, taskIDs []string) ([]Task, error)
+	CreateView(ctx context.Context, view ViewRequest) (View, error)
+	GetView(ctx context.Context, viewID string) (View, error)
+	ListViews(ctx context.Context) ([]View, error)
+	UpdateView(ctx context.Context, view ViewRequest, viewID string) (View, error)
+	DeleteView(ctx context.Context, viewID string) error
 }
@@ -0,0 +1,10 @@
+package tasktodo
+
+import "time"
+
+// DeletedTask is a task in the trash. It can be restored until it is purged, either by the user
+// or by the purge worker once the retention period has passed.
+type DeletedTask struct {
+	Task
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,34 @@
+package tasktodo
+
+import "github.com/google/uuid"
+
+// ViewFilters lists the listing parameters a saved view can hold, they mean the same as in GET /tasks.
+var ViewFilters = []string{"status", "date", "tag", "tag_mode", "blocked", "project",
+	"due_from", "due_to", "overdue", "priority", "created_after", "title_prefix", "q"}
+
+// View is a named task listing of a user, running it lists tasks the way GET /tasks does with the stored parameters.
+type View struct {
+	ID string `json:"id"`
+	ViewRequest
+}
+
+type ViewRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// Filters hold listing parameters by name, e.g. {"status": "false", "tag": "backend,api", "q": "overdue:true"}.
+	Filters map[string]string `json:"filters"`
+	// Sort is the sort parameter of the listing, tasks are ordered by priority and due date if it is empty.
+	Sort string `json:"sort" validate:"max=255"`
+	// PageSize is the limit parameter of the listing, 0 keeps the listing unpaginated by cursor.
+	// Sorted views are paged by number, as sorted listings are.
+	PageSize uint `json:"page_size"`
+}
+
+func NewView(req ViewRequest) View {
+	if req.Filters == nil {
+		req.Filters = make(map[string]string)
+	}
+	return View{
+		ID:          uuid.New().String(),
+		ViewRequest: req,
+	}
+}
@@ -0,0 +1,200 @@
+package tasktodo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vlasashk/task-manager/config"
+	"sort"
+	"strings"
+)
+
+// State is a workflow state of a task. The set of possible states is fixed by the
+// task_state enum in the database, the workflow decides which of them are used.
+type State string
+
+const (
+	StateTodo       State = "todo"
+	StateInProgress State = "in_progress"
+	StateBlocked    State = "blocked"
+	StateInReview   State = "in_review"
+	StateDone       State = "done"
+	StateCancelled  State = "cancelled"
+)
+
+const (
+	StateErr      = "unknown state"
+	TransitionErr = "transition not allowed"
+)
+
+// States lists every state known to the storage.
+var States = []State{StateTodo, StateInProgress, StateBlocked, StateInReview, StateDone, StateCancelled}
+
+var defaultTransitions = map[State][]State{
+	StateTodo:       {StateInProgress, StateBlocked, StateDone, StateCancelled},
+	StateInProgress: {StateTodo, StateBlocked, StateInReview, StateDone, StateCancelled},
+	StateBlocked:    {StateTodo, StateInProgress, StateCancelled},
+	StateInReview:   {StateInProgress, StateDone, StateCancelled},
+	StateDone:       {StateTodo, StateInProgress},
+	StateCancelled:  {StateTodo},
+}
+
+// Transition is a request to move a task to another workflow state.
+type Transition struct {
+	State State `json:"state" validate:"required"`
+}
+
+// Workflow defines the states a task can be in and the allowed moves between them.
+type Workflow struct {
+	initial     State
+	states      []State
+	transitions map[State]map[State]bool
+}
+
+// WorkflowDefinition is a serializable view of a Workflow.
+type WorkflowDefinition struct {
+	Initial     State             `json:"initial"`
+	Done        State             `json:"done"`
+	States      []State           `json:"states"`
+	Transitions map[State][]State `json:"transitions"`
+}
+
+// DefaultWorkflow uses every known state, starting from todo.
+func DefaultWorkflow() Workflow {
+	wf, _ := newWorkflow(StateTodo, States, defaultTransitions, true)
+	return wf
+}
+
+// NewWorkflow builds a workflow from config, empty values fall back to the defaults.
+// Transitions are written as "from:to1|to2", e.g. "todo:in_progress|done".
+func NewWorkflow(cfg config.WorkflowCfg) (Workflow, error) {
+	initial := StateTodo
+	if cfg.Initial != "" {
+		initial = State(cfg.Initial)
+	}
+	states := States
+	if len(cfg.States) != 0 {
+		states = make([]State, 0, len(cfg.States))
+		for _, state := range cfg.States {
+			states = append(states, State(strings.TrimSpace(state)))
+		}
+	}
+	transitions, strict := defaultTransitions, false
+	if len(cfg.Transitions) != 0 {
+		strict = true
+		transitions = make(map[State][]State, len(cfg.Transitions))
+		for from, targets := range cfg.Transitions {
+			state := State(strings.TrimSpace(from))
+			for _, to := range strings.Split(targets, "|") {
+				if to = strings.TrimSpace(to); to != "" {
+					transitions[state] = append(transitions[state], State(to))
+				}
+			}
+		}
+	}
+	return newWorkflow(initial, states, transitions, strict)
+}
+
+// newWorkflow validates the definition. With strict disabled, transitions touching
+// states that are not enabled are dropped instead of failing, which lets the default
+// transitions be reused with a reduced set of states.
+func newWorkflow(initial State, states []State, transitions map[State][]State, strict bool) (Workflow, error) {
+	wf := Workflow{
+		initial:     initial,
+		states:      states,
+		transitions: make(map[State]map[State]bool, len(states)),
+	}
+	for _, state := range states {
+		if !known(state) {
+			return Workflow{}, fmt.Errorf("workflow: %s %q", StateErr, state)
+		}
+		wf.transitions[state] = map[State]bool{}
+	}
+	if !wf.Valid(initial) {
+		return Workflow{}, fmt.Errorf("workflow: initial state %q is not enabled", initial)
+	}
+	if !wf.Valid(StateDone) {
+		return Workflow{}, errors.New("workflow: done state is not enabled")
+	}
+	for from, targets := range transitions {
+		for _, to := range targets {
+			if !wf.Valid(from) || !wf.Valid(to) {
+				if !strict {
+					continue
+				}
+				return Workflow{}, fmt.Errorf("workflow: transition %s -> %s uses disabled state", from, to)
+			}
+			wf.transitions[from][to] = true
+		}
+	}
+	return wf, nil
+}
+
+func known(state State) bool {
+	for _, s := range States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Initial is the state of newly created tasks.
+func (wf Workflow) Initial() State {
+	return wf.initial
+}
+
+// Valid reports whether the state is enabled in the workflow.
+func (wf Workflow) Valid(state State) bool {
+	_, ok := wf.transitions[state]
+	return ok
+}
+
+// Check returns an error if the task cannot be moved from one state to another.
+// Staying in the same state is always allowed.
+func (wf Workflow) Check(from, to State) error {
+	if !wf.Valid(to) {
+		return errors.New(StateErr)
+	}
+	if from == to || wf.transitions[from][to] {
+		return nil
+	}
+	return errors.New(TransitionErr)
+}
+
+// Resolve returns the state requested by a create or update, where current is
+// empty for new tasks. An explicit state wins, otherwise the legacy boolean status
+// is mapped: true means done, false reopens a done task and keeps any other state.
+func (wf Workflow) Resolve(current, state State, status *bool) (State, error) {
+	switch {
+	case state != "":
+		if !wf.Valid(state) {
+			return "", errors.New(StateErr)
+		}
+		return state, nil
+	case status != nil && *status:
+		return StateDone, nil
+	case current == "" || (status != nil && current == StateDone):
+		return wf.initial, nil
+	default:
+		return current, nil
+	}
+}
+
+// Definition describes the workflow for clients.
+func (wf Workflow) Definition() WorkflowDefinition {
+	def := WorkflowDefinition{
+		Initial:     wf.initial,
+		Done:        StateDone,
+		States:      wf.states,
+		Transitions: make(map[State][]State, len(wf.transitions)),
+	}
+	for from, targets := range wf.transitions {
+		moves := make([]State, 0, len(targets))
+		for to := range targets {
+			moves = append(moves, to)
+		}
+		sort.Slice(moves, func(i, j int) bool { return moves[i] < moves[j] })
+		def.Transitions[from] = moves
+	}
+	return def
+}
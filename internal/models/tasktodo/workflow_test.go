@@ -0,0 +1,69 @@
+package tasktodo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"testing"
+)
+
+func TestWorkflowResolve(t *testing.T) {
+	done, open := true, false
+	wf := tasktodo.DefaultWorkflow()
+	testCases := []struct {
+		name     string
+		current  tasktodo.State
+		state    tasktodo.State
+		status   *bool
+		expected tasktodo.State
+		err      string
+	}{
+		{name: "new task defaults to initial", expected: tasktodo.StateTodo},
+		{name: "new completed task", status: &done, expected: tasktodo.StateDone},
+		{name: "explicit state wins", current: tasktodo.StateTodo, state: tasktodo.StateInReview, status: &done, expected: tasktodo.StateInReview},
+		{name: "status false keeps open state", current: tasktodo.StateBlocked, status: &open, expected: tasktodo.StateBlocked},
+		{name: "status false reopens done task", current: tasktodo.StateDone, status: &open, expected: tasktodo.StateTodo},
+		{name: "no status keeps state", current: tasktodo.StateInProgress, expected: tasktodo.StateInProgress},
+		{name: "unknown state", state: "archived", err: tasktodo.StateErr},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := wf.Resolve(tc.current, tc.state, tc.status)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, state)
+		})
+	}
+}
+
+func TestNewWorkflow(t *testing.T) {
+	wf, err := tasktodo.NewWorkflow(config.WorkflowCfg{
+		States:      []string{"todo", "in_progress", "done"},
+		Transitions: map[string]string{"todo": "in_progress", "in_progress": "done|todo"},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, wf.Check(tasktodo.StateTodo, tasktodo.StateInProgress))
+	assert.NoError(t, wf.Check(tasktodo.StateInProgress, tasktodo.StateDone))
+	assert.NoError(t, wf.Check(tasktodo.StateDone, tasktodo.StateDone))
+	assert.EqualError(t, wf.Check(tasktodo.StateTodo, tasktodo.StateDone), tasktodo.TransitionErr)
+	assert.EqualError(t, wf.Check(tasktodo.StateDone, tasktodo.StateTodo), tasktodo.TransitionErr)
+	assert.EqualError(t, wf.Check(tasktodo.StateTodo, tasktodo.StateBlocked), tasktodo.StateErr)
+
+	reduced, err := tasktodo.NewWorkflow(config.WorkflowCfg{States: []string{"todo", "done"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[tasktodo.State][]tasktodo.State{
+		tasktodo.StateTodo: {tasktodo.StateDone},
+		tasktodo.StateDone: {tasktodo.StateTodo},
+	}, reduced.Definition().Transitions)
+
+	_, err = tasktodo.NewWorkflow(config.WorkflowCfg{States: []string{"todo", "in_progress"}})
+	assert.Error(t, err, "done state is mandatory")
+	_, err = tasktodo.NewWorkflow(config.WorkflowCfg{States: []string{"todo", "done"}, Transitions: map[string]string{"todo": "blocked"}})
+	assert.Error(t, err, "transition to a disabled state")
+	_, err = tasktodo.NewWorkflow(config.WorkflowCfg{Initial: "archived"})
+	assert.Error(t, err, "unknown initial state")
+}
@@ -0,0 +1,110 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"net/http"
+	"strings"
+)
+
+// CreateAPIKey creates a new API key of the user.
+//
+//	@Summary		Creates an API key
+//	@Description	Creates a key for machine clients with the given scopes: tasks:read, tasks:write, tasks:delete. The secret is returned only once
+//	@Tags			API keys
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			keyRequest	body		account.APIKeyRequest	true	"Name and scopes of the new key"
+//	@Success		201			{object}	account.CreatedAPIKey	"Key successfully created"
+//	@Failure		400			{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Keys are managed by users only"
+//	@Failure		422			{object}	ErrResp					"Invalid JSON or unknown scope"
+//	@Router			/keys [post]
+func (s Service) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeKeysManage) {
+		return
+	}
+	keyRequest := account.APIKeyRequest{}
+	log := *zerolog.Ctx(r.Context())
+	if err := render.DecodeJSON(r.Body, &keyRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	keyRequest.Name = strings.TrimSpace(keyRequest.Name)
+	if err := validator.New().Struct(keyRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	key, err := account.NewAPIKey(keyRequest, account.UserID(r.Context()))
+	if err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	if err = s.Users.CreateAPIKey(r.Context(), key.APIKey); err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	log.Info().Str("key_id", key.ID).Msg("api key created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, key)
+}
+
+// ListAPIKeys returns the API keys of the user.
+//
+//	@Summary		Returns API keys
+//	@Description	Retrieves all keys of the user including revoked ones, secrets are never returned
+//	@Tags			API keys
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	[]account.APIKey	"List of keys"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Keys are managed by users only"
+//	@Router			/keys [get]
+func (s Service) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeKeysManage) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	keys, err := s.Users.ListAPIKeys(r.Context())
+	if err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	log.Info().Int("amount", len(keys)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, keys)
+}
+
+// RevokeAPIKey revokes an API key of the user.
+//
+//	@Summary		Revokes an API key
+//	@Description	Revokes the key, requests made with it are rejected from now on
+//	@Tags			API keys
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Key ID"
+//	@Success		200	{object}	MsgResp	"Key revoked"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Keys are managed by users only"
+//	@Failure		404	{object}	MsgResp	"Key not found or already revoked"
+//	@Router			/keys/{id} [delete]
+func (s Service) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeKeysManage) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	keyID := chi.URLParam(r, "id")
+	if err := s.Users.RevokeAPIKey(r.Context(), keyID); err != nil {
+		authErrorHandler(w, r, log.With().Str("key_id", keyID).Logger(), err)
+		return
+	}
+	log.Info().Str("key_id", keyID).Msg("api key revoked successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
@@ -0,0 +1,169 @@
+package httpchi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestAPIKeyHandlers() {
+	type keyTestCase struct {
+		handler   func(s httpchi.Service) http.HandlerFunc
+		principal account.Principal
+		TestCase
+	}
+	users := mocks.NewUserRepo(suite.T())
+	session := account.Principal{UserID: "user", Scopes: account.SessionScopes}
+	machine := account.Principal{UserID: "user", KeyID: "key", Scopes: []account.Scope{account.ScopeTasksRead}}
+	testCases := []keyTestCase{
+		{
+			handler:   func(s httpchi.Service) http.HandlerFunc { return s.CreateAPIKey },
+			principal: session,
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"name":"ci","scopes":["keys:manage"]}`,
+			},
+		},
+		{
+			handler:   func(s httpchi.Service) http.HandlerFunc { return s.CreateAPIKey },
+			principal: machine,
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusForbidden,
+				expectedResp:  `{"param":"scope","value":"keys:manage","error":"insufficient scope"}`,
+				reqBody:       `{"name":"ci","scopes":["tasks:read"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListAPIKeys },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnauthorized,
+				expectedResp:  `{"param":"Authorization","error":"missing credentials"}`,
+			},
+		},
+		{
+			handler:   func(s httpchi.Service) http.HandlerFunc { return s.ListAPIKeys },
+			principal: session,
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("ListAPIKeys", mock.Anything).Return([]account.APIKey{{ID: "key", Name: "ci", Prefix: "tm_abcdefgh",
+						Scopes: []account.Scope{account.ScopeTasksRead}, UserID: "user", Hash: "hash"}}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"key","name":"ci","prefix":"tm_abcdefgh","scopes":["tasks:read"],"created_at":"0001-01-01T00:00:00Z"}]`,
+			},
+		},
+		{
+			handler:   func(s httpchi.Service) http.HandlerFunc { return s.RevokeAPIKey },
+			principal: session,
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("RevokeAPIKey", mock.Anything, "test").Return(errors.New(account.InvalidKeyErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid api key"}`,
+			},
+		},
+		{
+			handler:   func(s httpchi.Service) http.HandlerFunc { return s.DeleteTask },
+			principal: machine,
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusForbidden,
+				expectedResp:  `{"param":"scope","value":"tasks:delete","error":"insufficient scope"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(users, suite.newIssuer()))
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := httptest.NewRequest("POST", "/keys", strings.NewReader(tc.reqBody))
+		reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, ctx)
+		if tc.principal.UserID != "" {
+			reqCtx = account.WithPrincipal(reqCtx, tc.principal)
+		}
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req.WithContext(reqCtx))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestCreateAPIKey() {
+	users := mocks.NewUserRepo(suite.T())
+	var stored account.APIKey
+	users.On("CreateAPIKey", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(account.APIKey)
+	}).Return(nil).Once()
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(users, suite.newIssuer()))
+	w := httptest.NewRecorder()
+
+	suite.service.CreateAPIKey(w, newRequest("POST", "/keys", strings.NewReader(`{"name":" ci ","scopes":["tasks:write","tasks:read","tasks:write"]}`)))
+
+	suite.Equal(http.StatusCreated, w.Code)
+	var created account.CreatedAPIKey
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	suite.True(account.IsAPIKey(created.Key))
+	suite.True(strings.HasPrefix(created.Key, created.Prefix))
+	suite.Equal("ci", stored.Name)
+	suite.Equal("user", stored.UserID)
+	suite.Equal(account.HashKey(created.Key), stored.Hash)
+	suite.Equal([]account.Scope{account.ScopeTasksRead, account.ScopeTasksWrite}, stored.Scopes)
+	suite.NotContains(w.Body.String(), stored.Hash)
+}
+
+func (suite *UnitTestSuite) TestAuthenticateAPIKey() {
+	users := mocks.NewUserRepo(suite.T())
+	secret := "tm_secret"
+	key := account.APIKey{ID: "key", UserID: "owner", Scopes: []account.Scope{account.ScopeTasksRead}}
+	users.On("GetAPIKeyByHash", mock.Anything, account.HashKey(secret)).Return(key, nil).Times(3)
+	users.On("GetAPIKeyByHash", mock.Anything, account.HashKey("tm_revoked")).Return(account.APIKey{}, errors.New(account.InvalidKeyErr)).Once()
+	users.On("TouchAPIKey", mock.Anything, "key").Return(nil).Twice()
+	users.On("TouchAPIKey", mock.Anything, "key").Return(errors.New("any err")).Once()
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(users, suite.newIssuer()))
+	router := httpchi.NewRouter(suite.service, zerolog.Nop())
+	owned := mock.MatchedBy(func(ctx context.Context) bool { return account.UserID(ctx) == "owner" })
+	suite.storage.(*mocks.Repo).On("ListTags", owned).Return([]tasktodo.Tag{}, nil).Twice()
+
+	testCases := []struct {
+		method       string
+		header       string
+		value        string
+		expectedCode int
+		expectedResp string
+	}{
+		{method: "GET", header: "X-API-Key", value: secret, expectedCode: http.StatusOK, expectedResp: `[]`},
+		{method: "GET", header: "Authorization", value: "Bearer " + secret, expectedCode: http.StatusOK, expectedResp: `[]`},
+		{method: "POST", header: "X-API-Key", value: secret, expectedCode: http.StatusForbidden,
+			expectedResp: `{"param":"scope","value":"tasks:write","error":"insufficient scope"}`},
+		{method: "GET", header: "X-API-Key", value: "tm_revoked", expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"param":"Authorization","error":"invalid api key"}`},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, "/api/tags", strings.NewReader(`{"name":"work"}`))
+		req.Header.Set(tc.header, tc.value)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
@@ -0,0 +1,315 @@
+package httpchi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// multipartOverhead is allowed above the attachment size limit for the boundaries and headers of the form.
+const multipartOverhead = 1 << 20
+
+// UploadAttachment attaches a file to a task.
+//
+//	@Summary		Attaches a file to a task
+//	@Description	Uploads the file sent as the "file" field of a multipart form. The type is detected from the content,
+//	@Description	it has to be allowed by the server as well as the size. Files with the same content are stored once
+//	@Tags			Attachments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id		path		string				true	"Task ID"
+//	@Param			file	formData	file				true	"File to attach"
+//	@Success		201		{object}	tasktodo.Attachment	"Attachment successfully created"
+//	@Failure		400		{object}	ErrResp				"Malformed form or missing file"
+//	@Failure		401		{object}	ErrResp				"Missing credentials"
+//	@Failure		403		{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404		{object}	MsgResp				"Task not found"
+//	@Failure		413		{object}	ErrResp				"File is too large"
+//	@Failure		415		{object}	ErrResp				"File type is not allowed"
+//	@Router			/task/{id}/attachments [post]
+func (s Service) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	upload, ok := s.receiveUpload(w, r, log)
+	if !ok {
+		return
+	}
+	defer upload.remove(log)
+	logID := log.With().Str("id", taskID).Str("sha256", upload.sum).Logger()
+
+	attachment := tasktodo.NewAttachment(taskID, account.UserID(r.Context()), upload.name, upload.contentType, upload.size, upload.sum)
+	attachment, err := s.DB.CreateAttachment(r.Context(), attachment)
+	if err != nil {
+		attachmentErrorHandler(w, r, logID, taskID, err)
+		return
+	}
+	// the attachment is registered first, so the purge does not remove the content while it is being put
+	if err = s.storeUpload(r, upload); err != nil {
+		logID.Error().Err(err).Msg("storing attachment fail")
+		if err = s.DB.DeleteAttachment(r.Context(), taskID, attachment.ID); err != nil {
+			logID.Error().Err(err).Str("attachment_id", attachment.ID).Msg("removing attachment fail")
+		}
+		NewErr("id", taskID, "action fail").Send(w, r, http.StatusInternalServerError)
+		return
+	}
+	logID.Info().Str("attachment_id", attachment.ID).Int64("size", attachment.Size).Msg("attachment created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, attachment)
+}
+
+// ListAttachments returns attachments of a task.
+//
+//	@Summary		Returns attachments of a task
+//	@Description	Retrieves the metadata of the files attached to a task, the oldest first
+//	@Tags			Attachments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string					true	"Task ID"
+//	@Success		200	{object}	[]tasktodo.Attachment	"Attachments of the task"
+//	@Failure		401	{object}	ErrResp					"Missing credentials"
+//	@Failure		403	{object}	ErrResp					"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp					"Task not found"
+//	@Router			/task/{id}/attachments [get]
+func (s Service) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	attachments, err := s.DB.ListAttachments(r.Context(), taskID)
+	if err != nil {
+		attachmentErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Int("amount", len(attachments)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, attachments)
+}
+
+// DownloadAttachment returns the content of an attachment.
+//
+//	@Summary		Downloads an attachment
+//	@Description	Returns the content of the attached file with its detected type. The content never changes,
+//	@Description	so the ETag is its SHA-256 and If-None-Match is answered with 304 Not Modified
+//	@Tags			Attachments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		octet-stream
+//	@Param			id				path		string	true	"Task ID"
+//	@Param			aid				path		string	true	"Attachment ID"
+//	@Param			If-None-Match	header		string	false	"ETag of the cached content"
+//	@Success		200				{file}		file	"Content of the attachment"
+//	@Header			200				{string}	ETag	"SHA-256 of the content"
+//	@Success		304				"Not modified"
+//	@Failure		401				{object}	ErrResp	"Missing credentials"
+//	@Failure		403				{object}	ErrResp	"Scope tasks:read not granted"
+//	@Failure		404				{object}	MsgResp	"Attachment not found"
+//	@Router			/task/{id}/attachments/{aid} [get]
+func (s Service) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID, attachmentID := chi.URLParam(r, "id"), chi.URLParam(r, "aid")
+	log.Info().Str("id", taskID).Str("attachment_id", attachmentID).Msg("attachment id received")
+	logID := log.With().Str("id", taskID).Str("attachment_id", attachmentID).Logger()
+	attachment, err := s.DB.GetAttachment(r.Context(), taskID, attachmentID)
+	if err != nil {
+		attachmentErrorHandler(w, r, logID, taskID, err)
+		return
+	}
+	tag := `"` + attachment.SHA256 + `"`
+	w.Header().Set("ETag", tag)
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" && etagMatch(noneMatch, tag) {
+		logID.Info().Msg("not modified")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	content, err := s.Blobs.Get(r.Context(), attachment.SHA256)
+	if err != nil {
+		attachmentErrorHandler(w, r, logID, taskID, err)
+		return
+	}
+	defer content.Close()
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, content); err != nil {
+		logID.Error().Err(err).Msg("sending attachment fail")
+		return
+	}
+	logID.Info().Int64("size", attachment.Size).Msg("attachment sent successfully")
+}
+
+// DeleteAttachment deletes an attachment of a task.
+//
+//	@Summary		Deletes an attachment
+//	@Description	Deletes the attachment, its content is removed later by the purger unless other attachments share it
+//	@Tags			Attachments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Task ID"
+//	@Param			aid	path		string	true	"Attachment ID"
+//	@Success		200	{object}	MsgResp	"Attachment successfully deleted"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"Attachment not found"
+//	@Router			/task/{id}/attachments/{aid} [delete]
+func (s Service) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID, attachmentID := chi.URLParam(r, "id"), chi.URLParam(r, "aid")
+	log.Info().Str("id", taskID).Str("attachment_id", attachmentID).Msg("attachment id received")
+	if err := s.DB.DeleteAttachment(r.Context(), taskID, attachmentID); err != nil {
+		attachmentErrorHandler(w, r, log.With().Str("id", taskID).Str("attachment_id", attachmentID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("attachment_id", attachmentID).Msg("attachment deleted successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
+
+// upload is a received file spooled to a temporary file along with what is known of its content.
+type upload struct {
+	file        *os.File
+	name        string
+	contentType string
+	size        int64
+	sum         string
+}
+
+func (u upload) remove(log zerolog.Logger) {
+	u.file.Close()
+	if err := os.Remove(u.file.Name()); err != nil {
+		log.Error().Err(err).Msg("removing upload fail")
+	}
+}
+
+// receiveUpload spools the "file" field of the multipart form to a temporary file, hashing it on the way,
+// and checks the size and the type of the content. Other fields of the form are skipped.
+func (s Service) receiveUpload(w http.ResponseWriter, r *http.Request, log zerolog.Logger) (upload, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.Attachments.MaxSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		log.Error().Err(err).Send()
+		NewErr("file", "", "multipart form expected").Send(w, r, http.StatusBadRequest)
+		return upload{}, false
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			uploadErrorHandler(w, r, log, err)
+			return upload{}, false
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		name := tasktodo.AttachmentName(part.FileName())
+		if name == "" {
+			log.Error().Str("filename", part.FileName()).Msg("missing file name")
+			NewErr("file", "", "file name is missing").Send(w, r, http.StatusBadRequest)
+			return upload{}, false
+		}
+		file, err := os.CreateTemp("", "attachment-*")
+		if err != nil {
+			log.Error().Err(err).Send()
+			NewErr("file", name, "action fail").Send(w, r, http.StatusInternalServerError)
+			return upload{}, false
+		}
+		received := upload{file: file, name: name}
+		if !s.spool(w, r, log, part, &received) {
+			received.remove(log)
+			return upload{}, false
+		}
+		return received, true
+	}
+}
+
+func (s Service) spool(w http.ResponseWriter, r *http.Request, log zerolog.Logger, content io.Reader, received *upload) bool {
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(received.file, hash), io.LimitReader(content, s.Attachments.MaxSize+1))
+	if err != nil {
+		uploadErrorHandler(w, r, log, err)
+		return false
+	}
+	if size > s.Attachments.MaxSize {
+		log.Warn().Int64("max_size", s.Attachments.MaxSize).Msg(tasktodo.AttachmentSizeErr)
+		NewErr("file", received.name, tasktodo.AttachmentSizeErr).Send(w, r, http.StatusRequestEntityTooLarge)
+		return false
+	}
+	head := make([]byte, 512)
+	read, err := received.file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Send()
+		NewErr("file", received.name, "action fail").Send(w, r, http.StatusInternalServerError)
+		return false
+	}
+	contentType, allowed := s.Attachments.DetectType(head[:read])
+	if !allowed {
+		log.Warn().Str("content_type", contentType).Msg(tasktodo.AttachmentTypeErr)
+		NewErr("file", contentType, tasktodo.AttachmentTypeErr).Send(w, r, http.StatusUnsupportedMediaType)
+		return false
+	}
+	received.contentType, received.size, received.sum = contentType, size, hex.EncodeToString(hash.Sum(nil))
+	return true
+}
+
+// storeUpload puts the content to the blob store unless it is there already.
+func (s Service) storeUpload(r *http.Request, received upload) error {
+	exists, err := s.Blobs.Exists(r.Context(), received.sum)
+	if err != nil || exists {
+		return err
+	}
+	if _, err = received.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return s.Blobs.Put(r.Context(), received.sum, received.file, received.size, received.contentType)
+}
+
+func uploadErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		log.Warn().Err(err).Send()
+		NewErr("file", "", tasktodo.AttachmentSizeErr).Send(w, r, http.StatusRequestEntityTooLarge)
+	case errors.Is(err, io.EOF):
+		log.Error().Msg("file field is missing")
+		NewErr("file", "", "file is missing").Send(w, r, http.StatusBadRequest)
+	default:
+		log.Error().Err(err).Send()
+		NewErr("file", "", "bad multipart form").Send(w, r, http.StatusBadRequest)
+	}
+}
+
+func attachmentErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, taskID string, err error) {
+	switch err.Error() {
+	case pgrepo.InvalidAttachmentIdErr, tasktodo.BlobNotFoundErr:
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidAttachmentIdErr).Send(w, r, http.StatusNotFound)
+	default:
+		errorHandler(w, r, log, "", taskID, err)
+	}
+}
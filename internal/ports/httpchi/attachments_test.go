@@ -0,0 +1,275 @@
+package httpchi_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/adapters/blobstore"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+const attachmentContent = "2024-10-20 12:00:00 ERR connection refused\n"
+
+// failingBlobs is a blob store which is unavailable.
+type failingBlobs struct {
+	tasktodo.BlobStore
+}
+
+func (failingBlobs) Exists(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+// multipartBody returns a form with the file field, the file is left out when the filename is empty.
+func multipartBody(field, filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if filename != "" {
+		part, _ := form.CreateFormFile(field, filename)
+		_, _ = part.Write([]byte(content))
+	} else {
+		_ = form.WriteField(field, content)
+	}
+	_ = form.Close()
+	return body, form.FormDataContentType()
+}
+
+func (suite *UnitTestSuite) attachmentService(blobs tasktodo.BlobStore) httpchi.Service {
+	policy, err := tasktodo.NewAttachmentPolicy(config.AttachmentCfg{MaxSize: 64, Types: []string{"text/plain", "image/*"}})
+	suite.Require().NoError(err)
+	return httpchi.NewService(suite.storage, httpchi.WithAttachments(blobs, policy))
+}
+
+func (suite *UnitTestSuite) TestUploadAttachment() {
+	blobs, err := blobstore.NewLocal(suite.T().TempDir())
+	suite.Require().NoError(err)
+	sum := sha256.Sum256([]byte(attachmentContent))
+	key := hex.EncodeToString(sum[:])
+	created := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	uploaded := mock.MatchedBy(func(a tasktodo.Attachment) bool {
+		return a.ID != "" && a.TaskID == "test" && a.Name == "app.log" && a.ContentType == "text/plain" &&
+			a.Size == int64(len(attachmentContent)) && a.SHA256 == key && a.UploaderID == "user"
+	})
+	attachment := tasktodo.Attachment{ID: "a1", TaskID: "test", Name: "app.log", ContentType: "text/plain",
+		Size: int64(len(attachmentContent)), SHA256: key, UploaderID: "user", CreatedAt: created}
+	testCases := []struct {
+		testName      string
+		blobs         tasktodo.BlobStore
+		storageOutput func()
+		field         string
+		filename      string
+		content       string
+		contentType   string
+		expectedCode  int
+		expectedResp  string
+	}{
+		{
+			testName: "log file",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("CreateAttachment", mock.Anything, uploaded).Return(attachment, nil).Once()
+			},
+			field: "file", filename: "logs/app.log", content: attachmentContent,
+			expectedCode: http.StatusCreated,
+			expectedResp: `{"id":"a1","task_id":"test","name":"app.log","content_type":"text/plain","size":43,` +
+				`"sha256":"` + key + `","uploader_id":"user","created_at":"2024-10-20T12:00:00Z"}`,
+		},
+		{
+			testName: "missing task",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("CreateAttachment", mock.Anything, uploaded).
+					Return(tasktodo.Attachment{}, errors.New(pgrepo.InvalidIdErr)).Once()
+			},
+			field: "file", filename: "app.log", content: attachmentContent,
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"invalid task id"}`,
+		},
+		{
+			testName: "blob store fails",
+			blobs:    failingBlobs{},
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("CreateAttachment", mock.Anything, uploaded).Return(attachment, nil).Once()
+				suite.storage.(*mocks.Repo).On("DeleteAttachment", mock.Anything, "test", "a1").Return(nil).Once()
+			},
+			field: "file", filename: "app.log", content: attachmentContent,
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
+		},
+		{
+			testName:      "too large",
+			storageOutput: func() {},
+			field:         "file", filename: "app.log", content: strings.Repeat("x", 65),
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedResp: `{"param":"file","value":"app.log","error":"attachment is too large"}`,
+		},
+		{
+			testName:      "type not allowed",
+			storageOutput: func() {},
+			field:         "file", filename: "page.txt", content: "<html><body>hi</body></html>",
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedResp: `{"param":"file","value":"text/html","error":"attachment type is not allowed"}`,
+		},
+		{
+			testName:      "missing file",
+			storageOutput: func() {},
+			field:         "note", content: "see attached",
+			expectedCode: http.StatusBadRequest,
+			expectedResp: `{"param":"file","error":"file is missing"}`,
+		},
+		{
+			testName:      "not a form",
+			storageOutput: func() {},
+			contentType:   "application/json",
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"file","error":"multipart form expected"}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		store := tasktodo.BlobStore(blobs)
+		if tc.blobs != nil {
+			store = tc.blobs
+		}
+		suite.service = suite.attachmentService(store)
+		body, contentType := multipartBody(tc.field, tc.filename, tc.content)
+		if tc.contentType != "" {
+			contentType = tc.contentType
+		}
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest("POST", "/task/test/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.UploadAttachment(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code, tc.testName)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()), tc.testName)
+	}
+	exists, err := blobs.Exists(context.Background(), key)
+	suite.Require().NoError(err)
+	suite.True(exists)
+}
+
+func (suite *UnitTestSuite) TestDownloadAttachment() {
+	blobs, err := blobstore.NewLocal(suite.T().TempDir())
+	suite.Require().NoError(err)
+	sum := sha256.Sum256([]byte(attachmentContent))
+	key := hex.EncodeToString(sum[:])
+	suite.Require().NoError(blobs.Put(context.Background(), key, strings.NewReader(attachmentContent), int64(len(attachmentContent)), "text/plain"))
+	attachment := tasktodo.Attachment{ID: "a1", TaskID: "test", Name: "app log.txt", ContentType: "text/plain",
+		Size: int64(len(attachmentContent)), SHA256: key, UploaderID: "user"}
+	lost := attachment
+	lost.ID, lost.SHA256 = "a2", strings.Repeat("0", 64)
+	repo := suite.storage.(*mocks.Repo)
+	repo.On("GetAttachment", mock.Anything, "test", "a1").Return(attachment, nil).Twice()
+	repo.On("GetAttachment", mock.Anything, "test", "a2").Return(lost, nil).Once()
+	repo.On("GetAttachment", mock.Anything, "test", "a3").Return(tasktodo.Attachment{}, errors.New(pgrepo.InvalidAttachmentIdErr)).Once()
+	suite.service = suite.attachmentService(blobs)
+
+	testCases := []struct {
+		attachmentID string
+		noneMatch    string
+		expectedCode int
+		expectedResp string
+	}{
+		{attachmentID: "a1", expectedCode: http.StatusOK, expectedResp: attachmentContent},
+		{attachmentID: "a1", noneMatch: `"` + key + `"`, expectedCode: http.StatusNotModified},
+		{attachmentID: "a2", expectedCode: http.StatusNotFound, expectedResp: `{"message":"invalid attachment id"}` + "\n"},
+		{attachmentID: "a3", expectedCode: http.StatusNotFound, expectedResp: `{"message":"invalid attachment id"}` + "\n"},
+	}
+	for _, tc := range testCases {
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("aid", tc.attachmentID)
+		req := newRequest("GET", "/task/test/attachments/"+tc.attachmentID, nil)
+		if tc.noneMatch != "" {
+			req.Header.Set("If-None-Match", tc.noneMatch)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.DownloadAttachment(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code, tc.attachmentID)
+		suite.Equal(tc.expectedResp, w.Body.String(), tc.attachmentID)
+		if tc.expectedCode == http.StatusOK {
+			suite.Equal("text/plain", w.Header().Get("Content-Type"))
+			suite.Equal(`attachment; filename="app log.txt"`, w.Header().Get("Content-Disposition"))
+			suite.Equal(`"`+key+`"`, w.Header().Get("ETag"))
+			suite.Equal("43", w.Header().Get("Content-Length"))
+		}
+	}
+}
+
+func (suite *UnitTestSuite) TestAttachmentHandlers() {
+	attachment := tasktodo.Attachment{ID: "a1", TaskID: "test", Name: "app.log", ContentType: "text/plain", Size: 3,
+		SHA256: strings.Repeat("0", 64), UploaderID: "user", CreatedAt: time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)}
+	testCases := []struct {
+		handler       func(s httpchi.Service) http.HandlerFunc
+		storageOutput func()
+		expectedCode  int
+		expectedResp  string
+	}{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListAttachments },
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListAttachments", mock.Anything, "test").Return([]tasktodo.Attachment{attachment}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `[{"id":"a1","task_id":"test","name":"app.log","content_type":"text/plain","size":3,` +
+				`"sha256":"` + attachment.SHA256 + `","uploader_id":"user","created_at":"2024-10-20T12:00:00Z"}]`,
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListAttachments },
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListAttachments", mock.Anything, "test").Return(nil, errors.New(pgrepo.InvalidIdErr)).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"invalid task id"}`,
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteAttachment },
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("DeleteAttachment", mock.Anything, "test", "a1").Return(nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"message":"success"}`,
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteAttachment },
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("DeleteAttachment", mock.Anything, "test", "a1").Return(errors.New(pgrepo.InvalidAttachmentIdErr)).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"invalid attachment id"}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("aid", "a1")
+		req := newRequest("GET", "/task/test/attachments", nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
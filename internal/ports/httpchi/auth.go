@@ -0,0 +1,231 @@
+package httpchi
+
+import (
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"net/http"
+	"strings"
+)
+
+// RefreshRequest carries a refresh token to be exchanged for a new pair of tokens.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Register creates a new user account.
+//
+//	@Summary		Registers a new user
+//	@Description	Creates a user account, the password is stored as a bcrypt hash
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			credentials	body		account.Credentials	true	"Email and password of the new user"
+//	@Success		201			{object}	account.User		"User successfully registered"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON"
+//	@Failure		409			{object}	ErrResp				"Email already registered"
+//	@Failure		422			{object}	ErrResp				"Invalid email or password length"
+//	@Router			/auth/register [post]
+func (s Service) Register(w http.ResponseWriter, r *http.Request) {
+	log := *zerolog.Ctx(r.Context())
+	cred, ok := decodeCredentials(w, r, log)
+	if !ok {
+		return
+	}
+	user, err := account.NewUser(cred)
+	if err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	if err = s.Users.CreateUser(r.Context(), user); err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	log.Info().Str("user_id", user.ID).Msg("user registered successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, user)
+}
+
+// Login issues tokens for valid credentials.
+//
+//	@Summary		Logs a user in
+//	@Description	Checks the credentials and issues a short-lived access token and a long-lived refresh token
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			credentials	body		account.Credentials	true	"Email and password of the user"
+//	@Success		200			{object}	account.Tokens		"Tokens issued"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp				"Invalid email or password"
+//	@Failure		422			{object}	ErrResp				"Invalid email or password length"
+//	@Router			/auth/login [post]
+func (s Service) Login(w http.ResponseWriter, r *http.Request) {
+	log := *zerolog.Ctx(r.Context())
+	cred, ok := decodeCredentials(w, r, log)
+	if !ok {
+		return
+	}
+	user, err := s.Users.GetUserByEmail(r.Context(), cred.Email)
+	if err != nil {
+		if err.Error() == account.CredentialsErr {
+			// an unknown email takes as long as a wrong password
+			_ = account.UnknownUser.CheckPassword(cred.Password)
+		}
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	if err = user.CheckPassword(cred.Password); err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	s.issueTokens(w, r, log, user.ID)
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens.
+//
+//	@Summary		Refreshes tokens
+//	@Description	Issues a new pair of tokens for a valid refresh token of an existing user
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refresh	body		RefreshRequest	true	"Refresh token"
+//	@Success		200		{object}	account.Tokens	"Tokens issued"
+//	@Failure		400		{object}	ErrResp			"Incorrect JSON"
+//	@Failure		401		{object}	ErrResp			"Invalid token"
+//	@Router			/auth/refresh [post]
+func (s Service) Refresh(w http.ResponseWriter, r *http.Request) {
+	log := *zerolog.Ctx(r.Context())
+	refresh := RefreshRequest{}
+	if err := render.DecodeJSON(r.Body, &refresh); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	userID, err := s.Tokens.Parse(refresh.RefreshToken, account.RefreshToken)
+	if err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	// the user may have been removed since the token was issued
+	if _, err = s.Users.GetUser(r.Context(), userID); err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	s.issueTokens(w, r, log, userID)
+}
+
+// Authenticate lets through requests bearing a valid access token or API key and puts the caller in their context.
+// API keys are accepted both in the X-API-Key header and as a bearer credential.
+func (s Service) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := *zerolog.Ctx(r.Context())
+		bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		bearer = strings.TrimSpace(bearer)
+		key := r.Header.Get("X-API-Key")
+		if key == "" && found && account.IsAPIKey(bearer) {
+			key = bearer
+		}
+		var principal account.Principal
+		var err error
+		switch {
+		case key != "":
+			principal, err = s.keyPrincipal(r, key)
+		case found:
+			principal.UserID, err = s.Tokens.Parse(bearer, account.AccessToken)
+			principal.Scopes = account.SessionScopes
+		default:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			NewErr("Authorization", "", "missing credentials").Send(w, r, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Send()
+			if msg := err.Error(); msg != account.TokenErr && msg != account.InvalidKeyErr {
+				NewErr("", "", "action fail").Send(w, r, http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			NewErr("Authorization", "", err.Error()).Send(w, r, http.StatusUnauthorized)
+			return
+		}
+		ctx := account.WithPrincipal(r.Context(), principal)
+		logCtx := log.With().Str("user_id", principal.UserID)
+		if principal.KeyID != "" {
+			logCtx = logCtx.Str("key_id", principal.KeyID)
+		}
+		ctx = logCtx.Logger().WithContext(ctx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// keyPrincipal resolves an API key and records its use, a failed record does not fail the request.
+func (s Service) keyPrincipal(r *http.Request, secret string) (account.Principal, error) {
+	key, err := s.Users.GetAPIKeyByHash(r.Context(), account.HashKey(secret))
+	if err != nil {
+		return account.Principal{}, err
+	}
+	if err = s.Users.TouchAPIKey(r.Context(), key.ID); err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("key_id", key.ID).Msg("recording key use fail")
+	}
+	return key.Principal(), nil
+}
+
+// authorize reports whether the caller was granted the scope, otherwise 401 or 403 is sent.
+func authorize(w http.ResponseWriter, r *http.Request, scope account.Scope) bool {
+	principal, ok := account.FromContext(r.Context())
+	if !ok || principal.UserID == "" {
+		NewErr("Authorization", "", "missing credentials").Send(w, r, http.StatusUnauthorized)
+		return false
+	}
+	if !principal.Allows(scope) {
+		zerolog.Ctx(r.Context()).Error().Str("scope", string(scope)).Msg(account.ScopeErr)
+		NewErr("scope", string(scope), account.ScopeErr).Send(w, r, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (s Service) issueTokens(w http.ResponseWriter, r *http.Request, log zerolog.Logger, userID string) {
+	tokens, err := s.Tokens.Issue(userID)
+	if err != nil {
+		authErrorHandler(w, r, log, err)
+		return
+	}
+	log.Info().Str("user_id", userID).Msg("tokens issued successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tokens)
+}
+
+func decodeCredentials(w http.ResponseWriter, r *http.Request, log zerolog.Logger) (account.Credentials, bool) {
+	cred := account.Credentials{}
+	if err := render.DecodeJSON(r.Body, &cred); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return account.Credentials{}, false
+	}
+	cred.Email = account.NormalizeEmail(cred.Email)
+	if err := validator.New().Struct(cred); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return account.Credentials{}, false
+	}
+	return cred, true
+}
+
+func authErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, err error) {
+	log.Error().Err(err).Send()
+	switch err.Error() {
+	case account.EmailTakenErr:
+		NewErr("email", "", err.Error()).Send(w, r, http.StatusConflict)
+	case account.CredentialsErr:
+		NewErr("", "", err.Error()).Send(w, r, http.StatusUnauthorized)
+	case account.TokenErr, pgrepo.InvalidUserIdErr:
+		NewErr("refresh_token", "", account.TokenErr).Send(w, r, http.StatusUnauthorized)
+	case account.InvalidKeyErr:
+		NewMsg(err.Error()).Send(w, r, http.StatusNotFound)
+	default:
+		NewErr("", "", "action fail").Send(w, r, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,177 @@
+package httpchi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) newIssuer() account.Issuer {
+	issuer, err := account.NewIssuer(config.AuthCfg{Secret: "secret", AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	suite.Require().NoError(err)
+	return issuer
+}
+
+func (suite *UnitTestSuite) TestAuthHandlers() {
+	type authTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	issuer := suite.newIssuer()
+	users := mocks.NewUserRepo(suite.T())
+	user, err := account.NewUser(account.Credentials{Email: "user@example.com", Password: "password"})
+	suite.Require().NoError(err)
+	tokens, err := issuer.Issue(user.ID)
+	suite.Require().NoError(err)
+	byEmail := mock.MatchedBy(func(u account.User) bool { return u.Email == "user@example.com" })
+	testCases := []authTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Register },
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("CreateUser", mock.Anything, byEmail).Return(errors.New(account.EmailTakenErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"email","error":"email already registered"}`,
+				reqBody:      `{"email":" User@Example.com","password":"password"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Register },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"email":"user@example.com","password":"short"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Login },
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("GetUserByEmail", mock.Anything, "user@example.com").Return(user, nil).Once()
+				},
+				expectedCode: http.StatusUnauthorized,
+				expectedResp: `{"error":"invalid email or password"}`,
+				reqBody:      `{"email":"user@example.com","password":"wrong password"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Login },
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(account.User{}, errors.New(account.CredentialsErr)).Once()
+				},
+				expectedCode: http.StatusUnauthorized,
+				expectedResp: `{"error":"invalid email or password"}`,
+				reqBody:      `{"email":"nobody@example.com","password":"password"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Refresh },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnauthorized,
+				expectedResp:  `{"param":"refresh_token","error":"invalid token"}`,
+				reqBody:       `{"refresh_token":"` + tokens.AccessToken + `"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.Refresh },
+			TestCase: TestCase{
+				storageOutput: func() {
+					users.On("GetUser", mock.Anything, user.ID).Return(account.User{}, errors.New(pgrepo.InvalidUserIdErr)).Once()
+				},
+				expectedCode: http.StatusUnauthorized,
+				expectedResp: `{"param":"refresh_token","error":"invalid token"}`,
+				reqBody:      `{"refresh_token":"` + tokens.RefreshToken + `"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(users, issuer))
+		req := httptest.NewRequest("POST", "/auth", strings.NewReader(tc.reqBody))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestAuthTokens() {
+	issuer := suite.newIssuer()
+	users := mocks.NewUserRepo(suite.T())
+	user, err := account.NewUser(account.Credentials{Email: "user@example.com", Password: "password"})
+	suite.Require().NoError(err)
+	users.On("CreateUser", mock.Anything, mock.Anything).Return(nil).Once()
+	users.On("GetUserByEmail", mock.Anything, "user@example.com").Return(user, nil).Once()
+	users.On("GetUser", mock.Anything, user.ID).Return(user, nil).Once()
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(users, issuer))
+
+	w := httptest.NewRecorder()
+	suite.service.Register(w, httptest.NewRequest("POST", "/auth/register", strings.NewReader(`{"email":"user@example.com","password":"password"}`)))
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.NotContains(w.Body.String(), "password")
+
+	var tokens account.Tokens
+	w = httptest.NewRecorder()
+	suite.service.Login(w, httptest.NewRequest("POST", "/auth/login", strings.NewReader(`{"email":"User@example.com","password":"password"}`)))
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &tokens))
+	suite.Equal("Bearer", tokens.TokenType)
+
+	w = httptest.NewRecorder()
+	suite.service.Refresh(w, httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(`{"refresh_token":"`+tokens.RefreshToken+`"}`)))
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &tokens))
+	userID, err := issuer.Parse(tokens.AccessToken, account.AccessToken)
+	suite.NoError(err)
+	suite.Equal(user.ID, userID)
+}
+
+func (suite *UnitTestSuite) TestAuthenticate() {
+	issuer := suite.newIssuer()
+	tokens, err := issuer.Issue("owner")
+	suite.Require().NoError(err)
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(mocks.NewUserRepo(suite.T()), issuer))
+	router := httpchi.NewRouter(suite.service, zerolog.Nop())
+	owned := mock.MatchedBy(func(ctx context.Context) bool { return account.UserID(ctx) == "owner" })
+	suite.storage.(*mocks.Repo).On("ListTags", owned).Return([]tasktodo.Tag{}, nil).Once()
+
+	testCases := []struct {
+		header       string
+		expectedCode int
+		expectedResp string
+	}{
+		{header: "", expectedCode: http.StatusUnauthorized, expectedResp: `{"param":"Authorization","error":"missing credentials"}`},
+		{header: "Bearer " + tokens.RefreshToken, expectedCode: http.StatusUnauthorized, expectedResp: `{"param":"Authorization","error":"invalid token"}`},
+		{header: "Bearer " + tokens.AccessToken, expectedCode: http.StatusOK, expectedResp: `[]`},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", "/api/tags", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
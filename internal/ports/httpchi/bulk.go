@@ -0,0 +1,243 @@
+package httpchi
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BulkTasks applies a batch of task operations.
+//
+//	@Summary		Applies a batch of task operations
+//	@Description	Creates, updates (as PUT does), deletes and completes tasks in a single transaction, every operation is checked as by its own endpoint.
+//	@Description	An atomic batch is applied as a whole: the first failing operation is reported with its status, the error param points at the operation, and nothing is changed.
+//	@Description	Otherwise every operation is applied or fails on its own and the response lists the results with the status each operation would get from its own endpoint.
+//	@Description	A task can be the target of one operation per batch only.
+//	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bulkRequest	body		tasktodo.BulkRequest	true	"Operations to apply"
+//	@Success		200			{object}	BulkResp				"Results of the operations"
+//	@Failure		400			{object}	ErrResp					"Incorrect JSON or invalid date format of an atomic batch"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Scope tasks:write, or tasks:delete for delete operations, not granted"
+//	@Failure		404			{object}	ErrResp					"Task of an atomic batch not found"
+//	@Failure		409			{object}	ErrResp					"Operation of an atomic batch conflicts with the task"
+//	@Failure		412			{object}	ErrResp					"Task version of an atomic batch does not match"
+//	@Failure		422			{object}	ErrResp					"Invalid JSON, too many operations, task changed more than once or invalid operation of an atomic batch"
+//	@Router			/tasks/bulk [post]
+func (s Service) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	bulk := tasktodo.BulkRequest{}
+	log := *zerolog.Ctx(r.Context())
+	if err := render.DecodeJSON(r.Body, &bulk); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(bulk); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	if len(bulk.Operations) > s.BulkLimit {
+		log.Warn().Int("amount", len(bulk.Operations)).Msg(tasktodo.BulkLimitErr)
+		NewErr("operations", strconv.Itoa(len(bulk.Operations)), fmt.Sprintf("%s, at most %d", tasktodo.BulkLimitErr, s.BulkLimit)).
+			Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	var deleting bool
+	targets := make(map[string]bool, len(bulk.Operations))
+	for _, op := range bulk.Operations {
+		deleting = deleting || op.Action == tasktodo.BulkDelete
+		if op.ID == "" {
+			continue
+		}
+		if targets[op.ID] {
+			log.Warn().Str("id", op.ID).Msg(tasktodo.BulkDuplicateErr)
+			NewErr("id", op.ID, tasktodo.BulkDuplicateErr).Send(w, r, http.StatusUnprocessableEntity)
+			return
+		}
+		targets[op.ID] = true
+	}
+	if deleting && !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log.Info().Int("amount", len(bulk.Operations)).Bool("atomic", bulk.Atomic).Msg("request body decoded")
+
+	results := make([]BulkResult, len(bulk.Operations))
+	ready := make([]tasktodo.BulkOp, 0, len(bulk.Operations))
+	// indexes maps the operations ready to be applied to their results
+	indexes := make([]int, 0, len(bulk.Operations))
+	for i, op := range bulk.Operations {
+		results[i] = BulkResult{Index: i, Action: op.Action, ID: op.ID}
+		prepared, status, errResp, err := s.prepareOp(r, op)
+		if err != nil {
+			log.Warn().Err(err).Int("index", i).Send()
+			if bulk.Atomic {
+				operationErr(i, errResp).Send(w, r, status)
+				return
+			}
+			results[i].Status, results[i].Error = status, &errResp
+			continue
+		}
+		ready = append(ready, prepared)
+		indexes = append(indexes, i)
+	}
+
+	var outcomes []tasktodo.BulkOutcome
+	if len(ready) != 0 {
+		var err error
+		if outcomes, err = s.DB.ApplyBulk(r.Context(), ready, bulk.Atomic); err != nil {
+			var opErr tasktodo.OperationError
+			if !errors.As(err, &opErr) {
+				errorHandler(w, r, log, "", "", err)
+				return
+			}
+			i := indexes[opErr.Index]
+			log.Warn().Err(err).Int("index", i).Send()
+			status, errResp := opErrorResponse(r, bulk.Operations[i], opErr.Err)
+			operationErr(i, errResp).Send(w, r, status)
+			return
+		}
+	}
+
+	resp := BulkResp{Results: results}
+	for j := range outcomes {
+		outcome := &outcomes[j]
+		result := &resp.Results[indexes[j]]
+		if outcome.Err != nil {
+			log.Warn().Err(outcome.Err).Int("index", result.Index).Send()
+			status, errResp := opErrorResponse(r, bulk.Operations[result.Index], outcome.Err)
+			result.Status, result.Error = status, &errResp
+			continue
+		}
+		result.Status = http.StatusOK
+		switch result.Action {
+		case tasktodo.BulkCreate:
+			result.Status, result.ID = http.StatusCreated, outcome.Task.ID
+			result.Task = &outcome.Task
+		case tasktodo.BulkUpdate, tasktodo.BulkComplete:
+			result.Task = &outcome.Task
+		}
+	}
+	for _, result := range resp.Results {
+		if result.Error != nil {
+			resp.Failed++
+			continue
+		}
+		resp.Applied++
+	}
+	log.Info().Int("applied", resp.Applied).Int("failed", resp.Failed).Msg("bulk request applied")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// prepareOp checks an operation of a bulk request as the endpoint of its action does and turns it into
+// an operation ready to be applied. A failed check comes with the status and the body of its response.
+func (s Service) prepareOp(r *http.Request, op tasktodo.BulkOperation) (tasktodo.BulkOp, int, ErrResp, error) {
+	prepared := tasktodo.BulkOp{Action: op.Action, TaskID: op.ID, Version: op.Version}
+	if op.Task != nil {
+		if err := validateDate(op.Task.DueDate); err != nil {
+			return tasktodo.BulkOp{}, http.StatusBadRequest, NewErr("date", op.Task.DueDate, "bad date format"), err
+		}
+		task := *op.Task
+		task.Tags = tasktodo.NormalizeTags(task.Tags)
+		op.Task = &task
+	}
+	if err := validator.New().Struct(op); err != nil {
+		return tasktodo.BulkOp{}, http.StatusUnprocessableEntity, NewErr("", "", "invalid JSON"), err
+	}
+	if op.Task != nil {
+		if err := validateRecurrence(op.Task.Recurrence); err != nil {
+			return tasktodo.BulkOp{}, http.StatusUnprocessableEntity, NewErr("recurrence", *op.Task.Recurrence, tasktodo.RecurrenceErr), err
+		}
+		prepared.Task = *op.Task
+	}
+
+	var current tasktodo.Task
+	switch op.Action {
+	case tasktodo.BulkDelete:
+		return prepared, 0, ErrResp{}, nil
+	case tasktodo.BulkUpdate, tasktodo.BulkComplete:
+		var err error
+		if current, err = s.DB.GetTask(r.Context(), op.ID); err == nil && op.Version != 0 && op.Version != current.Version {
+			err = errors.New(pgrepo.VersionErr)
+		}
+		if err != nil {
+			status, errResp := opErrorResponse(r, op, err)
+			return tasktodo.BulkOp{}, status, errResp, err
+		}
+		// the write is bound to the version that has been checked
+		prepared.Version = current.Version
+	}
+
+	if op.Action == tasktodo.BulkComplete {
+		if current.State != tasktodo.StateDone {
+			if err := s.Workflow.Check(current.State, tasktodo.StateDone); err != nil {
+				status, errResp := stateErrorResponse(tasktodo.StateDone, err)
+				return tasktodo.BulkOp{}, status, errResp, err
+			}
+			done, state := true, tasktodo.StateDone
+			prepared.Patch = tasktodo.Patch{Status: &done, State: &state}
+		}
+		return prepared, 0, ErrResp{}, nil
+	}
+
+	task := &prepared.Task
+	if err := s.DueDates.Check(task.DueDate, task.TimeZone, current.DueDate, time.Now()); err != nil {
+		status, errResp := opErrorResponse(r, op, err)
+		return tasktodo.BulkOp{}, status, errResp, err
+	}
+	state, err := s.Workflow.Resolve(current.State, task.State, task.Status)
+	if err != nil {
+		status, errResp := stateErrorResponse(task.State, err)
+		return tasktodo.BulkOp{}, status, errResp, err
+	}
+	if op.Action == tasktodo.BulkUpdate {
+		if err = s.Workflow.Check(current.State, state); err != nil {
+			status, errResp := stateErrorResponse(state, err)
+			return tasktodo.BulkOp{}, status, errResp, err
+		}
+	}
+	task.SetState(state)
+	return prepared, 0, ErrResp{}, nil
+}
+
+// opErrorResponse maps an error of a bulk operation to the status and the body of its response,
+// the expected version of the task is given by the operation rather than by the headers.
+func opErrorResponse(r *http.Request, op tasktodo.BulkOperation, err error) (int, ErrResp) {
+	if err.Error() == pgrepo.VersionErr {
+		if op.Version == 0 {
+			return http.StatusConflict, NewErr("id", op.ID, "concurrent update")
+		}
+		return http.StatusPreconditionFailed, NewErr("version", strconv.FormatInt(op.Version, 10), pgrepo.VersionErr)
+	}
+	var date string
+	if op.Task != nil {
+		date = op.Task.DueDate
+	}
+	return errorResponse(r, date, op.ID, err)
+}
+
+// operationErr points the error response at the operation of a bulk request.
+func operationErr(index int, errResp ErrResp) ErrResp {
+	param := fmt.Sprintf("operations[%d]", index)
+	if errResp.Param != "" {
+		param += "." + errResp.Param
+	}
+	errResp.Param = param
+	return errResp
+}
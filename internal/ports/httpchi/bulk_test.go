@@ -0,0 +1,185 @@
+package httpchi_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestBulkTasks() {
+	type bulkTestCase struct {
+		opts      []httpchi.Option
+		principal account.Principal
+		TestCase
+	}
+	current := suite.testTask
+	current.Version = 3
+	created := suite.testTask
+	created.ID = "new"
+	completed := current
+	completed.SetState(tasktodo.StateDone)
+	completed.Version = 4
+	cancelled := current
+	cancelled.ID = "cancelled"
+	cancelled.SetState(tasktodo.StateCancelled)
+
+	createReq := suite.taskReq
+	createReq.Tags = tasktodo.NormalizeTags(nil)
+	done, doneState := true, tasktodo.StateDone
+	completePatch := tasktodo.Patch{Status: &done, State: &doneState}
+	createBody := `{"action":"create","task":{"title":"test","description":"test","due_date":"2024-10-26","status":false}}`
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":true,"state":"done","tags":[],"version":4}`
+	writer := account.Principal{UserID: "user", KeyID: "key", Scopes: []account.Scope{account.ScopeTasksWrite}}
+
+	testCases := []bulkTestCase{
+		{
+			opts: []httpchi.Option{anyDueDate},
+			TestCase: TestCase{
+				testName: "best effort",
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "missing").Return(tasktodo.Task{}, errors.New(pgrepo.InvalidIdErr)).Once()
+					suite.storage.(*mocks.Repo).On("ApplyBulk", mock.Anything, []tasktodo.BulkOp{
+						{Action: tasktodo.BulkCreate, Task: createReq},
+						{Action: tasktodo.BulkComplete, TaskID: "test", Version: 3, Patch: completePatch},
+						{Action: tasktodo.BulkDelete, TaskID: "gone", Version: 2},
+					}, false).Return([]tasktodo.BulkOutcome{
+						{Task: created},
+						{Task: completed},
+						{Err: errors.New(pgrepo.InvalidIdErr)},
+					}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"applied":2,"failed":2,"results":[` +
+					`{"index":0,"action":"create","id":"new","status":201,"task":{"id":"new","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}},` +
+					`{"index":1,"action":"update","id":"missing","status":404,"error":{"param":"id","value":"missing","error":"invalid task id"}},` +
+					`{"index":2,"action":"complete","id":"test","status":200,"task":` + taskResp + `},` +
+					`{"index":3,"action":"delete","id":"gone","status":404,"error":{"param":"id","value":"gone","error":"invalid task id"}}]}`,
+				reqBody: `{"operations":[` + createBody + `,{"action":"update","id":"missing","task":{"title":"test","description":"test","due_date":"2024-10-26","state":"todo"}},` +
+					`{"action":"complete","id":"test"},{"action":"delete","id":"gone","version":2}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName: "operations failing checks are not applied",
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "cancelled").Return(cancelled, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"applied":0,"failed":4,"results":[` +
+					`{"index":0,"action":"create","status":422,"error":{"param":"due_date","value":"2024-10-26","error":"due date is in the past"}},` +
+					`{"index":1,"action":"complete","status":422,"error":{"error":"invalid JSON"}},` +
+					`{"index":2,"action":"complete","id":"cancelled","status":409,"error":{"param":"state","value":"done","error":"transition not allowed"}},` +
+					`{"index":3,"action":"create","status":400,"error":{"param":"date","value":"26.10.2024","error":"bad date format"}}]}`,
+				reqBody: `{"operations":[` + createBody + `,{"action":"complete"},{"action":"complete","id":"cancelled"},` +
+					`{"action":"create","task":{"title":"test","due_date":"26.10.2024","status":false}}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName: "atomic batch stops at a failed check",
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+				},
+				expectedCode: http.StatusPreconditionFailed,
+				expectedResp: `{"param":"operations[1].version","value":"2","error":"version mismatch"}`,
+				reqBody:      `{"atomic":true,"operations":[{"action":"delete","id":"gone"},{"action":"complete","id":"test","version":2}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName: "atomic batch rolled back",
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+					suite.storage.(*mocks.Repo).On("ApplyBulk", mock.Anything, []tasktodo.BulkOp{
+						{Action: tasktodo.BulkDelete, TaskID: "gone"},
+						{Action: tasktodo.BulkComplete, TaskID: "test", Version: 3, Patch: completePatch},
+					}, true).Return(nil, tasktodo.OperationError{Index: 1, Err: errors.New(pgrepo.BlockedErr)}).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"operations[1].state","value":"done","error":"task has open blockers"}`,
+				reqBody:      `{"atomic":true,"operations":[{"action":"delete","id":"gone"},{"action":"complete","id":"test","version":3}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName: "transaction failure",
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ApplyBulk", mock.Anything, []tasktodo.BulkOp{{Action: tasktodo.BulkDelete, TaskID: "gone"}}, false).
+						Return(nil, errors.New("any error")).Once()
+				},
+				expectedCode: http.StatusInternalServerError,
+				expectedResp: `{"param":"id","error":"action fail"}`,
+				reqBody:      `{"operations":[{"action":"delete","id":"gone"}]}`,
+			},
+		},
+		{
+			opts: []httpchi.Option{httpchi.WithBulkLimit(1)},
+			TestCase: TestCase{
+				testName:      "too many operations",
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"operations","value":"2","error":"too many operations, at most 1"}`,
+				reqBody:       `{"operations":[{"action":"delete","id":"a"},{"action":"delete","id":"b"}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName:      "task changed twice",
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"id","value":"test","error":"task changed more than once"}`,
+				reqBody:       `{"operations":[{"action":"complete","id":"test"},{"action":"delete","id":"test"}]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName:      "no operations",
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"operations":[]}`,
+			},
+		},
+		{
+			TestCase: TestCase{
+				testName:      "bad JSON",
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"error":"bad JSON"}`,
+				reqBody:       `{"operations":`,
+			},
+		},
+		{
+			principal: writer,
+			TestCase: TestCase{
+				testName:      "delete needs its scope",
+				storageOutput: func() {},
+				expectedCode:  http.StatusForbidden,
+				expectedResp:  `{"param":"scope","value":"tasks:delete","error":"insufficient scope"}`,
+				reqBody:       `{"operations":[{"action":"delete","id":"test"}]}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, tc.opts...)
+		req := newRequest("POST", "/tasks/bulk", strings.NewReader(tc.reqBody))
+		if tc.principal.UserID != "" {
+			req = req.WithContext(account.WithPrincipal(req.Context(), tc.principal))
+		}
+		w := httptest.NewRecorder()
+
+		suite.service.BulkTasks(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code, tc.testName)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()), tc.testName)
+	}
+}
@@ -0,0 +1,258 @@
+package httpchi
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strings"
+)
+
+// GetChecklist returns the checklist of a task.
+//
+//	@Summary		Returns the checklist of a task
+//	@Description	Retrieves checklist items of a task in their order together with the progress like "3/5"
+//	@Tags			Checklist
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string				true	"Task ID"
+//	@Success		200	{object}	tasktodo.Checklist	"Checklist of the task"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp				"Task not found"
+//	@Router			/task/{id}/checklist [get]
+func (s Service) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	checklist, err := s.DB.GetChecklist(r.Context(), taskID)
+	if err != nil {
+		checklistErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("progress", checklist.Progress).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, checklist)
+}
+
+// AddChecklistItem appends an item to the checklist of a task.
+//
+//	@Summary		Adds a checklist item
+//	@Description	Appends an unchecked item to the end of the checklist of a task
+//	@Tags			Checklist
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string							true	"Task ID"
+//	@Param			itemRequest	body		tasktodo.ChecklistItemRequest	true	"Item text"
+//	@Success		201			{object}	tasktodo.Checklist				"Checklist with the new item"
+//	@Failure		400			{object}	ErrResp							"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp							"Missing credentials"
+//	@Failure		403			{object}	ErrResp							"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp							"Task not found"
+//	@Failure		422			{object}	ErrResp							"Invalid JSON"
+//	@Router			/task/{id}/checklist [post]
+func (s Service) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	itemRequest := tasktodo.ChecklistItemRequest{}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if err := render.DecodeJSON(r.Body, &itemRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	itemRequest.Text = strings.TrimSpace(itemRequest.Text)
+	if err := validator.New().Struct(itemRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	checklist, err := s.DB.AddChecklistItem(r.Context(), taskID, itemRequest)
+	if err != nil {
+		checklistErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("progress", checklist.Progress).Msg("checklist item added successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, checklist)
+}
+
+// ReorderChecklist changes the order of checklist items of a task.
+//
+//	@Summary		Reorders checklist items
+//	@Description	Puts checklist items of a task in the order of the given IDs, every item has to be listed exactly once
+//	@Tags			Checklist
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Task ID"
+//	@Param			order	body		tasktodo.ChecklistOrder	true	"Item IDs in the new order"
+//	@Success		200		{object}	tasktodo.Checklist		"Reordered checklist"
+//	@Failure		400		{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401		{object}	ErrResp					"Missing credentials"
+//	@Failure		403		{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404		{object}	MsgResp					"Task not found"
+//	@Failure		422		{object}	ErrResp					"Invalid JSON or the IDs do not match the items"
+//	@Router			/task/{id}/checklist/order [put]
+func (s Service) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	order := tasktodo.ChecklistOrder{}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if err := render.DecodeJSON(r.Body, &order); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(order); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	checklist, err := s.DB.ReorderChecklist(r.Context(), taskID, order.IDs)
+	if err != nil {
+		checklistErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Int("amount", checklist.Total).Msg("checklist reordered successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, checklist)
+}
+
+// ToggleChecklistItem checks or unchecks a checklist item.
+//
+//	@Summary		Checks or unchecks a checklist item
+//	@Description	Sets whether a checklist item is done. With complete set the task is moved to the done state as well
+//	@Description	once every item is checked, unless the workflow does not allow it or the task has open blockers.
+//	@Description	The completed task is returned along with the checklist
+//	@Tags			Checklist
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Task ID"
+//	@Param			iid		path		string						true	"Checklist item ID"
+//	@Param			toggle	body		tasktodo.ChecklistToggle	true	"Item state"
+//	@Success		200		{object}	ChecklistResp				"Checklist with the item changed"
+//	@Failure		400		{object}	ErrResp						"Incorrect JSON"
+//	@Failure		401		{object}	ErrResp						"Missing credentials"
+//	@Failure		403		{object}	ErrResp						"Scope tasks:write not granted"
+//	@Failure		404		{object}	MsgResp						"Task or checklist item not found"
+//	@Failure		422		{object}	ErrResp						"Invalid JSON"
+//	@Router			/task/{id}/checklist/{iid} [patch]
+func (s Service) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	toggle := tasktodo.ChecklistToggle{}
+	log := *zerolog.Ctx(r.Context())
+	taskID, itemID := chi.URLParam(r, "id"), chi.URLParam(r, "iid")
+	log.Info().Str("id", taskID).Str("item_id", itemID).Msg("checklist item id received")
+	if err := render.DecodeJSON(r.Body, &toggle); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(toggle); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Str("item_id", itemID).Logger()
+	version, err := s.completeVersion(r.Context(), taskID, toggle.Complete)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	checklist, task, err := s.DB.ToggleChecklistItem(r.Context(), taskID, itemID, *toggle.Done, version)
+	if err != nil {
+		checklistErrorHandler(w, r, logID, taskID, err)
+		return
+	}
+	resp := ChecklistResp{Checklist: checklist, Task: task}
+	if task != nil {
+		logID.Info().Msg("task completed by checklist")
+	}
+	logID.Info().Bool("done", *toggle.Done).Str("progress", checklist.Progress).Msg("checklist item toggled successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// RemoveChecklistItem removes an item from the checklist of a task.
+//
+//	@Summary		Removes a checklist item
+//	@Description	Removes an item from the checklist of a task, the order of the other items is kept
+//	@Tags			Checklist
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string				true	"Task ID"
+//	@Param			iid	path		string				true	"Checklist item ID"
+//	@Success		200	{object}	tasktodo.Checklist	"Checklist without the item"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp				"Task or checklist item not found"
+//	@Router			/task/{id}/checklist/{iid} [delete]
+func (s Service) RemoveChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID, itemID := chi.URLParam(r, "id"), chi.URLParam(r, "iid")
+	log.Info().Str("id", taskID).Str("item_id", itemID).Msg("checklist item id received")
+	checklist, err := s.DB.RemoveChecklistItem(r.Context(), taskID, itemID)
+	if err != nil {
+		checklistErrorHandler(w, r, log.With().Str("id", taskID).Str("item_id", itemID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("progress", checklist.Progress).Msg("checklist item removed successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, checklist)
+}
+
+// completeVersion returns the version of the task to be completed by its checklist, or 0 if it is not asked for,
+// the task is done already or the workflow does not allow moving it to the done state.
+func (s Service) completeVersion(ctx context.Context, taskID string, asked bool) (int64, error) {
+	if !asked {
+		return 0, nil
+	}
+	current, err := s.DB.GetTask(ctx, taskID)
+	if err != nil {
+		return 0, err
+	}
+	if current.State == tasktodo.StateDone || s.Workflow.Check(current.State, tasktodo.StateDone) != nil {
+		return 0, nil
+	}
+	return current.Version, nil
+}
+
+func checklistErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, taskID string, err error) {
+	switch err.Error() {
+	case pgrepo.InvalidChecklistItemIdErr:
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidChecklistItemIdErr).Send(w, r, http.StatusNotFound)
+	case pgrepo.ChecklistOrderErr:
+		log.Warn().Err(err).Send()
+		NewErr("ids", "", pgrepo.ChecklistOrderErr).Send(w, r, http.StatusUnprocessableEntity)
+	default:
+		errorHandler(w, r, log, "", taskID, err)
+	}
+}
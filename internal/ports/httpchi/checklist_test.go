@@ -0,0 +1,267 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestChecklistHandlers() {
+	type checklistTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	created := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	milk := tasktodo.ChecklistItem{ID: "i1", Text: "milk", CreatedAt: created}
+	bread := tasktodo.ChecklistItem{ID: "i2", Text: "bread", Done: true, CreatedAt: created}
+	milkResp := `{"id":"i1","text":"milk","done":false,"created_at":"2024-10-20T12:00:00Z"}`
+	breadResp := `{"id":"i2","text":"bread","done":true,"created_at":"2024-10-20T12:00:00Z"}`
+	testCases := []checklistTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.GetChecklist },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"items":[],"done":0,"total":0,"progress":"0/0"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("AddChecklistItem", mock.Anything, "test", tasktodo.ChecklistItemRequest{Text: "milk"}).
+						Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{bread, milk}), nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: `{"items":[` + breadResp + `,` + milkResp + `],"done":1,"total":2,"progress":"1/2"}`,
+				reqBody:      `{"text":" milk "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"text":"  "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("AddChecklistItem", mock.Anything, "test", tasktodo.ChecklistItemRequest{Text: "milk"}).
+						Return(tasktodo.Checklist{}, errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid task id"}`,
+				reqBody:      `{"text":"milk"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ReorderChecklist },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ReorderChecklist", mock.Anything, "test", []string{"i1", "i2"}).
+						Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{milk, bread}), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"items":[` + milkResp + `,` + breadResp + `],"done":1,"total":2,"progress":"1/2"}`,
+				reqBody:      `{"ids":["i1","i2"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ReorderChecklist },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"ids":["i1","i1"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ReorderChecklist },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ReorderChecklist", mock.Anything, "test", []string{"i1"}).
+						Return(tasktodo.Checklist{}, errors.New(pgrepo.ChecklistOrderErr)).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"ids","error":"order must list every checklist item once"}`,
+				reqBody:      `{"ids":["i1"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ToggleChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					task := suite.testTask
+					task.Version = 1
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(task, nil).Once()
+					suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", false, int64(1)).
+						Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{milk, bread}), (*tasktodo.Task)(nil), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"items":[` + milkResp + `,` + breadResp + `],"done":1,"total":2,"progress":"1/2"}`,
+				reqBody:      `{"done":false,"complete":true}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ToggleChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"complete":true}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ToggleChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(0)).
+						Return(tasktodo.Checklist{}, (*tasktodo.Task)(nil), errors.New(pgrepo.InvalidChecklistItemIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid checklist item id"}`,
+				reqBody:      `{"done":true}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RemoveChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("RemoveChecklistItem", mock.Anything, "test", "i1").
+						Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{bread}), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"items":[` + breadResp + `],"done":1,"total":1,"progress":"1/1"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RemoveChecklistItem },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("RemoveChecklistItem", mock.Anything, "test", "i1").
+						Return(tasktodo.Checklist{}, errors.New(pgrepo.InvalidChecklistItemIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid checklist item id"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("iid", "i1")
+		req := newRequest("POST", "/task/test/checklist", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestToggleChecklistComplete() {
+	item := tasktodo.ChecklistItem{ID: "i1", Text: "milk", Done: true, CreatedAt: time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)}
+	checked := tasktodo.NewChecklist([]tasktodo.ChecklistItem{item})
+	checkedResp := `"items":[{"id":"i1","text":"milk","done":true,"created_at":"2024-10-20T12:00:00Z"}],"done":1,"total":1,"progress":"1/1"`
+	current := suite.testTask
+	current.Version = 2
+	completed := current
+	completed.SetState(tasktodo.StateDone)
+	completed.Version = 3
+	blocked := current
+	blocked.SetState(tasktodo.StateBlocked)
+	finished := current
+	finished.SetState(tasktodo.StateDone)
+	testCases := []TestCase{
+		{
+			testName: "completed",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+				suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(2)).Return(checked, &completed, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{` + checkedResp + `,"task":{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":true,"state":"done","tags":[],"version":3}}`,
+		},
+		{
+			testName: "open blockers",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+				suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(2)).Return(checked, (*tasktodo.Task)(nil), nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{` + checkedResp + `}`,
+		},
+		{
+			testName: "workflow forbids",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(blocked, nil).Once()
+				suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(0)).Return(checked, (*tasktodo.Task)(nil), nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{` + checkedResp + `}`,
+		},
+		{
+			testName: "done already",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(finished, nil).Once()
+				suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(0)).Return(checked, (*tasktodo.Task)(nil), nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{` + checkedResp + `}`,
+		},
+		{
+			// nothing is saved when the task can not be read
+			testName: "task read fails",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(tasktodo.Task{}, errors.New("any err")).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
+		},
+		{
+			// a failed completion rolls the toggle back as well
+			testName: "completion fails",
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+				suite.storage.(*mocks.Repo).On("ToggleChecklistItem", mock.Anything, "test", "i1", true, int64(2)).
+					Return(tasktodo.Checklist{}, (*tasktodo.Task)(nil), errors.New("any err")).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("iid", "i1")
+		req := newRequest("PATCH", "/task/test/checklist/i1", strings.NewReader(`{"done":true,"complete":true}`))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.ToggleChecklistItem(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code, tc.testName)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()), tc.testName)
+	}
+}
@@ -0,0 +1,211 @@
+package httpchi
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strings"
+)
+
+// CreateComment adds a comment to a task.
+//
+//	@Summary		Adds a comment to a task
+//	@Description	Adds a comment written in Markdown to the discussion of a task, the rendered HTML is returned along with the source
+//	@Tags			Comments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Task ID"
+//	@Param			commentRequest	body		tasktodo.CommentRequest	true	"Comment body"
+//	@Success		201				{object}	tasktodo.Comment		"Comment successfully created"
+//	@Failure		400				{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401				{object}	ErrResp					"Missing credentials"
+//	@Failure		403				{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp					"Task not found"
+//	@Failure		422				{object}	ErrResp					"Invalid JSON"
+//	@Router			/task/{id}/comments [post]
+func (s Service) CreateComment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	commentRequest, ok := decodeComment(w, r, log)
+	if !ok {
+		return
+	}
+	comment, err := s.DB.CreateComment(r.Context(), taskID, commentRequest)
+	if err != nil {
+		commentErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("comment_id", comment.ID).Msg("comment created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, comment)
+}
+
+// ListComments returns comments of a task.
+//
+//	@Summary		Returns comments of a task
+//	@Description	Retrieves comments of a task oldest first, a page at a time. The next page is asked for with the cursor of the previous one
+//	@Tags			Comments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string		true	"Task ID"
+//	@Param			limit	query		int			false	"Page size, limited by the server"
+//	@Param			cursor	query		string		false	"Opaque cursor from next_cursor of the previous page"
+//	@Success		200		{object}	CommentPage	"Page of comments"
+//	@Failure		400		{object}	ErrResp		"Invalid limit or cursor"
+//	@Failure		401		{object}	ErrResp		"Missing credentials"
+//	@Failure		403		{object}	ErrResp		"Scope tasks:read not granted"
+//	@Failure		404		{object}	MsgResp		"Task not found"
+//	@Router			/task/{id}/comments [get]
+func (s Service) ListComments(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	query := r.URL.Query()
+	limit, errResp, err := s.pageLimit(query)
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	var after *tasktodo.CommentCursor
+	if token := query.Get("cursor"); token != "" {
+		cursor, err := s.Pages.DecodeComment(token)
+		if err != nil {
+			log.Error().Err(err).Send()
+			NewErr("cursor", "", tasktodo.CursorErr).Send(w, r, http.StatusBadRequest)
+			return
+		}
+		after = &cursor
+	}
+	// one extra comment tells whether the page is the last one
+	comments, err := s.DB.ListComments(r.Context(), taskID, limit+1, after)
+	if err != nil {
+		commentErrorHandler(w, r, log.With().Str("id", taskID).Logger(), taskID, err)
+		return
+	}
+	page := CommentPage{Comments: comments}
+	if uint(len(comments)) > limit {
+		page.Comments = comments[:limit]
+		last := page.Comments[limit-1]
+		page.NextCursor = s.Pages.EncodeComment(tasktodo.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	log.Info().Int("amount", len(page.Comments)).Bool("last", page.NextCursor == "").Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, page)
+}
+
+// UpdateComment edits a comment of a task.
+//
+//	@Summary		Edits a comment
+//	@Description	Replaces the body of a comment written by the user, the comment is marked as edited
+//	@Tags			Comments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Task ID"
+//	@Param			cid				path		string					true	"Comment ID"
+//	@Param			commentRequest	body		tasktodo.CommentRequest	true	"New comment body"
+//	@Success		200				{object}	tasktodo.Comment		"Comment successfully updated"
+//	@Failure		400				{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401				{object}	ErrResp					"Missing credentials"
+//	@Failure		403				{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp					"Comment not found"
+//	@Failure		422				{object}	ErrResp					"Invalid JSON"
+//	@Router			/task/{id}/comments/{cid} [put]
+func (s Service) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID, commentID := chi.URLParam(r, "id"), chi.URLParam(r, "cid")
+	log.Info().Str("id", taskID).Str("comment_id", commentID).Msg("comment id received")
+	commentRequest, ok := decodeComment(w, r, log)
+	if !ok {
+		return
+	}
+	comment, err := s.DB.UpdateComment(r.Context(), taskID, commentID, commentRequest)
+	if err != nil {
+		commentErrorHandler(w, r, log.With().Str("id", taskID).Str("comment_id", commentID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("comment_id", commentID).Msg("comment updated successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, comment)
+}
+
+// DeleteComment deletes a comment of a task.
+//
+//	@Summary		Deletes a comment
+//	@Description	Deletes a comment written by the user, it is no longer listed nor counted
+//	@Tags			Comments
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Task ID"
+//	@Param			cid	path		string	true	"Comment ID"
+//	@Success		200	{object}	MsgResp	"Comment successfully deleted"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"Comment not found"
+//	@Router			/task/{id}/comments/{cid} [delete]
+func (s Service) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID, commentID := chi.URLParam(r, "id"), chi.URLParam(r, "cid")
+	log.Info().Str("id", taskID).Str("comment_id", commentID).Msg("comment id received")
+	if err := s.DB.DeleteComment(r.Context(), taskID, commentID); err != nil {
+		commentErrorHandler(w, r, log.With().Str("id", taskID).Str("comment_id", commentID).Logger(), taskID, err)
+		return
+	}
+	log.Info().Str("comment_id", commentID).Msg("comment deleted successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
+
+// decodeComment reads the comment body, a body of whitespace only is not accepted.
+// Leading spaces are kept as they are meaningful in Markdown.
+func decodeComment(w http.ResponseWriter, r *http.Request, log zerolog.Logger) (tasktodo.CommentRequest, bool) {
+	commentRequest := tasktodo.CommentRequest{}
+	if err := render.DecodeJSON(r.Body, &commentRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return tasktodo.CommentRequest{}, false
+	}
+	err := validator.New().Struct(commentRequest)
+	if err == nil && strings.TrimSpace(commentRequest.Body) == "" {
+		err = errors.New("blank comment body")
+	}
+	if err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return tasktodo.CommentRequest{}, false
+	}
+	return commentRequest, true
+}
+
+func commentErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, taskID string, err error) {
+	if err.Error() == pgrepo.InvalidCommentIdErr {
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidCommentIdErr).Send(w, r, http.StatusNotFound)
+		return
+	}
+	errorHandler(w, r, log, "", taskID, err)
+}
@@ -0,0 +1,175 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestCommentHandlers() {
+	type commentTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	created := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	comment := tasktodo.Comment{ID: "c1", TaskID: "test", AuthorID: "user", Body: "*hi*", HTML: "<p><em>hi</em></p>\n", CreatedAt: created}
+	edited := comment
+	edited.EditedAt = &created
+	commentResp := `{"id":"c1","task_id":"test","author_id":"user","body":"*hi*","html":"\u003cp\u003e\u003cem\u003ehi\u003c/em\u003e\u003c/p\u003e\n",` +
+		`"created_at":"2024-10-20T12:00:00Z"`
+	testCases := []commentTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateComment", mock.Anything, "test", tasktodo.CommentRequest{Body: "*hi*"}).Return(comment, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: commentResp + `}`,
+				reqBody:      `{"body":"*hi*"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateComment", mock.Anything, "test", tasktodo.CommentRequest{Body: "*hi*"}).
+						Return(tasktodo.Comment{}, errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid task id"}`,
+				reqBody:      `{"body":"*hi*"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateComment },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"body":" \n "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateComment },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"error":"bad JSON"}`,
+				reqBody:       `{"body":`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("UpdateComment", mock.Anything, "test", "c1", tasktodo.CommentRequest{Body: "*hi*"}).Return(edited, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: commentResp + `,"edited_at":"2024-10-20T12:00:00Z"}`,
+				reqBody:      `{"body":"*hi*"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("UpdateComment", mock.Anything, "test", "c1", tasktodo.CommentRequest{Body: "*hi*"}).
+						Return(tasktodo.Comment{}, errors.New(pgrepo.InvalidCommentIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid comment id"}`,
+				reqBody:      `{"body":"*hi*"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("DeleteComment", mock.Anything, "test", "c1").Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteComment },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("DeleteComment", mock.Anything, "test", "c1").Return(errors.New(pgrepo.InvalidCommentIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid comment id"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("cid", "c1")
+		req := newRequest("POST", "/task/test/comments", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestListComments() {
+	pages := tasktodo.DefaultPaginator()
+	first := tasktodo.Comment{ID: "c1", TaskID: "test", AuthorID: "user", Body: "one", HTML: "<p>one</p>\n",
+		CreatedAt: time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)}
+	second := first
+	second.ID, second.CreatedAt = "c2", first.CreatedAt.Add(time.Minute)
+	cursor := tasktodo.CommentCursor{CreatedAt: first.CreatedAt, ID: "c1"}
+	repo := suite.storage.(*mocks.Repo)
+	repo.On("ListComments", mock.Anything, "test", uint(2), (*tasktodo.CommentCursor)(nil)).
+		Return([]tasktodo.Comment{first, second}, nil).Once()
+	repo.On("ListComments", mock.Anything, "test", uint(2), &cursor).Return([]tasktodo.Comment{second}, nil).Once()
+	repo.On("ListComments", mock.Anything, "missing", uint(11), (*tasktodo.CommentCursor)(nil)).
+		Return(nil, errors.New(pgrepo.InvalidIdErr)).Once()
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithPagination(pages))
+
+	firstResp := `{"id":"c1","task_id":"test","author_id":"user","body":"one","html":"\u003cp\u003eone\u003c/p\u003e\n","created_at":"2024-10-20T12:00:00Z"}`
+	secondResp := `{"id":"c2","task_id":"test","author_id":"user","body":"one","html":"\u003cp\u003eone\u003c/p\u003e\n","created_at":"2024-10-20T12:01:00Z"}`
+	testCases := []struct {
+		taskID       string
+		target       string
+		expectedCode int
+		expectedResp string
+	}{
+		{taskID: "test", target: "/task/test/comments?limit=1", expectedCode: http.StatusOK,
+			expectedResp: `{"comments":[` + firstResp + `],"next_cursor":"` + pages.EncodeComment(cursor) + `"}`},
+		{taskID: "test", target: "/task/test/comments?limit=1&cursor=" + pages.EncodeComment(cursor), expectedCode: http.StatusOK,
+			expectedResp: `{"comments":[` + secondResp + `]}`},
+		{taskID: "test", target: "/task/test/comments?cursor=" + pages.Encode(tasktodo.Cursor{DueDate: "2024-10-26", ID: "test"}),
+			expectedCode: http.StatusBadRequest, expectedResp: `{"param":"cursor","error":"invalid cursor"}`},
+		{taskID: "missing", target: "/task/missing/comments", expectedCode: http.StatusNotFound, expectedResp: `{"message":"invalid task id"}`},
+	}
+	for _, tc := range testCases {
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", tc.taskID)
+		r := newRequest("GET", tc.target, nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.ListComments(w, r)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
@@ -0,0 +1,115 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+)
+
+// AddDependency makes a task wait for another one.
+//
+//	@Summary		Adds a blocker to a task
+//	@Description	The task cannot be done until the blocker is done or cancelled, dependency cycles are refused
+//	@Tags			Dependencies
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Task ID"
+//	@Param			dependency	body		tasktodo.Dependency	true	"Blocking task"
+//	@Success		201			{object}	[]tasktodo.Task		"Blockers of the task"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp				"Task not found"
+//	@Failure		409			{object}	ErrResp				"Dependency cycle"
+//	@Failure		422			{object}	ErrResp				"Invalid JSON or unknown blocker"
+//	@Router			/task/{id}/dependencies [post]
+func (s Service) AddDependency(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	dependency := tasktodo.Dependency{}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if err := render.DecodeJSON(r.Body, &dependency); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(dependency); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Str("blocker", dependency.BlockerID).Logger()
+	if err := s.DB.AddDependency(r.Context(), taskID, dependency.BlockerID); err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	blockers, err := s.DB.ListBlockers(r.Context(), taskID)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	logID.Info().Msg("dependency added")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, blockers)
+}
+
+// ListBlockers returns the tasks a task waits for.
+//
+//	@Summary		Returns blockers of a task
+//	@Description	Retrieves the tasks the specified task depends on, including finished ones
+//	@Tags			Dependencies
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"Task ID"
+//	@Success		200	{object}	[]tasktodo.Task	"Blockers of the task"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp			"Task not found"
+//	@Router			/task/{id}/dependencies [get]
+func (s Service) ListBlockers(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	s.listRelated(w, r, s.DB.ListBlockers)
+}
+
+// RemoveDependency removes a blocker from a task.
+//
+//	@Summary		Removes a blocker from a task
+//	@Description	Deletes the dependency between the task and the blocker
+//	@Tags			Dependencies
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Task ID"
+//	@Param			blocker	path		string	true	"Blocking task ID"
+//	@Success		200		{object}	MsgResp	"Dependency successfully removed"
+//	@Failure		401		{object}	ErrResp	"Missing credentials"
+//	@Failure		403		{object}	ErrResp	"Scope tasks:write not granted"
+//	@Failure		404		{object}	MsgResp	"Dependency not found"
+//	@Router			/task/{id}/dependencies/{blocker} [delete]
+func (s Service) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	blockerID := chi.URLParam(r, "blocker")
+	logID := log.With().Str("id", taskID).Str("blocker", blockerID).Logger()
+	if err := s.DB.RemoveDependency(r.Context(), taskID, blockerID); err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	logID.Info().Msg("dependency removed")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
@@ -0,0 +1,120 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestDependencies() {
+	type dependencyTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	blocker := suite.testTask
+	blocker.ID = "blocker"
+	blocked := suite.testTask
+	blocked.Blocked = true
+	testCases := []dependencyTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddDependency },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("AddDependency", mock.Anything, "test", "blocker").Return(nil).Once()
+					suite.storage.(*mocks.Repo).On("ListBlockers", mock.Anything, "test").Return([]tasktodo.Task{blocker}, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: `[{"id":"blocker","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}]`,
+				reqBody:      `{"blocker_id":"blocker"}`,
+				reqMethod:    "POST",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddDependency },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("AddDependency", mock.Anything, "test", "blocker").Return(errors.New(pgrepo.DependencyCycleErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"blocker_id","error":"dependency cycle"}`,
+				reqBody:      `{"blocker_id":"blocker"}`,
+				reqMethod:    "POST",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddDependency },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("AddDependency", mock.Anything, "test", "missing").Return(errors.New(pgrepo.BlockerErr)).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"blocker_id","error":"invalid blocker id"}`,
+				reqBody:      `{"blocker_id":"missing"}`,
+				reqMethod:    "POST",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.AddDependency },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{}`,
+				reqMethod:     "POST",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RemoveDependency },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("RemoveDependency", mock.Anything, "test", "blocker").Return(errors.New(pgrepo.DependencyErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"dependency not found"}`,
+				reqMethod:    "DELETE",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					done := suite.taskReq
+					done.SetState(tasktodo.StateDone)
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(blocked, nil).Once()
+					suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, done, "test", int64(0)).Return(tasktodo.Task{}, errors.New(pgrepo.BlockedErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"state","value":"done","error":"task has open blockers"}`,
+				reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":true}`,
+				reqMethod:    "PUT",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		ctx.URLParams.Add("blocker", "blocker")
+		req := newRequest(tc.reqMethod, "/task", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
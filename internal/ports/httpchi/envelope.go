@@ -0,0 +1,101 @@
+package httpchi
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type versionKey struct{}
+
+// TaskList is a page of tasks returned since API version 2, Total counts every task matching the filters.
+// Page is 0 when the tasks are listed by cursor, NextCursor is left out on the last page.
+type TaskList struct {
+	Items      []tasktodo.Task `json:"items"`
+	Total      int             `json:"total"`
+	Page       uint            `json:"page"`
+	PageSize   uint            `json:"page_size"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// APIVersion marks requests served by the given version of the API, handlers change their responses accordingly.
+func APIVersion(version int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), versionKey{}, version)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// apiVersion returns the version of the API serving the request, the first one if not marked.
+func apiVersion(r *http.Request) int {
+	if version, ok := r.Context().Value(versionKey{}).(int); ok {
+		return version
+	}
+	return 1
+}
+
+// listTaskList sends tasks wrapped in a TaskList along with the X-Total-Count and RFC 8288 Link headers.
+// Pages by number link to the first, previous, next and last pages, pages by cursor only to the first and next ones.
+func (s Service) listTaskList(w http.ResponseWriter, r *http.Request, log zerolog.Logger, params tasktodo.ListParams, byCursor bool) {
+	total, err := s.DB.CountTasks(r.Context(), params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	limit := params.Limit
+	if byCursor {
+		// one extra task tells whether the page is the last one
+		params.Lookahead = 1
+	}
+	tasks, err := s.DB.ListTasks(r.Context(), params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	list := TaskList{Items: tasks, Total: total, Page: params.Page, PageSize: limit}
+	var links []string
+	if byCursor {
+		links = append(links, pageLink(r.URL, "first", "cursor", ""))
+		if uint(len(tasks)) > limit {
+			list.Items = tasks[:limit]
+			last := list.Items[limit-1]
+			list.NextCursor = s.Pages.Encode(tasktodo.NewCursor(last))
+			links = append(links, pageLink(r.URL, "next", "cursor", list.NextCursor))
+		}
+	} else {
+		lastPage := uint(0)
+		if total > 0 {
+			lastPage = (uint(total) - 1) / limit
+		}
+		links = append(links, pageLink(r.URL, "first", "page", "0"))
+		if params.Page > 0 {
+			links = append(links, pageLink(r.URL, "prev", "page", strconv.FormatUint(uint64(min(params.Page-1, lastPage)), 10)))
+		}
+		if params.Page < lastPage {
+			links = append(links, pageLink(r.URL, "next", "page", strconv.FormatUint(uint64(params.Page+1), 10)))
+		}
+		links = append(links, pageLink(r.URL, "last", "page", strconv.FormatUint(uint64(lastPage), 10)))
+	}
+	log.Info().Int("amount", len(list.Items)).Int("total", total).Msg("found successfully")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Link", strings.Join(links, ", "))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, list)
+}
+
+// pageLink formats a link to the same listing with the page or cursor replaced.
+func pageLink(u *url.URL, rel, param, value string) string {
+	query := u.Query()
+	query.Del("page")
+	query.Del("cursor")
+	query.Set(param, value)
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), rel)
+}
@@ -0,0 +1,122 @@
+package httpchi_test
+
+import (
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestListTasksV2() {
+	pages, err := tasktodo.NewPaginator(config.PaginationCfg{CursorSecret: "secret", DefaultLimit: 2, MaxLimit: 5})
+	suite.Require().NoError(err)
+	task2 := suite.testTask
+	task2.ID += "2"
+	next := pages.Encode(tasktodo.Cursor{DueDate: "2024-10-26", ID: "test2"})
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`
+	task2Resp := strings.Replace(taskResp, `"id":"test"`, `"id":"test2"`, 1)
+	testCases := []struct {
+		storageOutput func()
+		target        string
+		expectedCode  int
+		expectedResp  string
+		expectedTotal string
+		expectedLink  string
+	}{
+		{
+			storageOutput: func() {
+				params := tasktodo.ListParams{Limit: 2, Page: 1, Status: "false", TagMode: tasktodo.TagModeAny}
+				suite.storage.(*mocks.Repo).On("CountTasks", mock.Anything, params).Return(5, nil).Once()
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, params).Return([]tasktodo.Task{suite.testTask, task2}, nil).Once()
+			},
+			target:        "/tasks?status=false&page=1",
+			expectedCode:  http.StatusOK,
+			expectedResp:  `{"items":[` + taskResp + `,` + task2Resp + `],"total":5,"page":1,"page_size":2}`,
+			expectedTotal: "5",
+			expectedLink: `</tasks?page=0&status=false>; rel="first", </tasks?page=0&status=false>; rel="prev", ` +
+				`</tasks?page=2&status=false>; rel="next", </tasks?page=2&status=false>; rel="last"`,
+		},
+		{
+			storageOutput: func() {
+				params := tasktodo.ListParams{Limit: 2, Page: 7, TagMode: tasktodo.TagModeAny}
+				suite.storage.(*mocks.Repo).On("CountTasks", mock.Anything, params).Return(0, nil).Once()
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, params).Return([]tasktodo.Task{}, nil).Once()
+			},
+			target:        "/tasks?page=7",
+			expectedCode:  http.StatusOK,
+			expectedResp:  `{"items":[],"total":0,"page":7,"page_size":2}`,
+			expectedTotal: "0",
+			expectedLink:  `</tasks?page=0>; rel="first", </tasks?page=0>; rel="prev", </tasks?page=0>; rel="last"`,
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("CountTasks", mock.Anything, tasktodo.ListParams{Limit: 2, TagMode: tasktodo.TagModeAny}).Return(3, nil).Once()
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 2, Lookahead: 1, TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.Task{suite.testTask, task2, task2}, nil).Once()
+			},
+			target:        "/tasks?cursor=",
+			expectedCode:  http.StatusOK,
+			expectedResp:  `{"items":[` + taskResp + `,` + task2Resp + `],"total":3,"page":0,"page_size":2,"next_cursor":"` + next + `"}`,
+			expectedTotal: "3",
+			expectedLink:  `</tasks?cursor=>; rel="first", </tasks?cursor=` + next + `>; rel="next"`,
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("CountTasks", mock.Anything, tasktodo.ListParams{Limit: 2, TagMode: tasktodo.TagModeAny}).
+					Return(0, errors.New("any error")).Once()
+			},
+			target:       "/tasks",
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","error":"action fail"}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, httpchi.WithPagination(pages))
+		w := httptest.NewRecorder()
+
+		httpchi.APIVersion(2)(http.HandlerFunc(suite.service.ListTasks)).ServeHTTP(w, newRequest("GET", tc.target, nil))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+		suite.Equal(tc.expectedTotal, w.Header().Get("X-Total-Count"))
+		suite.Equal(tc.expectedLink, w.Header().Get("Link"))
+	}
+}
+
+func (suite *UnitTestSuite) TestAPIVersionRoutes() {
+	issuer := suite.newIssuer()
+	tokens, err := issuer.Issue("user")
+	suite.Require().NoError(err)
+	suite.service = httpchi.NewService(suite.storage, httpchi.WithAuth(mocks.NewUserRepo(suite.T()), issuer))
+	router := httpchi.NewRouter(suite.service, zerolog.Nop())
+	params := tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny}
+	suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, params).Return([]tasktodo.Task{}, nil).Once()
+	suite.storage.(*mocks.Repo).On("CountTasks", mock.Anything, params).Return(0, nil).Once()
+	suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, params).Return([]tasktodo.Task{}, nil).Once()
+
+	testCases := []struct {
+		target       string
+		expectedCode int
+		expectedResp string
+	}{
+		{target: "/api/tasks", expectedCode: http.StatusNotFound, expectedResp: `{"message":"nothing found"}`},
+		{target: "/api/v2/tasks", expectedCode: http.StatusOK, expectedResp: `{"items":[],"total":0,"page":0,"page_size":10}`},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", tc.target, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
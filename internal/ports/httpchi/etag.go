@@ -0,0 +1,84 @@
+package httpchi
+
+import (
+	"errors"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errBadPrecondition = errors.New("bad precondition header")
+
+// etag renders the task version as a strong entity tag.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// etagMatch reports whether the If-None-Match style header value matches the tag.
+// Comparison is weak as required for If-None-Match, so W/"1" matches "1".
+func etagMatch(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIfMatch extracts the expected task version from the If-Match header.
+// Absent header and "*" both mean that any existing version is acceptable (0).
+// Only a single strong tag is supported, a weak tag never matches.
+func parseIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	if strings.HasPrefix(header, "W/") {
+		return 0, errors.New(pgrepo.VersionErr)
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, errBadPrecondition
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		return 0, errBadPrecondition
+	}
+	if version < 1 {
+		return 0, errors.New(pgrepo.VersionErr)
+	}
+	return version, nil
+}
+
+// writeVersion evaluates If-Match and If-None-Match of a state changing request
+// and returns the version the write has to be conditioned on.
+// If-None-Match requires the current version, so in that case the task is read first
+// and the write is bound to the version that has been checked.
+func (s Service) writeVersion(r *http.Request, taskID string) (int64, error) {
+	if r.Header.Get("If-None-Match") == "" {
+		return parseIfMatch(r.Header.Get("If-Match"))
+	}
+	task, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		return 0, err
+	}
+	return preconditions(r, task)
+}
+
+// preconditions evaluates If-Match and If-None-Match against a task that has already
+// been read and returns its version, so the write fails if the task changes in between.
+func preconditions(r *http.Request, current tasktodo.Task) (int64, error) {
+	version, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		return 0, err
+	}
+	if version != 0 && version != current.Version {
+		return 0, errors.New(pgrepo.VersionErr)
+	}
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" && etagMatch(noneMatch, etag(current.Version)) {
+		return 0, errors.New(pgrepo.VersionErr)
+	}
+	return current.Version, nil
+}
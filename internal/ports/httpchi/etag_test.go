@@ -0,0 +1,218 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestConditionalRequests() {
+	type conditionalTestCase struct {
+		headers      map[string]string
+		handler      func(s httpchi.Service) http.HandlerFunc
+		expectedETag string
+		TestCase
+	}
+	stored := suite.testTask
+	stored.Version = 3
+	updated := stored
+	updated.Version = 4
+	testCases := []conditionalTestCase{
+		{
+			headers:      map[string]string{"If-None-Match": `"3"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.GetSingleTask },
+			expectedETag: `"3"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.Progress{}, nil).Once()
+					suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(0, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
+				},
+				expectedCode: http.StatusNotModified,
+				expectedResp: ``,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			headers:      map[string]string{"If-None-Match": `"1", "2"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.GetSingleTask },
+			expectedETag: `"3"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.Progress{}, nil).Once()
+					suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(0, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":3}`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			headers:      map[string]string{"If-None-Match": `"3"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.GetSingleTask },
+			expectedETag: `"3"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.NewProgress(1, 1), nil).Once()
+					suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(0, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":3,"progress":{"total":1,"done":1,"percent":100}}`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			headers:      map[string]string{"If-None-Match": `"3"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.GetSingleTask },
+			expectedETag: `"3"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.Progress{}, nil).Once()
+					suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(1, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":3,"comment_count":1}`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			headers:      map[string]string{"If-None-Match": `"3"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.GetSingleTask },
+			expectedETag: `"3"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.Progress{}, nil).Once()
+					suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(0, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{{ID: "i1", Text: "milk", Done: true}}), nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":3,"checklist":{"items":[{"id":"i1","text":"milk","done":true,"created_at":"0001-01-01T00:00:00Z"}],"done":1,"total":1,"progress":"1/1"}}`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			headers:      map[string]string{"If-Match": `"3"`},
+			handler:      func(s httpchi.Service) http.HandlerFunc { return s.UpdateTask },
+			expectedETag: `"4"`,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, suite.taskReq, "test", int64(3)).Return(updated, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":4}`,
+				reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
+				reqMethod:    "PUT",
+			},
+		},
+		{
+			headers: map[string]string{"If-Match": `"2"`},
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+				},
+				expectedCode: http.StatusPreconditionFailed,
+				expectedResp: `{"param":"If-Match","value":"\"2\"","error":"version mismatch"}`,
+				reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
+				reqMethod:    "PUT",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, suite.taskReq, "test", int64(3)).Return(tasktodo.Task{}, errors.New(pgrepo.VersionErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"id","value":"test","error":"concurrent update"}`,
+				reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
+				reqMethod:    "PUT",
+			},
+		},
+		{
+			headers: map[string]string{"If-Match": `W/"3"`},
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusPreconditionFailed,
+				expectedResp:  `{"param":"If-Match","value":"W/\"3\"","error":"version mismatch"}`,
+				reqMethod:     "DELETE",
+			},
+		},
+		{
+			headers: map[string]string{"If-Match": `3`},
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"If-Match","value":"3","error":"bad precondition header"}`,
+				reqMethod:     "DELETE",
+			},
+		},
+		{
+			headers: map[string]string{"If-None-Match": `"3"`},
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+				},
+				expectedCode: http.StatusPreconditionFailed,
+				expectedResp: `{"param":"If-None-Match","value":"\"3\"","error":"version mismatch"}`,
+				reqMethod:    "DELETE",
+			},
+		},
+		{
+			headers: map[string]string{"If-None-Match": `"2"`},
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(stored, nil).Once()
+					suite.storage.(*mocks.Repo).On("DeleteTask", mock.Anything, "test", int64(3)).Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+				reqMethod:    "DELETE",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest(tc.reqMethod, "/task", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		for key, val := range tc.headers {
+			req.Header.Set(key, val)
+		}
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+		suite.Equal(tc.expectedETag, w.Header().Get("ETag"))
+	}
+}
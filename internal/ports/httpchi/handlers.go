@@ -2,30 +2,50 @@
 package httpchi
 
 import (
+	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog"
 	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
 	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+const mergePatchType = "application/merge-patch+json"
+
 // CreateTask creates a new task.
 //
 //	@Summary		creates a new task
-//	@Description	Creates a task with specified fields: title, description, due date, and completion status
+//	@Description	Creates a task with specified fields: title, description, due date, and workflow state (or legacy completion status)
 //	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Accept			json
 //	@Produce		json
 //	@Param			taskRequest	body		tasktodo.Request	true	"Data of the new task"
+//	@Param			import		query		bool				false	"Import the task with any due date, admins only"
 //	@Success		201			{object}	tasktodo.Task		"Task successfully created"
+//	@Header			201			{string}	ETag				"Task version"
 //	@Failure		400			{object}	ErrResp				"Incorrect JSON or invalid date format"
-//	@Failure		422			{object}	ErrResp				"Invalid JSON"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted or import by a user who is not an admin"
+//	@Failure		409			{object}	ErrResp				"Project is archived"
+//	@Failure		422			{object}	ErrResp				"Invalid JSON, due date in the past, unknown state, parent task, project or recurrence rule"
 //	@Router			/task [post]
 func (s Service) CreateTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
 	taskRequest := tasktodo.Request{}
 	log := *zerolog.Ctx(r.Context())
 	if err := render.DecodeJSON(r.Body, &taskRequest); err != nil {
@@ -39,17 +59,53 @@ func (s Service) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	log.Info().Msg("request body decoded")
+	if projectID := chi.URLParam(r, "pid"); projectID != "" {
+		taskRequest.ProjectID = &projectID
+	}
+	taskRequest.Tags = tasktodo.NormalizeTags(taskRequest.Tags)
 	if err := validator.New().Struct(taskRequest); err != nil {
 		log.Error().Err(err).Send()
 		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
 		return
 	}
-	newTask, err := s.DB.CreateTask(taskRequest)
+	if err := validateRecurrence(taskRequest.Recurrence); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("recurrence", *taskRequest.Recurrence, tasktodo.RecurrenceErr).Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	var importing bool
+	if param := r.URL.Query().Get("import"); param != "" {
+		var err error
+		if importing, err = strconv.ParseBool(param); err != nil {
+			log.Error().Err(err).Send()
+			NewErr("import", param, "bad import").Send(w, r, http.StatusBadRequest)
+			return
+		}
+		if userID := account.UserID(r.Context()); importing && !s.DueDates.CanImport(userID) {
+			log.Warn().Str("user_id", userID).Msg(tasktodo.ImportErr)
+			NewErr("import", param, tasktodo.ImportErr).Send(w, r, http.StatusForbidden)
+			return
+		}
+	}
+	if !importing {
+		if err := s.DueDates.Check(taskRequest.DueDate, taskRequest.TimeZone, "", time.Now()); err != nil {
+			errorHandler(w, r, log, taskRequest.DueDate, "", err)
+			return
+		}
+	}
+	state, err := s.Workflow.Resolve("", taskRequest.State, taskRequest.Status)
+	if err != nil {
+		stateErrorHandler(w, r, log, taskRequest.State, err)
+		return
+	}
+	taskRequest.SetState(state)
+	newTask, err := s.DB.CreateTask(r.Context(), taskRequest)
 	if err != nil {
 		errorHandler(w, r, log, taskRequest.DueDate, "", err)
 		return
 	}
 	log.Info().Msg("task created successfully")
+	w.Header().Set("ETag", etag(newTask.Version))
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, newTask)
 }
@@ -57,24 +113,67 @@ func (s Service) CreateTask(w http.ResponseWriter, r *http.Request) {
 // GetSingleTask returns a task based on the specified ID.
 //
 //	@Summary		Gets a task by ID
-//	@Description	Retrieves a task based on the provided identifier
+//	@Description	Retrieves a task based on the provided identifier, a task with subtasks also gets the completion roll-up
 //	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string			true	"Task ID"
-//	@Success		200	{object}	tasktodo.Task	"Task successfully retrieved"
-//	@Failure		404	{object}	MsgResp			"Task not found"
+//	@Param			id				path		string			true	"Task ID"
+//	@Param			If-None-Match	header		string			false	"Known task ETag"
+//	@Success		200				{object}	tasktodo.Task	"Task successfully retrieved"
+//	@Header			200				{string}	ETag			"Task version"
+//	@Success		304				"Task has not changed (never used for tasks with subtasks, comments or checklist items)"
+//	@Failure		401				{object}	ErrResp	"Missing credentials"
+//	@Failure		403				{object}	ErrResp	"Scope tasks:read not granted"
+//	@Failure		404				{object}	MsgResp	"Task not found"
 //	@Router			/task/{id} [get]
 func (s Service) GetSingleTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
 	log := *zerolog.Ctx(r.Context())
 	taskID := chi.URLParam(r, "id")
 	log.Info().Str("id", taskID).Msg("task id received")
-	task, err := s.DB.GetTask(taskID)
+	task, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		logID := log.With().Str("id", taskID).Logger()
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	progress, err := s.DB.GetProgress(r.Context(), taskID)
 	if err != nil {
 		logID := log.With().Str("id", taskID).Logger()
 		errorHandler(w, r, logID, "", taskID, err)
 		return
 	}
+	if progress.Total != 0 {
+		task.Progress = &progress
+	}
+	if task.CommentCount, err = s.DB.CountComments(r.Context(), taskID); err != nil {
+		logID := log.With().Str("id", taskID).Logger()
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	checklist, err := s.DB.GetChecklist(r.Context(), taskID)
+	if err != nil {
+		logID := log.With().Str("id", taskID).Logger()
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	if checklist.Total != 0 {
+		task.Checklist = &checklist
+	}
+	tag := etag(task.Version)
+	w.Header().Set("ETag", tag)
+	// the roll-up, the comment count and the checklist are not covered by the task version,
+	// so tasks with subtasks, comments or checklist items are always sent in full
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" && task.Progress == nil && task.CommentCount == 0 &&
+		task.Checklist == nil && etagMatch(noneMatch, tag) {
+		log.Info().Str("id", taskID).Msg("not modified")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	log.Info().Str("id", taskID).Msg("received successfully")
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, task)
@@ -83,19 +182,33 @@ func (s Service) GetSingleTask(w http.ResponseWriter, r *http.Request) {
 // DeleteTask deletes a task by the specified ID.
 //
 //	@Summary		Deletes a task by ID
-//	@Description	Deletes a task by the specified identifier
+//	@Description	Deletes a task by the specified identifier together with all its subtasks
 //	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"Task ID"
-//	@Success		200	{object}	MsgResp	"Task successfully deleted"
-//	@Failure		404	{object}	MsgResp	"Task not found"
+//	@Param			id				path		string	true	"Task ID"
+//	@Param			If-Match		header		string	false	"Expected task ETag"
+//	@Param			If-None-Match	header		string	false	"Task ETag that must not match"
+//	@Success		200				{object}	MsgResp	"Task successfully deleted"
+//	@Failure		400				{object}	ErrResp	"Malformed precondition header"
+//	@Failure		401				{object}	ErrResp	"Missing credentials"
+//	@Failure		403				{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404				{object}	MsgResp	"Task not found"
+//	@Failure		412				{object}	ErrResp	"Task version does not match"
 //	@Router			/task/{id} [delete]
 func (s Service) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
 	log := *zerolog.Ctx(r.Context())
 	taskID := chi.URLParam(r, "id")
 	log.Info().Str("id", taskID).Msg("task id received")
-	err := s.DB.DeleteTask(taskID)
+	version, err := s.writeVersion(r, taskID)
+	if err == nil {
+		err = s.DB.DeleteTask(r.Context(), taskID, version)
+	}
 	if err != nil {
 		logID := log.With().Str("id", taskID).Logger()
 		errorHandler(w, r, logID, "", taskID, err)
@@ -110,16 +223,28 @@ func (s Service) DeleteTask(w http.ResponseWriter, r *http.Request) {
 //	@Summary		Updates a task by ID
 //	@Description	Updates a task by the specified identifier
 //	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		string				true	"Task ID"
-//	@Param			taskUpd	body		tasktodo.Request	true	"Data for updating the task"
-//	@Success		200		{object}	tasktodo.Task		"Task successfully updated"
-//	@Failure		400		{object}	ErrResp				"Incorrect JSON or invalid date format"
-//	@Failure		404		{object}	MsgResp				"Task not found"
-//	@Failure		422		{object}	ErrResp				"Invalid JSON"
+//	@Param			id				path		string				true	"Task ID"
+//	@Param			If-Match		header		string				false	"Expected task ETag"
+//	@Param			If-None-Match	header		string				false	"Task ETag that must not match"
+//	@Param			taskUpd			body		tasktodo.Request	true	"Data for updating the task"
+//	@Success		200				{object}	tasktodo.Task		"Task successfully updated"
+//	@Header			200				{string}	ETag				"New task version"
+//	@Failure		400				{object}	ErrResp				"Incorrect JSON, invalid date format or malformed precondition header"
+//	@Failure		401				{object}	ErrResp				"Missing credentials"
+//	@Failure		403				{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp				"Task not found"
+//	@Failure		409				{object}	ErrResp				"State transition is not allowed, task has open blockers, hierarchy cycle, archived project or concurrent update"
+//	@Failure		412				{object}	ErrResp				"Task version does not match"
+//	@Failure		422				{object}	ErrResp				"Invalid JSON, due date changed to the past, unknown state, parent task, project or recurrence rule"
 //	@Router			/task/{id} [put]
 func (s Service) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
 	taskUpd := tasktodo.Request{}
 	log := *zerolog.Ctx(r.Context())
 	taskID := chi.URLParam(r, "id")
@@ -135,55 +260,240 @@ func (s Service) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	log.Info().Msg("request body decoded")
+	taskUpd.Tags = tasktodo.NormalizeTags(taskUpd.Tags)
 	if err := validator.New().Struct(taskUpd); err != nil {
 		log.Error().Err(err).Send()
 		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
 		return
 	}
-	newTask, err := s.DB.UpdateTask(taskUpd, taskID)
+	if err := validateRecurrence(taskUpd.Recurrence); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("recurrence", *taskUpd.Recurrence, tasktodo.RecurrenceErr).Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Logger()
+	current, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		errorHandler(w, r, logID, taskUpd.DueDate, taskID, err)
+		return
+	}
+	version, err := preconditions(r, current)
+	if err != nil {
+		errorHandler(w, r, logID, taskUpd.DueDate, taskID, err)
+		return
+	}
+	if err = s.DueDates.Check(taskUpd.DueDate, taskUpd.TimeZone, current.DueDate, time.Now()); err != nil {
+		errorHandler(w, r, logID, taskUpd.DueDate, taskID, err)
+		return
+	}
+	state, err := s.Workflow.Resolve(current.State, taskUpd.State, taskUpd.Status)
+	if err != nil {
+		stateErrorHandler(w, r, logID, taskUpd.State, err)
+		return
+	}
+	if err = s.Workflow.Check(current.State, state); err != nil {
+		stateErrorHandler(w, r, logID, state, err)
+		return
+	}
+	taskUpd.SetState(state)
+	newTask, err := s.DB.UpdateTask(r.Context(), taskUpd, taskID, version)
 	if err != nil {
-		logID := log.With().Str("id", taskID).Logger()
 		errorHandler(w, r, logID, taskUpd.DueDate, taskID, err)
 		return
 	}
 	log.Info().Msg("task updated successfully")
+	w.Header().Set("ETag", etag(newTask.Version))
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, newTask)
 }
 
+// PatchTask partially updates a task by the specified ID.
+//
+//	@Summary		Partially updates a task by ID
+//	@Description	Applies a JSON Merge Patch (RFC 7396): only supplied fields are validated and updated, the stored task is returned.
+//	@Description	With the series scope title, description and recurrence are applied to the series and its open occurrences.
+//	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			id				path		string			true	"Task ID"
+//	@Param			If-Match		header		string			false	"Expected task ETag"
+//	@Param			If-None-Match	header		string			false	"Task ETag that must not match"
+//	@Param			scope			query		string			false	"Patch only this occurrence or the whole series of a recurring task"	Enums(occurrence, series)	default(occurrence)
+//	@Param			taskPatch		body		tasktodo.Patch	true	"Fields to update"
+//	@Success		200				{object}	tasktodo.Task	"Task successfully updated"
+//	@Header			200				{string}	ETag			"New task version"
+//	@Failure		400				{object}	ErrResp			"Incorrect JSON, invalid date format, unknown scope or malformed precondition header"
+//	@Failure		401				{object}	ErrResp			"Missing credentials"
+//	@Failure		403				{object}	ErrResp			"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp			"Task not found"
+//	@Failure		409				{object}	ErrResp			"Transition is not allowed, task has open blockers, hierarchy cycle, archived project or concurrent update"
+//	@Failure		412				{object}	ErrResp			"Task version does not match"
+//	@Failure		415				{object}	ErrResp			"Unsupported content type"
+//	@Failure		422				{object}	ErrResp			"Invalid field value, due date changed to the past, unknown project, field not shared by the series or task is not recurring"
+//	@Router			/task/{id} [patch]
+func (s Service) PatchTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	patch := tasktodo.Patch{}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if contentType := r.Header.Get("Content-Type"); !isMergePatch(contentType) {
+		log.Warn().Str("content_type", contentType).Msg("unsupported patch media type")
+		w.Header().Set("Accept-Patch", mergePatchType)
+		NewErr("Content-Type", contentType, "unsupported media type").Send(w, r, http.StatusUnsupportedMediaType)
+		return
+	}
+	if err := render.DecodeJSON(r.Body, &patch); err != nil {
+		log.Error().Err(err).Send()
+		var nullErr tasktodo.NullFieldError
+		if errors.As(err, &nullErr) {
+			NewErr(nullErr.Field, "null", "field cannot be removed").Send(w, r, http.StatusUnprocessableEntity)
+			return
+		}
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	var date string
+	if patch.DueDate != nil {
+		date = *patch.DueDate
+		if err := validateDate(date); err != nil {
+			log.Error().Err(err).Send()
+			NewErr("date", date, "bad date format").Send(w, r, http.StatusBadRequest)
+			return
+		}
+	}
+	log.Info().Msg("request body decoded")
+	if patch.Tags != nil {
+		tags := tasktodo.NormalizeTags(*patch.Tags)
+		patch.Tags = &tags
+	}
+	if err := validator.New().Struct(patch); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := validateRecurrence(patch.Recurrence); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("recurrence", *patch.Recurrence, tasktodo.RecurrenceErr).Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Logger()
+	switch scope := tasktodo.Scope(r.URL.Query().Get("scope")); scope {
+	case "", tasktodo.ScopeOccurrence:
+	case tasktodo.ScopeSeries:
+		s.patchSeries(w, r, logID, taskID, patch)
+		return
+	default:
+		logID.Warn().Str("scope", string(scope)).Msg("unknown scope")
+		NewErr("scope", string(scope), "bad scope").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	version, state, err := s.patchVersion(r, taskID, &patch)
+	if err != nil {
+		if msg := err.Error(); msg == tasktodo.StateErr || msg == tasktodo.TransitionErr {
+			stateErrorHandler(w, r, logID, state, err)
+			return
+		}
+		errorHandler(w, r, logID, date, taskID, err)
+		return
+	}
+	task, err := s.DB.PatchTask(r.Context(), patch, taskID, version)
+	if err != nil {
+		errorHandler(w, r, logID, date, taskID, err)
+		return
+	}
+	log.Info().Msg("task patched successfully")
+	w.Header().Set("ETag", etag(task.Version))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, task)
+}
+
 // ListTasks returns a list of tasks considering request parameters.
 //
 //	@Summary		Returns a list of tasks with filtering and pagination
-//	@Description	Retrieves a list of tasks based on status, date, and page for pagination
+//	@Description	Retrieves a list of tasks based on status, date, tags, blockers, project, priority, due date and creation ranges, title prefix, and page for pagination.
+//	@Description	Tasks are ordered by priority and then by due date unless sort lists due_date, title, state (workflow order), created_at or priority. Tasks of archived projects are listed only when the project is asked for.
+//	@Description	With cursor or limit the tasks are wrapped in a TaskPage: an empty cursor starts the listing and next_cursor continues it
+//	@Description	Under /v2 the tasks are always wrapped in a TaskList with the total count, an empty page is not an error there
 //	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Accept			json
 //	@Produce		json
-//	@Param			status	query		string			false	"Task completion status (true/false)"
-//	@Param			date	query		string			false	"Task date (format: YYYY-MM-DD)"
-//	@Param			page	query		string			false	"Page number for pagination"
-//	@Success		200		{object}	[]tasktodo.Task	"List of tasks"
-//	@Failure		400		{object}	ErrResp			"Invalid request parameters"
-//	@Failure		404		{object}	MsgResp			"Tasks not found"
+//	@Param			status		query		string			false	"Task completion status (true/false)"
+//	@Param			date		query		string			false	"Task date (format: YYYY-MM-DD)"
+//	@Param			page		query		string			false	"Page number for pagination"
+//	@Param			tag			query		[]string		false	"Tag names, repeated or comma separated"		collectionFormat(multi)
+//	@Param			tag_mode	query		string			false	"Whether a task needs any or all of the tags"	Enums(any, all)	default(any)
+//	@Param			blocked		query		string			false	"Whether a task has open blockers (true/false)"
+//	@Param			due_from	query		string			false	"Earliest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			due_to		query		string			false	"Latest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			overdue		query		string			false	"Whether a task is past its due time and still open (true/false)"
+//	@Param			priority	query		[]string		false	"Priorities, repeated or comma separated"	collectionFormat(multi)
+//	@Param			created_after	query		string			false	"Creation time lower bound, RFC 3339 or YYYY-MM-DD"
+//	@Param			title_prefix	query		string			false	"Case-insensitive title prefix"
+//	@Param			sort		query		[]string			false	"Sort fields, repeated or comma separated, "-" prefix for descending order; can not be combined with cursor, sorted pages come without next_cursor"	collectionFormat(multi)
+//	@Param			q			query		string			false	"Task query, e.g. status:open tag:backend due<2025-01-01 release"
+//	@Param			project		query		string			false	"Project ID, or none for tasks outside any project"
+//	@Param			limit		query		int				false	"Page size, limited by the server"
+//	@Param			cursor		query		string			false	"Cursor returned as next_cursor, can not be combined with page"
+//	@Success		200			{object}	[]tasktodo.Task	"List of tasks, or TaskPage when paginated by cursor"
+//	@Header			200			{integer}	X-Total-Count	"Number of tasks matching the filters (v2)"
+//	@Header			200			{string}	Link			"RFC 8288 links to the first, prev, next and last pages (v2)"
+//	@Failure		400			{object}	ErrResp			"Invalid request parameters"
+//	@Failure		401			{object}	ErrResp			"Missing credentials"
+//	@Failure		403			{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404			{object}	MsgResp			"Tasks not found"
 //	@Router			/tasks [get]
+//	@Router			/v2/tasks [get]
 func (s Service) ListTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
 	log := *zerolog.Ctx(r.Context())
-	status := r.URL.Query().Get("status")
-	date := r.URL.Query().Get("date")
-	page := r.URL.Query().Get("page")
-	pageNum, errResp, err := validateParams(status, date, page)
+	query := r.URL.Query()
+	params, errResp, err := validateParams(query)
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if projectID := chi.URLParam(r, "pid"); projectID != "" {
+		params.ProjectID = projectID
+	}
+	if errResp, err = parseQuery(query, &params); err != nil {
+		log.Error().Err(err).Str("q", query.Get("q")).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	paged, errResp, err := s.paginate(query, &params)
 	if err != nil {
 		log.Error().Err(err).Send()
 		errResp.Send(w, r, http.StatusBadRequest)
 		return
 	}
-	log.Info().Str("status", status).Str("date", date).Str("page", page).Msg("params received")
-	tasks, err := s.DB.ListTasks(pageNum, date, status)
+	log.Info().Str("status", params.Status).Str("date", params.Date).Uint("page", params.Page).
+		Strs("tags", params.Tags).Str("tag_mode", string(params.TagMode)).Str("blocked", params.Blocked).
+		Str("project", params.ProjectID).Str("q", query.Get("q")).Uint("limit", params.Limit).Bool("cursor", params.After != nil).Msg("params received")
+	if apiVersion(r) >= 2 {
+		s.listTaskList(w, r, log, params, query.Has("cursor"))
+		return
+	}
+	if paged {
+		s.listPage(w, r, log, params)
+		return
+	}
+	tasks, err := s.DB.ListTasks(r.Context(), params)
 	if err != nil {
 		errorHandler(w, r, log, "", "", err)
 		return
 	}
 	if len(tasks) == 0 {
-		log.Warn().Str("status", status).Str("date", date).Str("page", page).Msg("nothing found")
+		log.Warn().Str("query", query.Encode()).Msg("nothing found")
 		NewMsg("nothing found").Send(w, r, http.StatusNotFound)
 		return
 	}
@@ -192,45 +502,371 @@ func (s Service) ListTasks(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, tasks)
 }
 
+// listPage sends a page of tasks listed by cursor, an empty page is not an error there.
+// One extra task is asked for to tell whether the page is the last one.
+func (s Service) listPage(w http.ResponseWriter, r *http.Request, log zerolog.Logger, params tasktodo.ListParams) {
+	limit := params.Limit
+	params.Lookahead = 1
+	tasks, err := s.DB.ListTasks(r.Context(), params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	page := TaskPage{Tasks: tasks}
+	if uint(len(tasks)) > limit {
+		page.Tasks = tasks[:limit]
+		// cursors hold the position in the default order only, sorted tasks are paged by number
+		if len(params.Sort) == 0 {
+			last := page.Tasks[limit-1]
+			page.NextCursor = s.Pages.Encode(tasktodo.NewCursor(last))
+		}
+	}
+	log.Info().Int("amount", len(page.Tasks)).Bool("last", page.NextCursor == "").Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, page)
+}
+
+// paginate reads the page size and cursor, it reports whether the client asked for cursor pagination
+// by passing either of them. Old clients paging by number get bare lists as before.
+func (s Service) paginate(query url.Values, params *tasktodo.ListParams) (bool, ErrResp, error) {
+	limit, errResp, err := s.pageLimit(query)
+	if err != nil {
+		return false, errResp, err
+	}
+	params.Limit = limit
+	if !query.Has("cursor") {
+		return query.Has("limit"), ErrResp{}, nil
+	}
+	if page := query.Get("page"); page != "" {
+		return false, NewErr("page", page, "page can not be combined with cursor"), errors.New("page with cursor")
+	}
+	// cursors hold the position in the default order only
+	if sort := query.Get("sort"); sort != "" {
+		return false, NewErr("sort", sort, "sort can not be combined with cursor"), errors.New("sort with cursor")
+	}
+	if token := query.Get("cursor"); token != "" {
+		cursor, err := s.Pages.Decode(token)
+		if err != nil {
+			return false, NewErr("cursor", "", tasktodo.CursorErr), err
+		}
+		params.After = &cursor
+	}
+	return true, ErrResp{}, nil
+}
+
+// pageLimit reads the page size, the default one if it is not given.
+func (s Service) pageLimit(query url.Values) (uint, ErrResp, error) {
+	limit := query.Get("limit")
+	if limit == "" {
+		return s.Pages.DefaultLimit, ErrResp{}, nil
+	}
+	temp, err := strconv.ParseUint(limit, 10, 32)
+	if err != nil || temp == 0 || uint(temp) > s.Pages.MaxLimit {
+		return 0, NewErr("limit", limit, fmt.Sprintf("limit must be between 1 and %d", s.Pages.MaxLimit)), errors.New("bad limit")
+	}
+	return uint(temp), ErrResp{}, nil
+}
+
+// pageParams reads the page number and size of listings paged by number only.
+func (s Service) pageParams(query url.Values) (tasktodo.ListParams, ErrResp, error) {
+	limit, errResp, err := s.pageLimit(query)
+	if err != nil {
+		return tasktodo.ListParams{}, errResp, err
+	}
+	params := tasktodo.ListParams{Limit: limit}
+	if page := query.Get("page"); page != "" {
+		temp, err := strconv.ParseUint(page, 10, 32)
+		if err != nil {
+			return tasktodo.ListParams{}, NewErr("page", page, "bad page"), err
+		}
+		params.Page = uint(temp)
+	}
+	return params, ErrResp{}, nil
+}
+
+func validateRecurrence(rule *string) error {
+	if rule == nil || *rule == "" {
+		return nil
+	}
+	return tasktodo.ValidateRecurrence(*rule)
+}
+
+// dateLayout is the layout of dates in requests, RFC 3339 times are accepted where dates are.
+const dateLayout = "2006-01-02"
+
+// validateDate accepts a date or an RFC 3339 time.
 func validateDate(date string) error {
-	layout := "2006-01-02"
-	_, err := time.Parse(layout, date)
-	return err
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		_, err = time.Parse(time.RFC3339, date)
+		return err
+	}
+	return nil
 }
 
-func validateParams(status, date, page string) (uint, ErrResp, error) {
-	var pageNum uint
-	if date != "" {
-		if err := validateDate(date); err != nil {
-			return 0, NewErr("date", date, "bad date format"), err
+// calendarDate returns the date part of a date or an RFC 3339 time accepted by validateDate,
+// the listing filters compare due dates only.
+func calendarDate(date string) string {
+	if len(date) > len(dateLayout) {
+		return date[:len(dateLayout)]
+	}
+	return date
+}
+
+// isMergePatch reports whether the request body can be treated as a merge patch document.
+// Plain JSON is accepted as well, since every JSON object is a valid merge patch.
+func isMergePatch(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == mergePatchType || mediaType == "application/json"
+}
+
+// validateParams parses listing filters. Tags may be given as repeated tag parameters
+// or as a comma separated list, tag_mode tells whether a task needs any or all of them.
+func validateParams(query url.Values) (tasktodo.ListParams, ErrResp, error) {
+	params := tasktodo.ListParams{
+		Date:      query.Get("date"),
+		Status:    query.Get("status"),
+		TagMode:   tasktodo.TagModeAny,
+		ProjectID: query.Get("project"),
+	}
+	if params.Date != "" {
+		if err := validateDate(params.Date); err != nil {
+			return tasktodo.ListParams{}, NewErr("date", params.Date, "bad date format"), err
+		}
+		params.Date = calendarDate(params.Date)
+	}
+	if params.Status != "" {
+		_, err := strconv.ParseBool(params.Status)
+		if err != nil {
+			return tasktodo.ListParams{}, NewErr("status", params.Status, "bad status"), err
 		}
 	}
-	if status != "" {
-		_, err := strconv.ParseBool(status)
+	if blocked := query.Get("blocked"); blocked != "" {
+		_, err := strconv.ParseBool(blocked)
 		if err != nil {
-			return 0, NewErr("status", status, "bad status"), err
+			return tasktodo.ListParams{}, NewErr("blocked", blocked, "bad blocked"), err
 		}
+		params.Blocked = blocked
 	}
-	if page != "" {
+	if page := query.Get("page"); page != "" {
 		temp, err := strconv.ParseUint(page, 10, 32)
 		if err != nil {
-			return 0, NewErr("page", page, "bad page"), err
+			return tasktodo.ListParams{}, NewErr("page", page, "bad page"), err
+		}
+		params.Page = uint(temp)
+	}
+	var tags []string
+	for _, tag := range query["tag"] {
+		tags = append(tags, strings.Split(tag, ",")...)
+	}
+	if tags = tasktodo.NormalizeTags(tags); len(tags) != 0 {
+		params.Tags = tags
+	}
+	if mode := query.Get("tag_mode"); mode != "" {
+		params.TagMode = tasktodo.TagMode(mode)
+		if params.TagMode != tasktodo.TagModeAny && params.TagMode != tasktodo.TagModeAll {
+			return tasktodo.ListParams{}, NewErr("tag_mode", mode, "bad tag mode"), errors.New("unknown tag mode")
+		}
+	}
+	return validateFilters(query, params)
+}
+
+// parseQuery reads the task query of the listing, the error response points at the offending token.
+func parseQuery(query url.Values, params *tasktodo.ListParams) (ErrResp, error) {
+	if !query.Has("q") {
+		return ErrResp{}, nil
+	}
+	q := query.Get("q")
+	if utf8.RuneCountInString(q) > tasktodo.QueryLimit {
+		return NewErr("q", "", fmt.Sprintf("query is longer than %d characters", tasktodo.QueryLimit)), errors.New("query is too long")
+	}
+	node, err := tasktodo.ParseQuery(q)
+	if err != nil {
+		var queryErr *taskquery.Error
+		if errors.As(err, &queryErr) {
+			return NewErr("q", queryErr.Token, queryErr.Error()), err
+		}
+		return NewErr("q", q, "bad query"), err
+	}
+	params.Query = node
+	return ErrResp{}, nil
+}
+
+// validateFilters reads the range, priority, prefix and sort parameters of a task listing.
+func validateFilters(query url.Values, params tasktodo.ListParams) (tasktodo.ListParams, ErrResp, error) {
+	for _, param := range []string{"due_from", "due_to"} {
+		if date := query.Get(param); date != "" {
+			if err := validateDate(date); err != nil {
+				return tasktodo.ListParams{}, NewErr(param, date, "bad date format"), err
+			}
+		}
+	}
+	params.DueFrom, params.DueTo = calendarDate(query.Get("due_from")), calendarDate(query.Get("due_to"))
+	// dates in the same layout compare as strings
+	if params.DueFrom != "" && params.DueTo != "" && params.DueFrom > params.DueTo {
+		return tasktodo.ListParams{}, NewErr("due_to", params.DueTo, "due_to is before due_from"), errors.New("empty due date range")
+	}
+	if overdue := query.Get("overdue"); overdue != "" {
+		_, err := strconv.ParseBool(overdue)
+		if err != nil {
+			return tasktodo.ListParams{}, NewErr("overdue", overdue, "bad overdue"), err
 		}
-		pageNum = uint(temp)
+		params.Overdue = overdue
 	}
-	return pageNum, ErrResp{}, nil
+	if query.Has("priority") {
+		list := strings.Join(query["priority"], ",")
+		priorities, err := tasktodo.ParsePriorities(list)
+		if err != nil {
+			return tasktodo.ListParams{}, NewErr("priority", list, tasktodo.PriorityErr), err
+		}
+		params.Priorities = priorities
+	}
+	if created := query.Get("created_after"); created != "" {
+		if err := validateDate(created); err != nil {
+			return tasktodo.ListParams{}, NewErr("created_after", created, "bad created_after"), err
+		}
+		params.CreatedAfter = created
+	}
+	if prefix := strings.TrimSpace(query.Get("title_prefix")); prefix != "" {
+		if utf8.RuneCountInString(prefix) > 255 {
+			return tasktodo.ListParams{}, NewErr("title_prefix", prefix, "bad title_prefix"), errors.New("title prefix is too long")
+		}
+		params.TitlePrefix = prefix
+	}
+	if query.Has("sort") {
+		sort := strings.Join(query["sort"], ",")
+		fields, err := tasktodo.ParseSort(sort)
+		if err != nil {
+			return tasktodo.ListParams{}, NewErr("sort", sort, tasktodo.SortErr), err
+		}
+		params.Sort = fields
+	}
+	return params, ErrResp{}, nil
+}
+
+// patchVersion checks preconditions of a patch. A patch touching the state or the status
+// goes through the workflow, which needs the current task, and is rewritten to set both.
+// A changed due date is checked against the due date policy.
+// The returned state is the one requested by the patch, it is meant for error reporting.
+func (s Service) patchVersion(r *http.Request, taskID string, patch *tasktodo.Patch) (int64, tasktodo.State, error) {
+	if patch.State == nil && patch.Status == nil && patch.DueDate == nil {
+		version, err := s.writeVersion(r, taskID)
+		return version, "", err
+	}
+	current, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		return 0, "", err
+	}
+	version, err := preconditions(r, current)
+	if err != nil {
+		return 0, "", err
+	}
+	if patch.DueDate != nil {
+		zone := current.TimeZone
+		if patch.TimeZone != nil {
+			zone = *patch.TimeZone
+		}
+		if err = s.DueDates.Check(*patch.DueDate, zone, current.DueDate, time.Now()); err != nil {
+			return 0, "", err
+		}
+	}
+	if patch.State == nil && patch.Status == nil {
+		return version, "", nil
+	}
+	var requested tasktodo.State
+	if patch.State != nil {
+		requested = *patch.State
+	}
+	state, err := s.Workflow.Resolve(current.State, requested, patch.Status)
+	if err != nil {
+		return 0, requested, err
+	}
+	if err = s.Workflow.Check(current.State, state); err != nil {
+		return 0, state, err
+	}
+	done := state == tasktodo.StateDone
+	patch.State, patch.Status = &state, &done
+	return version, state, nil
 }
 
 func errorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, date, taskID string, err error) {
 	switch err.Error() {
-	case pgrepo.InvalidIdErr:
+	case pgrepo.InvalidIdErr, pgrepo.DependencyErr:
 		log.Warn().Err(err).Send()
-		NewMsg(pgrepo.InvalidIdErr).Send(w, r, http.StatusNotFound)
-	case pgrepo.DateErr:
-		log.Warn().Err(err).Send()
-		NewErr("date", date, pgrepo.DateErr).Send(w, r, http.StatusConflict)
-	default:
+		NewMsg(err.Error()).Send(w, r, http.StatusNotFound)
+		return
+	}
+	status, resp := errorResponse(r, date, taskID, err)
+	if status == http.StatusInternalServerError {
 		log.Error().Err(err).Send()
-		NewErr("id", taskID, "action fail").Send(w, r, http.StatusInternalServerError)
+	} else {
+		log.Warn().Err(err).Send()
+	}
+	resp.Send(w, r, status)
+}
+
+// errorResponse maps an error of a task operation to the status and the body of the response.
+func errorResponse(r *http.Request, date, taskID string, err error) (int, ErrResp) {
+	switch err.Error() {
+	case pgrepo.InvalidIdErr:
+		return http.StatusNotFound, NewErr("id", taskID, pgrepo.InvalidIdErr)
+	case tasktodo.DueDatePastErr:
+		return http.StatusUnprocessableEntity, NewErr("due_date", date, tasktodo.DueDatePastErr)
+	case pgrepo.VersionErr:
+		param := "If-Match"
+		if r.Header.Get(param) == "" {
+			param = "If-None-Match"
+		}
+		if r.Header.Get(param) == "" {
+			// the write was bound to the version read by the handler itself
+			return http.StatusConflict, NewErr("id", taskID, "concurrent update")
+		}
+		return http.StatusPreconditionFailed, NewErr(param, r.Header.Get(param), pgrepo.VersionErr)
+	case pgrepo.ParentErr:
+		return http.StatusUnprocessableEntity, NewErr("parent_id", "", pgrepo.ParentErr)
+	case pgrepo.CycleErr:
+		return http.StatusConflict, NewErr("parent_id", "", pgrepo.CycleErr)
+	case pgrepo.ParentDeletedErr:
+		return http.StatusConflict, NewErr("parent_id", "", pgrepo.ParentDeletedErr)
+	case pgrepo.BlockedErr:
+		return http.StatusConflict, NewErr("state", string(tasktodo.StateDone), pgrepo.BlockedErr)
+	case pgrepo.BlockerErr:
+		return http.StatusUnprocessableEntity, NewErr("blocker_id", "", pgrepo.BlockerErr)
+	case pgrepo.DependencyCycleErr:
+		return http.StatusConflict, NewErr("blocker_id", "", pgrepo.DependencyCycleErr)
+	case pgrepo.DependencyErr:
+		return http.StatusNotFound, NewErr("blocker_id", "", pgrepo.DependencyErr)
+	case pgrepo.InvalidProjectIdErr:
+		return http.StatusUnprocessableEntity, NewErr("project_id", "", pgrepo.InvalidProjectIdErr)
+	case pgrepo.ProjectArchivedErr:
+		return http.StatusConflict, NewErr("project_id", "", pgrepo.ProjectArchivedErr)
+	case tasktodo.HistoryVersionErr:
+		return http.StatusUnprocessableEntity, NewErr("version", "", tasktodo.HistoryVersionErr)
+	case tasktodo.NotRecurringErr:
+		return http.StatusUnprocessableEntity, NewErr("id", taskID, tasktodo.NotRecurringErr)
+	case tasktodo.StateErr, tasktodo.TransitionErr:
+		return stateErrorResponse("", err)
+	case errBadPrecondition.Error():
+		return http.StatusBadRequest, NewErr("If-Match", r.Header.Get("If-Match"), errBadPrecondition.Error())
+	}
+	return http.StatusInternalServerError, NewErr("id", taskID, "action fail")
+}
+
+func stateErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, state tasktodo.State, err error) {
+	log.Warn().Err(err).Send()
+	status, resp := stateErrorResponse(state, err)
+	resp.Send(w, r, status)
+}
+
+// stateErrorResponse maps a workflow error about the state to the status and the body of the response.
+func stateErrorResponse(state tasktodo.State, err error) (int, ErrResp) {
+	if err.Error() == tasktodo.StateErr {
+		return http.StatusUnprocessableEntity, NewErr("state", string(state), tasktodo.StateErr)
 	}
+	return http.StatusConflict, NewErr("state", string(state), err.Error())
 }
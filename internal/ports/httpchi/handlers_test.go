@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/vlasashk/task-manager/config"
 	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
 	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/taskquery"
 	"github.com/vlasashk/task-manager/internal/models/tasktodo"
 	"github.com/vlasashk/task-manager/internal/ports/httpchi"
 	"io"
@@ -15,6 +19,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 type UnitTestSuite struct {
@@ -33,9 +38,26 @@ func (suite *UnitTestSuite) SetupTest() {
 		Description: "test",
 		DueDate:     "2024-10-26",
 		Status:      &stat,
+		State:       tasktodo.StateTodo,
 	}
 	suite.testTask = tasktodo.New(suite.taskReq)
 	suite.testTask.ID = "test"
+	suite.testTask.Tags = []string{}
+}
+
+// timePtr parses an RFC 3339 time keeping its offset.
+func timePtr(value string) (*time.Time, error) {
+	at, err := time.Parse(time.RFC3339, value)
+	return &at, err
+}
+
+// anyDueDate lets the fixed due dates of the tests pass the due date policy.
+var anyDueDate = httpchi.WithDueDatePolicy(tasktodo.NewDueDatePolicy(config.DueDateCfg{PastDays: -1}))
+
+// newRequest returns a request made in a session of a user, which is granted every scope.
+func newRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	return req.WithContext(account.WithUser(req.Context(), "user"))
 }
 
 type TestCase struct {
@@ -47,36 +69,69 @@ type TestCase struct {
 	reqBody       string
 	reqMethod     string
 	reqTarget     string
+	contentType   string
 }
 
 func (suite *UnitTestSuite) TestCreateTask() {
+	timedReq := suite.taskReq
+	timedReq.DueDate, timedReq.TimeZone = "2024-10-26T18:30:00+03:00", "Europe/Moscow"
+	timed := suite.testTask
+	timed.DueTime, timed.TimeZone = "18:30", "Europe/Moscow"
+	timed.DueAt, _ = timePtr("2024-10-26T18:30:00+03:00")
+	timed.CreatedAt, _ = timePtr("2024-10-20T09:00:00Z")
+	timed.UpdatedAt = timed.CreatedAt
 	testCases := []TestCase{
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("CreateTask", suite.taskReq).Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, timedReq).Return(timed, nil).Once()
 			},
 			expectedCode: http.StatusCreated,
-			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false}`,
-			reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
-			reqMethod:    "POST",
-			reqTarget:    "/task",
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","due_time":"18:30","time_zone":"Europe/Moscow",` +
+				`"status":false,"state":"todo","tags":[],"due_at":"2024-10-26T18:30:00+03:00","created_at":"2024-10-20T09:00:00Z","updated_at":"2024-10-20T09:00:00Z"}`,
+			reqBody:   `{"title":"test","description":"test","due_date":"2024-10-26T18:30:00+03:00","time_zone":"Europe/Moscow","status":false}`,
+			reqMethod: "POST",
+			reqTarget: "/task",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"title":"test","description":"test","due_date":"2024-10-26","time_zone":"Mars/Olympus","status":false}`,
+			reqMethod:     "POST",
+			reqTarget:     "/task",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"title":"test","description":"test","due_date":"2024-10-26","due_time":"25:00","status":false}`,
+			reqMethod:     "POST",
+			reqTarget:     "/task",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"date","value":"2024-10-26T18:30","error":"bad date format"}`,
+			reqBody:       `{"title":"test","description":"test","due_date":"2024-10-26T18:30","status":false}`,
+			reqMethod:     "POST",
+			reqTarget:     "/task",
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("CreateTask", suite.taskReq).Return(tasktodo.Task{}, errors.New("any err")).Once()
+				suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, suite.taskReq).Return(suite.testTask, nil).Once()
 			},
-			expectedCode: http.StatusInternalServerError,
-			expectedResp: `{"param":"id","error":"action fail"}`,
+			expectedCode: http.StatusCreated,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
 			reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
 			reqMethod:    "POST",
 			reqTarget:    "/task",
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("CreateTask", suite.taskReq).Return(tasktodo.Task{}, errors.New(pgrepo.DateErr)).Once()
+				suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, suite.taskReq).Return(tasktodo.Task{}, errors.New("any err")).Once()
 			},
-			expectedCode: http.StatusConflict,
-			expectedResp: `{"param":"date","value":"2024-10-26","error":"bad date"}`,
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","error":"action fail"}`,
 			reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
 			reqMethod:    "POST",
 			reqTarget:    "/task",
@@ -108,8 +163,8 @@ func (suite *UnitTestSuite) TestCreateTask() {
 	}
 	for _, tc := range testCases {
 		tc.storageOutput()
-		suite.service = httpchi.NewService(suite.storage)
-		req := httptest.NewRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		suite.service = httpchi.NewService(suite.storage, anyDueDate)
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
 		w := httptest.NewRecorder()
 
 		suite.service.CreateTask(w, req)
@@ -126,17 +181,36 @@ func (suite *UnitTestSuite) TestGetSingleTask() {
 	testCases := []TestCase{
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("GetTask", "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.Progress{}, nil).Once()
+				suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(0, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist(nil), nil).Once()
 			},
 			expectedCode: http.StatusOK,
-			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false}`,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
 			urlParamID:   "test",
 			reqMethod:    "GET",
 			reqTarget:    "/task",
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("GetTask", "test").Return(tasktodo.Task{}, errors.New("any err")).Once()
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetProgress", mock.Anything, "test").Return(tasktodo.NewProgress(3, 2), nil).Once()
+				suite.storage.(*mocks.Repo).On("CountComments", mock.Anything, "test").Return(2, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetChecklist", mock.Anything, "test").Return(tasktodo.NewChecklist([]tasktodo.ChecklistItem{
+					{ID: "i1", Text: "milk", Done: true}, {ID: "i2", Text: "bread"},
+				}), nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"progress":{"total":3,"done":2,"percent":66},"comment_count":2,` +
+				`"checklist":{"items":[{"id":"i1","text":"milk","done":true,"created_at":"0001-01-01T00:00:00Z"},{"id":"i2","text":"bread","done":false,"created_at":"0001-01-01T00:00:00Z"}],"done":1,"total":2,"progress":"1/2"}}`,
+			urlParamID: "test",
+			reqMethod:  "GET",
+			reqTarget:  "/task",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(tasktodo.Task{}, errors.New("any err")).Once()
 			},
 			expectedCode: http.StatusInternalServerError,
 			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
@@ -146,7 +220,7 @@ func (suite *UnitTestSuite) TestGetSingleTask() {
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("GetTask", "test").Return(tasktodo.Task{}, errors.New(pgrepo.InvalidIdErr)).Once()
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(tasktodo.Task{}, errors.New(pgrepo.InvalidIdErr)).Once()
 			},
 			expectedCode: http.StatusNotFound,
 			expectedResp: `{"message":"invalid task id"}`,
@@ -160,7 +234,7 @@ func (suite *UnitTestSuite) TestGetSingleTask() {
 		suite.service = httpchi.NewService(suite.storage)
 		ctx := chi.NewRouteContext()
 		ctx.URLParams.Add("id", tc.urlParamID)
-		req := httptest.NewRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
 		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 		w := httptest.NewRecorder()
 
@@ -178,7 +252,7 @@ func (suite *UnitTestSuite) TestDeleteTask() {
 	testCases := []TestCase{
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("DeleteTask", "test").Return(nil).Once()
+				suite.storage.(*mocks.Repo).On("DeleteTask", mock.Anything, "test", int64(0)).Return(nil).Once()
 			},
 			expectedCode: http.StatusOK,
 			expectedResp: `{"message":"success"}`,
@@ -188,7 +262,7 @@ func (suite *UnitTestSuite) TestDeleteTask() {
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("DeleteTask", "test").Return(errors.New("any err")).Once()
+				suite.storage.(*mocks.Repo).On("DeleteTask", mock.Anything, "test", int64(0)).Return(errors.New("any err")).Once()
 			},
 			expectedCode: http.StatusInternalServerError,
 			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
@@ -198,7 +272,7 @@ func (suite *UnitTestSuite) TestDeleteTask() {
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("DeleteTask", "test").Return(errors.New(pgrepo.InvalidIdErr)).Once()
+				suite.storage.(*mocks.Repo).On("DeleteTask", mock.Anything, "test", int64(0)).Return(errors.New(pgrepo.InvalidIdErr)).Once()
 			},
 			expectedCode: http.StatusNotFound,
 			expectedResp: `{"message":"invalid task id"}`,
@@ -212,7 +286,7 @@ func (suite *UnitTestSuite) TestDeleteTask() {
 		suite.service = httpchi.NewService(suite.storage)
 		ctx := chi.NewRouteContext()
 		ctx.URLParams.Add("id", tc.urlParamID)
-		req := httptest.NewRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
 		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 		w := httptest.NewRecorder()
 
@@ -230,10 +304,11 @@ func (suite *UnitTestSuite) TestUpdateTask() {
 	testCases := []TestCase{
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("UpdateTask", suite.taskReq, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, suite.taskReq, "test", int64(0)).Return(suite.testTask, nil).Once()
 			},
 			expectedCode: http.StatusOK,
-			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false}`,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
 			reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
 			urlParamID:   "test",
 			reqMethod:    "PUT",
@@ -241,7 +316,8 @@ func (suite *UnitTestSuite) TestUpdateTask() {
 		},
 		{
 			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("UpdateTask", suite.taskReq, "test").Return(tasktodo.Task{}, errors.New("any err")).Once()
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, suite.taskReq, "test", int64(0)).Return(tasktodo.Task{}, errors.New("any err")).Once()
 			},
 			expectedCode: http.StatusInternalServerError,
 			expectedResp: `{"param":"id","value":"test","error":"action fail"}`,
@@ -250,17 +326,6 @@ func (suite *UnitTestSuite) TestUpdateTask() {
 			reqMethod:    "PUT",
 			reqTarget:    "/task",
 		},
-		{
-			storageOutput: func() {
-				suite.storage.(*mocks.Repo).On("UpdateTask", suite.taskReq, "test").Return(tasktodo.Task{}, errors.New(pgrepo.DateErr)).Once()
-			},
-			expectedCode: http.StatusConflict,
-			expectedResp: `{"param":"date","value":"2024-10-26","error":"bad date"}`,
-			reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","status":false}`,
-			urlParamID:   "test",
-			reqMethod:    "PUT",
-			reqTarget:    "/task",
-		},
 		{
 			storageOutput: func() {},
 			expectedCode:  http.StatusBadRequest,
@@ -294,7 +359,7 @@ func (suite *UnitTestSuite) TestUpdateTask() {
 		suite.service = httpchi.NewService(suite.storage)
 		ctx := chi.NewRouteContext()
 		ctx.URLParams.Add("id", tc.urlParamID)
-		req := httptest.NewRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
 		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
 		w := httptest.NewRecorder()
 
@@ -308,11 +373,184 @@ func (suite *UnitTestSuite) TestUpdateTask() {
 	}
 }
 
+func (suite *UnitTestSuite) TestPatchTask() {
+	done := true
+	doneState := tasktodo.StateDone
+	title := "new"
+	zone := "Asia/Tokyo"
+	patched := suite.testTask
+	patched.SetState(tasktodo.StateDone)
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{Status: &done, State: &doneState}, "test", int64(0)).Return(patched, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":true,"state":"done","tags":[]}`,
+			reqBody:      `{"status":true}`,
+			urlParamID:   "test",
+			reqMethod:    "PATCH",
+			reqTarget:    "/task",
+			contentType:  "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{Title: &title}, "test", int64(0)).Return(tasktodo.Task{}, errors.New(pgrepo.InvalidIdErr)).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"invalid task id"}`,
+			reqBody:      `{"title":"new"}`,
+			urlParamID:   "test",
+			reqMethod:    "PATCH",
+			reqTarget:    "/task",
+			contentType:  "application/json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"date","value":"12345","error":"bad date format"}`,
+			reqBody:       `{"due_date":"12345"}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"param":"title","value":"null","error":"field cannot be removed"}`,
+			reqBody:       `{"title":null}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {
+				priority := tasktodo.PriorityP0
+				suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{Priority: &priority}, "test", int64(0)).Return(suite.testTask, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
+			reqBody:      `{"priority":"P0"}`,
+			urlParamID:   "test",
+			reqMethod:    "PATCH",
+			reqTarget:    "/task",
+			contentType:  "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"param":"priority","value":"null","error":"field cannot be removed"}`,
+			reqBody:       `{"priority":null}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{DueTime: new(string), TimeZone: &zone}, "test", int64(0)).
+					Return(suite.testTask, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
+			reqBody:      `{"due_time":null,"time_zone":"Asia/Tokyo"}`,
+			urlParamID:   "test",
+			reqMethod:    "PATCH",
+			reqTarget:    "/task",
+			contentType:  "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"due_time":"6pm"}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"time_zone":"MSK+3"}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"priority":"P5"}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{"description":""}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"error":"bad JSON"}`,
+			reqBody:       `{"fail"}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "application/merge-patch+json",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnsupportedMediaType,
+			expectedResp:  `{"param":"Content-Type","value":"text/plain","error":"unsupported media type"}`,
+			reqBody:       `{"status":true}`,
+			urlParamID:    "test",
+			reqMethod:     "PATCH",
+			reqTarget:     "/task",
+			contentType:   "text/plain",
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", tc.urlParamID)
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		req.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+
+		suite.service.PatchTask(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
+
 func (suite *UnitTestSuite) TestListTasks() {
 	type listTestCase struct {
-		date   string
-		status string
-		page   string
+		date    string
+		status  string
+		page    string
+		tag     []string
+		tagMode string
+		blocked string
 		TestCase
 	}
 	tasks := make([]tasktodo.Task, 0, 2)
@@ -322,6 +560,7 @@ func (suite *UnitTestSuite) TestListTasks() {
 	task2.Title += "2"
 	stat := true
 	task2.Status = &stat
+	task2.State = tasktodo.StateDone
 	tasks = append(tasks, suite.testTask, task2)
 	testCases := []listTestCase{
 		{
@@ -330,10 +569,10 @@ func (suite *UnitTestSuite) TestListTasks() {
 			page:   "",
 			TestCase: TestCase{
 				storageOutput: func() {
-					suite.storage.(*mocks.Repo).On("ListTasks", uint(0), "", "").Return(tasks, nil).Once()
+					suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny}).Return(tasks, nil).Once()
 				},
 				expectedCode: http.StatusOK,
-				expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false},{"id":"test2","title":"test2","description":"test2","due_date":"2024-10-26","status":true}]`,
+				expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]},{"id":"test2","title":"test2","description":"test2","due_date":"2024-10-26","status":true,"state":"done","tags":[]}]`,
 				reqMethod:    "GET",
 				reqTarget:    "/tasks?",
 			},
@@ -344,7 +583,7 @@ func (suite *UnitTestSuite) TestListTasks() {
 			page:   "1",
 			TestCase: TestCase{
 				storageOutput: func() {
-					suite.storage.(*mocks.Repo).On("ListTasks", uint(1), "2024-10-26", "true").Return([]tasktodo.Task{}, nil).Once()
+					suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, Page: 1, Date: "2024-10-26", Status: "true", TagMode: tasktodo.TagModeAny}).Return([]tasktodo.Task{}, nil).Once()
 				},
 				expectedCode: http.StatusNotFound,
 				expectedResp: `{"message":"nothing found"}`,
@@ -358,7 +597,7 @@ func (suite *UnitTestSuite) TestListTasks() {
 			page:   "1",
 			TestCase: TestCase{
 				storageOutput: func() {
-					suite.storage.(*mocks.Repo).On("ListTasks", uint(1), "2024-10-26", "true").Return(nil, errors.New("any error")).Once()
+					suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, Page: 1, Date: "2024-10-26", Status: "true", TagMode: tasktodo.TagModeAny}).Return(nil, errors.New("any error")).Once()
 				},
 				expectedCode: http.StatusInternalServerError,
 				expectedResp: `{"param":"id","error":"action fail"}`,
@@ -402,6 +641,52 @@ func (suite *UnitTestSuite) TestListTasks() {
 				reqTarget:     "/tasks?",
 			},
 		},
+		{
+			tag:     []string{"work, urgent", "home", "work"},
+			tagMode: "all",
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, Tags: []string{"home", "urgent", "work"}, TagMode: tasktodo.TagModeAll}).Return(tasks[:1], nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}]`,
+				reqMethod:    "GET",
+				reqTarget:    "/tasks?",
+			},
+		},
+		{
+			blocked: "true",
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, Blocked: "true", TagMode: tasktodo.TagModeAny}).Return([]tasktodo.Task{}, nil).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"nothing found"}`,
+				reqMethod:    "GET",
+				reqTarget:    "/tasks?",
+			},
+		},
+		{
+			blocked: "maybe",
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"blocked","value":"maybe","error":"bad blocked"}`,
+				reqMethod:     "GET",
+				reqTarget:     "/tasks?",
+			},
+		},
+		{
+			tag:     []string{"work"},
+			tagMode: "none",
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"tag_mode","value":"none","error":"bad tag mode"}`,
+				reqMethod:     "GET",
+				reqTarget:     "/tasks?",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		tc.storageOutput()
@@ -410,7 +695,16 @@ func (suite *UnitTestSuite) TestListTasks() {
 		params.Add("page", tc.page)
 		params.Add("date", tc.date)
 		params.Add("status", tc.status)
-		req := httptest.NewRequest(tc.reqMethod, tc.reqTarget+params.Encode(), strings.NewReader(tc.reqBody))
+		for _, tag := range tc.tag {
+			params.Add("tag", tag)
+		}
+		if tc.tagMode != "" {
+			params.Add("tag_mode", tc.tagMode)
+		}
+		if tc.blocked != "" {
+			params.Add("blocked", tc.blocked)
+		}
+		req := newRequest(tc.reqMethod, tc.reqTarget+params.Encode(), strings.NewReader(tc.reqBody))
 		w := httptest.NewRecorder()
 
 		suite.service.ListTasks(w, req)
@@ -423,6 +717,402 @@ func (suite *UnitTestSuite) TestListTasks() {
 	}
 }
 
+func (suite *UnitTestSuite) TestListTasksByCursor() {
+	pages, err := tasktodo.NewPaginator(config.PaginationCfg{CursorSecret: "secret", DefaultLimit: 1, MaxLimit: 5})
+	suite.Require().NoError(err)
+	task := suite.testTask
+	task.Priority = tasktodo.PriorityP1
+	task2 := task
+	task2.ID += "2"
+	cursor := tasktodo.Cursor{Priority: tasktodo.PriorityP1, DueDate: "2024-10-26", ID: "test"}
+	next := pages.Encode(cursor)
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","priority":"P1","tags":[]}`
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 1, Lookahead: 1, TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.Task{task, task2}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"tasks":[` + taskResp + `],"next_cursor":"` + next + `"}`,
+			reqTarget:    "/tasks?cursor=",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 5, Lookahead: 1, TagMode: tasktodo.TagModeAny, After: &cursor}).
+					Return([]tasktodo.Task{}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"tasks":[]}`,
+			reqTarget:    "/tasks?limit=5&cursor=" + next,
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 3, Lookahead: 1, Page: 1, TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.Task{task}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"tasks":[` + taskResp + `]}`,
+			reqTarget:    "/tasks?limit=3&page=1",
+		},
+		{
+			// the page is still 3 tasks long, the extra task is looked ahead past it rather than counted in
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 3, Lookahead: 1, Page: 2, TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.Task{task, task, task, task2}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"tasks":[` + taskResp + `,` + taskResp + `,` + taskResp + `],"next_cursor":"` + next + `"}`,
+			reqTarget:    "/tasks?limit=3&page=2",
+		},
+		{
+			// sorted tasks get no cursor since it holds the position in the default order only
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 1, Lookahead: 1, TagMode: tasktodo.TagModeAny,
+					Sort: []tasktodo.SortField{{Field: "title"}}}).
+					Return([]tasktodo.Task{task, task2}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"tasks":[` + taskResp + `]}`,
+			reqTarget:    "/tasks?sort=title&limit=1",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"cursor","error":"invalid cursor"}`,
+			reqTarget:     "/tasks?cursor=" + next + "x",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"page","value":"1","error":"page can not be combined with cursor"}`,
+			reqTarget:     "/tasks?page=1&cursor=" + next,
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"limit","value":"6","error":"limit must be between 1 and 5"}`,
+			reqTarget:     "/tasks?limit=6",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"limit","value":"0","error":"limit must be between 1 and 5"}`,
+			reqTarget:     "/tasks?limit=0",
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, httpchi.WithPagination(pages))
+		w := httptest.NewRecorder()
+
+		suite.service.ListTasks(w, newRequest("GET", tc.reqTarget, nil))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestListTasksFilters() {
+	taskResp := `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}]`
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					DueFrom: "2024-10-01", DueTo: "2024-10-31", Overdue: "false", TitlePrefix: "te_",
+					Sort: []tasktodo.SortField{{Field: "state", Desc: true}, {Field: "title"}, {Field: "created_at"}}}).
+					Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?due_from=2024-10-01&due_to=2024-10-31&overdue=false&title_prefix=+te_+&sort=-state,%2Btitle&sort=created_at",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					DueFrom: "2024-10-26", DueTo: "2024-10-26"}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?due_from=2024-10-26T10:00:00%2B03:00&due_to=2024-10-26",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					CreatedAfter: "2024-10-26T10:00:00+03:00"}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?created_after=2024-10-26T10:00:00%2B03:00",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					CreatedAfter: "2024-10-26"}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?created_after=2024-10-26",
+		},
+		{
+			storageOutput: func() {
+				query := taskquery.And{Terms: []taskquery.Node{
+					taskquery.Match{Field: "status", Op: taskquery.OpEq, Value: "open", At: 1},
+					taskquery.Match{Field: "tag", Op: taskquery.OpEq, Value: "backend", At: 13},
+					taskquery.Match{Field: "due", Op: taskquery.OpLt, Value: "2025-01-01", At: 25},
+					taskquery.Text{Value: "release notes", Phrase: true, At: 40},
+				}}
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					Query: query}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?q=" + url.QueryEscape(`status:open tag:backend due<2025-01-01 "release notes"`),
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"q","value":"doing","error":"state must be one of todo, in_progress, blocked, in_review, done, cancelled at position 19"}`,
+			reqTarget:     "/tasks?q=" + url.QueryEscape(`tag:backend state:doing`),
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"q","error":"empty query at position 1"}`,
+			reqTarget:     "/tasks?q=",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"due_to","value":"2024-10","error":"bad date format"}`,
+			reqTarget:     "/tasks?due_to=2024-10",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"due_to","value":"2024-10-01","error":"due_to is before due_from"}`,
+			reqTarget:     "/tasks?due_from=2024-10-31&due_to=2024-10-01",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"overdue","value":"yes","error":"bad overdue"}`,
+			reqTarget:     "/tasks?overdue=yes",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					Priorities: []tasktodo.Priority{tasktodo.PriorityP0, tasktodo.PriorityP1},
+					Sort:       []tasktodo.SortField{{Field: "priority", Desc: true}}}).
+					Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?priority=P0,p1&priority=P0&sort=-priority",
+		},
+		{
+			storageOutput: func() {
+				query := taskquery.Match{Field: "priority", Op: taskquery.OpEq, Value: "p0", At: 1}
+				suite.storage.(*mocks.Repo).On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny,
+					Query: query}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: taskResp,
+			reqTarget:    "/tasks?q=" + url.QueryEscape(`priority:P0`),
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"priority","value":"P1,high","error":"unknown priority"}`,
+			reqTarget:     "/tasks?priority=P1,high",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"created_after","value":"yesterday","error":"bad created_after"}`,
+			reqTarget:     "/tasks?created_after=yesterday",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"sort","value":"id","error":"bad sort"}`,
+			reqTarget:     "/tasks?sort=id",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"sort","value":"title","error":"sort can not be combined with cursor"}`,
+			reqTarget:     "/tasks?sort=title&cursor=",
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		w := httptest.NewRecorder()
+
+		suite.service.ListTasks(w, newRequest("GET", tc.reqTarget, nil))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestDueDatePolicy() {
+	type dueTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		user    string
+		TestCase
+	}
+	past := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	future := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	body := func(date string) string {
+		return `{"title":"test","description":"test","due_date":"` + date + `","status":false}`
+	}
+	request := func(date string) tasktodo.Request {
+		req := suite.taskReq
+		req.DueDate = date
+		return req
+	}
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`
+	create := func(s httpchi.Service) http.HandlerFunc { return s.CreateTask }
+	update := func(s httpchi.Service) http.HandlerFunc { return s.UpdateTask }
+	patch := func(s httpchi.Service) http.HandlerFunc { return s.PatchTask }
+	testCases := []dueTestCase{
+		{
+			handler: create,
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"due_date","value":"` + past + `","error":"due date is in the past"}`,
+				reqBody:       body(past),
+				reqTarget:     "/task",
+			},
+		},
+		{
+			handler: create,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, request(yesterday)).Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: taskResp,
+				reqBody:      body(yesterday),
+				reqTarget:    "/task",
+			},
+		},
+		{
+			handler: create,
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusForbidden,
+				expectedResp:  `{"param":"import","value":"true","error":"only admins may import tasks"}`,
+				reqBody:       body(past),
+				reqTarget:     "/task?import=true",
+			},
+		},
+		{
+			handler: create,
+			user:    "admin",
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, request("2020-01-01")).Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: taskResp,
+				reqBody:      body("2020-01-01"),
+				reqTarget:    "/task?import=true",
+			},
+		},
+		{
+			handler: create,
+			user:    "admin",
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"import","value":"maybe","error":"bad import"}`,
+				reqBody:       body(past),
+				reqTarget:     "/task?import=maybe",
+			},
+		},
+		{
+			handler: update,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"due_date","value":"` + past + `","error":"due date is in the past"}`,
+				reqBody:      body(past),
+				reqTarget:    "/task/test",
+			},
+		},
+		{
+			handler: update,
+			TestCase: TestCase{
+				storageOutput: func() {
+					done := true
+					req := suite.taskReq
+					req.SetState(tasktodo.StateDone)
+					completed := suite.testTask
+					completed.Status = &done
+					completed.State = tasktodo.StateDone
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+					suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, req, "test", int64(0)).Return(completed, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":true,"state":"done","tags":[]}`,
+				reqBody:      `{"title":"test","description":"test","due_date":"2024-10-26","state":"done"}`,
+				reqTarget:    "/task/test",
+			},
+		},
+		{
+			handler: patch,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"due_date","value":"` + past + `","error":"due date is in the past"}`,
+				reqBody:      `{"due_date":"` + past + `"}`,
+				reqTarget:    "/task/test",
+			},
+		},
+		{
+			handler: patch,
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+					suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{DueDate: &future}, "test", int64(0)).Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: taskResp,
+				reqBody:      `{"due_date":"` + future + `"}`,
+				reqTarget:    "/task/test",
+			},
+		},
+	}
+	policy := httpchi.WithDueDatePolicy(tasktodo.NewDueDatePolicy(config.DueDateCfg{PastDays: 1, Admins: []string{"admin"}}))
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage, policy)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := httptest.NewRequest("POST", tc.reqTarget, strings.NewReader(tc.reqBody))
+		user := tc.user
+		if user == "" {
+			user = "user"
+		}
+		req = req.WithContext(context.WithValue(account.WithUser(req.Context(), user), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code, tc.reqTarget)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()), tc.reqTarget)
+	}
+}
+
 func TestUnitTestSuite(t *testing.T) {
 	suite.Run(t, new(UnitTestSuite))
 }
@@ -0,0 +1,137 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"time"
+)
+
+// ListHistory returns the change history of a task.
+//
+//	@Summary		Returns the history of a task
+//	@Description	Retrieves the recorded changes of a task, the latest first: who made them, within which request and the changed fields before and after.
+//	@Description	The history is kept for deleted and purged tasks too
+//	@Tags			History
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string				true	"Task ID"
+//	@Param			page	query		string				false	"Page number for pagination"
+//	@Param			limit	query		int					false	"Page size, limited by the server"
+//	@Success		200		{object}	[]tasktodo.Event	"Task history"
+//	@Failure		400		{object}	ErrResp				"Invalid request parameters"
+//	@Failure		401		{object}	ErrResp				"Missing credentials"
+//	@Failure		403		{object}	ErrResp				"Scope tasks:read not granted"
+//	@Failure		404		{object}	MsgResp				"Task not found"
+//	@Router			/task/{id}/history [get]
+func (s Service) ListHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	params, errResp, err := s.pageParams(r.URL.Query())
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	events, err := s.DB.ListHistory(r.Context(), taskID, params)
+	if err != nil {
+		errorHandler(w, r, log.With().Str("id", taskID).Logger(), "", taskID, err)
+		return
+	}
+	log.Info().Int("amount", len(events)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, events)
+}
+
+// RevertTask brings a task back to a previous version.
+//
+//	@Summary		Reverts a task to a previous version
+//	@Description	Sets the fields of the task to the ones it had at the version according to its history, the revert is a new version itself.
+//	@Description	The recurrence is kept as it is, the state change has to be allowed by the workflow
+//	@Tags			History
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Task ID"
+//	@Param			If-Match		header		string					false	"Expected task ETag"
+//	@Param			If-None-Match	header		string					false	"Task ETag that must not match"
+//	@Param			revert			body		tasktodo.RevertRequest	true	"Version to revert to"
+//	@Success		200				{object}	tasktodo.Task			"Task successfully reverted"
+//	@Header			200				{string}	ETag					"New task version"
+//	@Failure		400				{object}	ErrResp					"Incorrect JSON or malformed precondition header"
+//	@Failure		401				{object}	ErrResp					"Missing credentials"
+//	@Failure		403				{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp					"Task not found"
+//	@Failure		409				{object}	ErrResp					"Transition is not allowed, hierarchy cycle, archived project or concurrent update"
+//	@Failure		412				{object}	ErrResp					"Task version does not match"
+//	@Failure		422				{object}	ErrResp					"Invalid JSON, version not in the history, due date changed to the past, missing parent task or project"
+//	@Router			/task/{id}/revert [post]
+func (s Service) RevertTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	var revert tasktodo.RevertRequest
+	if err := render.DecodeJSON(r.Body, &revert); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(revert); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Int64("version", revert.Version).Logger()
+	current, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	version, err := preconditions(r, current)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	snapshot, err := s.DB.GetTaskVersion(r.Context(), taskID, revert.Version)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	if err = s.DueDates.Check(snapshot.DueDate, snapshot.TimeZone, current.DueDate, time.Now()); err != nil {
+		errorHandler(w, r, logID, snapshot.DueDate, taskID, err)
+		return
+	}
+	state, err := s.Workflow.Resolve(current.State, snapshot.State, snapshot.Status)
+	if err != nil {
+		stateErrorHandler(w, r, logID, snapshot.State, err)
+		return
+	}
+	if err = s.Workflow.Check(current.State, state); err != nil {
+		stateErrorHandler(w, r, logID, state, err)
+		return
+	}
+	taskUpd := tasktodo.Reverted(snapshot)
+	taskUpd.SetState(state)
+	task, err := s.DB.RevertTask(r.Context(), taskUpd, taskID, version)
+	if err != nil {
+		errorHandler(w, r, logID, taskUpd.DueDate, taskID, err)
+		return
+	}
+	logID.Info().Msg("task reverted successfully")
+	w.Header().Set("ETag", etag(task.Version))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, task)
+}
@@ -0,0 +1,142 @@
+package httpchi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestHistoryHandlers() {
+	type historyTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	event := tasktodo.Event{ID: 7, TaskID: "test", Action: tasktodo.ActionUpdate, Version: 2, Actor: "user", RequestID: "host/abc-000001",
+		Before:    map[string]json.RawMessage{"title": json.RawMessage(`"old"`)},
+		After:     map[string]json.RawMessage{"title": json.RawMessage(`"test"`)},
+		CreatedAt: time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)}
+	current := suite.testTask
+	current.Version = 3
+	snapshot := suite.taskReq
+	snapshot.Title = "old"
+	snapshot.Tags = []string{"backend"}
+	reverted := tasktodo.Reverted(snapshot)
+	reverted.SetState(tasktodo.StateTodo)
+	revertedTask := tasktodo.Task{ID: "test", Request: reverted, Version: 4}
+	blocked := current
+	blocked.State = tasktodo.StateBlocked
+	inReview := snapshot
+	inReview.State = tasktodo.StateInReview
+	testCases := []historyTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListHistory },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListHistory", mock.Anything, "test", tasktodo.ListParams{Limit: 10}).
+						Return([]tasktodo.Event{event}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":7,"task_id":"test","action":"update","version":2,"actor":"user","request_id":"host/abc-000001",` +
+					`"before":{"title":"old"},"after":{"title":"test"},"created_at":"2024-10-20T12:00:00Z"}]`,
+				reqTarget: "/task/test/history",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListHistory },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListHistory", mock.Anything, "test", tasktodo.ListParams{Limit: 10}).
+						Return(nil, errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid task id"}`,
+				reqTarget:    "/task/test/history",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListHistory },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"limit","value":"0","error":"limit must be between 1 and 100"}`,
+				reqTarget:     "/task/test/history?limit=0",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RevertTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetTaskVersion", mock.Anything, "test", int64(2)).Return(snapshot, nil).Once()
+					suite.storage.(*mocks.Repo).On("RevertTask", mock.Anything, reverted, "test", int64(3)).Return(revertedTask, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"old","description":"test","due_date":"2024-10-26","status":false,"state":"todo",` +
+					`"tags":["backend"],"parent_id":"","project_id":"","version":4}`,
+				reqBody: `{"version":2}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RevertTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetTaskVersion", mock.Anything, "test", int64(9)).
+						Return(tasktodo.Request{}, errors.New(tasktodo.HistoryVersionErr)).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"version","error":"version is not in the task history"}`,
+				reqBody:      `{"version":9}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RevertTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(blocked, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetTaskVersion", mock.Anything, "test", int64(2)).Return(inReview, nil).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"state","value":"in_review","error":"transition not allowed"}`,
+				reqBody:      `{"version":2}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RevertTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"version":0}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		target := "/task/test/revert"
+		if tc.reqTarget != "" {
+			target = tc.reqTarget
+		}
+		req := newRequest("POST", target, strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
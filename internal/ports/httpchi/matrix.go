@@ -0,0 +1,69 @@
+package httpchi
+
+import (
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strconv"
+)
+
+// maxUrgentDays bounds how far ahead a task can be due to count as urgent.
+const maxUrgentDays = 365
+
+// GetMatrix returns open tasks bucketed into the Eisenhower matrix.
+//
+//	@Summary		Returns the Eisenhower matrix of open tasks
+//	@Description	Buckets open tasks by importance and urgency. Tasks of priority P0 and P1 are important,
+//	@Description	tasks due within urgent_days days or overdue are urgent. Every quadrant holds up to limit tasks
+//	@Description	ordered by priority and due date along with the total number of its tasks
+//	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			project		query		string			false	"Project ID, or none for tasks outside any project"
+//	@Param			urgent_days	query		int				false	"Days ahead a task is due to be urgent"	default(2)
+//	@Param			limit		query		int				false	"Number of tasks per quadrant, limited by the server"
+//	@Success		200			{object}	tasktodo.Matrix	"Tasks by quadrant"
+//	@Failure		400			{object}	ErrResp			"Invalid request parameters"
+//	@Failure		401			{object}	ErrResp			"Missing credentials"
+//	@Failure		403			{object}	ErrResp			"Scope tasks:read not granted"
+//	@Router			/tasks/matrix [get]
+func (s Service) GetMatrix(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	query := r.URL.Query()
+	limit, errResp, err := s.pageLimit(query)
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	params := tasktodo.MatrixParams{ProjectID: query.Get("project"), UrgentDays: tasktodo.DefaultUrgentDays, Limit: limit}
+	if days := query.Get("urgent_days"); days != "" {
+		temp, err := strconv.Atoi(days)
+		if err == nil && (temp < 0 || temp > maxUrgentDays) {
+			err = errors.New("urgent days out of range")
+		}
+		if err != nil {
+			log.Error().Err(err).Send()
+			NewErr("urgent_days", days, "bad urgent_days").Send(w, r, http.StatusBadRequest)
+			return
+		}
+		params.UrgentDays = temp
+	}
+	log.Info().Str("project", params.ProjectID).Int("urgent_days", params.UrgentDays).Uint("limit", limit).Msg("params received")
+	matrix, err := s.DB.GetMatrix(r.Context(), params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	log.Info().Int("do", matrix.Do.Total).Int("schedule", matrix.Schedule.Total).Int("delegate", matrix.Delegate.Total).
+		Int("eliminate", matrix.Eliminate.Total).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, matrix)
+}
@@ -0,0 +1,79 @@
+package httpchi_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestGetMatrix() {
+	urgent := suite.testTask
+	urgent.Priority = tasktodo.PriorityP0
+	matrix := tasktodo.NewMatrix()
+	do := matrix.Quadrant(true, true)
+	do.Tasks, do.Total = []tasktodo.Task{urgent}, 3
+	matrix.Quadrant(false, false).Total = 1
+	emptyResp := `{"tasks":[],"total":0}`
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetMatrix", mock.Anything, tasktodo.MatrixParams{UrgentDays: 2, Limit: 10}).Return(matrix, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"do":{"tasks":[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","priority":"P0","tags":[]}],"total":3},` +
+				`"schedule":` + emptyResp + `,"delegate":` + emptyResp + `,"eliminate":{"tasks":[],"total":1}}`,
+			reqTarget: "/tasks/matrix",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetMatrix", mock.Anything, tasktodo.MatrixParams{ProjectID: "p1", UrgentDays: 0, Limit: 5}).
+					Return(tasktodo.NewMatrix(), nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"do":` + emptyResp + `,"schedule":` + emptyResp + `,"delegate":` + emptyResp + `,"eliminate":` + emptyResp + `}`,
+			reqTarget:    "/tasks/matrix?project=p1&urgent_days=0&limit=5",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetMatrix", mock.Anything, tasktodo.MatrixParams{UrgentDays: 7, Limit: 10}).
+					Return(tasktodo.Matrix{}, errors.New("any error")).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","error":"action fail"}`,
+			reqTarget:    "/tasks/matrix?urgent_days=7",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"urgent_days","value":"-1","error":"bad urgent_days"}`,
+			reqTarget:     "/tasks/matrix?urgent_days=-1",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"urgent_days","value":"soon","error":"bad urgent_days"}`,
+			reqTarget:     "/tasks/matrix?urgent_days=soon",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"limit","value":"0","error":"limit must be between 1 and 100"}`,
+			reqTarget:     "/tasks/matrix?limit=0",
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		w := httptest.NewRecorder()
+
+		suite.service.GetMatrix(w, newRequest("GET", tc.reqTarget, nil))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
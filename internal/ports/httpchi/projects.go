@@ -0,0 +1,378 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CreateProject creates a new project.
+//
+//	@Summary		Creates a new project
+//	@Description	Creates a project to group tasks of the user
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			projectRequest	body		tasktodo.ProjectRequest	true	"Data of the new project"
+//	@Success		201				{object}	tasktodo.Project		"Project successfully created"
+//	@Failure		400				{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401				{object}	ErrResp					"Missing credentials"
+//	@Failure		403				{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		422				{object}	ErrResp					"Invalid JSON"
+//	@Router			/projects [post]
+func (s Service) CreateProject(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectRequest, ok := decodeProject(w, r, log)
+	if !ok {
+		return
+	}
+	project, err := s.DB.CreateProject(r.Context(), projectRequest)
+	if err != nil {
+		projectErrorHandler(w, r, log, "", err)
+		return
+	}
+	log.Info().Str("id", project.ID).Msg("project created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, project)
+}
+
+// ListProjects returns projects of the user.
+//
+//	@Summary		Returns projects
+//	@Description	Retrieves projects with task counts ordered by name, archived projects are included on demand
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			archived	query		string				false	"Whether to include archived projects (true/false)"
+//	@Success		200			{object}	[]tasktodo.Project	"List of projects"
+//	@Failure		400			{object}	ErrResp				"Invalid request parameters"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:read not granted"
+//	@Router			/projects [get]
+func (s Service) ListProjects(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	var archived bool
+	if param := r.URL.Query().Get("archived"); param != "" {
+		var err error
+		if archived, err = strconv.ParseBool(param); err != nil {
+			log.Error().Err(err).Send()
+			NewErr("archived", param, "bad archived").Send(w, r, http.StatusBadRequest)
+			return
+		}
+	}
+	projects, err := s.DB.ListProjects(r.Context(), archived)
+	if err != nil {
+		projectErrorHandler(w, r, log, "", err)
+		return
+	}
+	log.Info().Int("amount", len(projects)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, projects)
+}
+
+// GetProject returns a project by ID.
+//
+//	@Summary		Returns a project by ID
+//	@Description	Retrieves a project with counts of its tasks by state
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			pid	path		string				true	"Project ID"
+//	@Success		200	{object}	tasktodo.Project	"Project details"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp				"Project not found"
+//	@Router			/projects/{pid} [get]
+func (s Service) GetProject(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectID := chi.URLParam(r, "pid")
+	project, err := s.DB.GetProject(r.Context(), projectID)
+	if err != nil {
+		projectErrorHandler(w, r, log, projectID, err)
+		return
+	}
+	log.Info().Str("id", project.ID).Msg("project found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, project)
+}
+
+// UpdateProject updates a project by ID.
+//
+//	@Summary		Updates a project
+//	@Description	Replaces name and description of the project
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			pid				path		string					true	"Project ID"
+//	@Param			projectRequest	body		tasktodo.ProjectRequest	true	"New data of the project"
+//	@Success		200				{object}	tasktodo.Project		"Project successfully updated"
+//	@Failure		400				{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401				{object}	ErrResp					"Missing credentials"
+//	@Failure		403				{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404				{object}	MsgResp					"Project not found"
+//	@Failure		422				{object}	ErrResp					"Invalid JSON"
+//	@Router			/projects/{pid} [put]
+func (s Service) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectID := chi.URLParam(r, "pid")
+	projectRequest, ok := decodeProject(w, r, log)
+	if !ok {
+		return
+	}
+	project, err := s.DB.UpdateProject(r.Context(), projectRequest, projectID)
+	if err != nil {
+		projectErrorHandler(w, r, log, projectID, err)
+		return
+	}
+	log.Info().Str("id", project.ID).Msg("project updated successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, project)
+}
+
+// DeleteProject deletes a project by ID.
+//
+//	@Summary		Deletes a project
+//	@Description	Deletes the project, its tasks are kept outside any project
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			pid	path		string	true	"Project ID"
+//	@Success		200	{object}	MsgResp	"Project deleted"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"Project not found"
+//	@Router			/projects/{pid} [delete]
+func (s Service) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectID := chi.URLParam(r, "pid")
+	if err := s.DB.DeleteProject(r.Context(), projectID); err != nil {
+		projectErrorHandler(w, r, log, projectID, err)
+		return
+	}
+	log.Info().Str("id", projectID).Msg("project deleted successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
+
+// ArchiveProject archives a project by ID.
+//
+//	@Summary		Archives a project
+//	@Description	Archives the project as a whole: its tasks are left out of task listings and no tasks can be added to it
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			pid	path		string				true	"Project ID"
+//	@Success		200	{object}	tasktodo.Project	"Project archived"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404	{object}	MsgResp				"Project not found"
+//	@Router			/projects/{pid}/archive [post]
+func (s Service) ArchiveProject(w http.ResponseWriter, r *http.Request) {
+	s.archiveProject(w, r, true)
+}
+
+// UnarchiveProject restores an archived project by ID.
+//
+//	@Summary		Restores an archived project
+//	@Description	Brings the project and its tasks back to task listings
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			pid	path		string				true	"Project ID"
+//	@Success		200	{object}	tasktodo.Project	"Project restored"
+//	@Failure		401	{object}	ErrResp				"Missing credentials"
+//	@Failure		403	{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404	{object}	MsgResp				"Project not found"
+//	@Router			/projects/{pid}/unarchive [post]
+func (s Service) UnarchiveProject(w http.ResponseWriter, r *http.Request) {
+	s.archiveProject(w, r, false)
+}
+
+// CreateProjectTask creates a new task in a project.
+//
+//	@Summary		Creates a new task in a project
+//	@Description	Creates a task the same way as POST /task, the project is taken from the path
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			pid			path		string				true	"Project ID"
+//	@Param			taskRequest	body		tasktodo.Request	true	"Data of the new task"
+//	@Success		201			{object}	tasktodo.Task		"Task successfully created"
+//	@Header			201			{string}	ETag				"Task version"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON or invalid date format"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		409			{object}	ErrResp				"Project is archived"
+//	@Failure		422			{object}	ErrResp				"Invalid JSON, unknown state, parent task, project or recurrence rule"
+//	@Router			/projects/{pid}/tasks [post]
+func (s Service) CreateProjectTask(w http.ResponseWriter, r *http.Request) {
+	s.CreateTask(w, r)
+}
+
+// ListProjectTasks returns tasks of a project.
+//
+//	@Summary		Returns tasks of a project
+//	@Description	Retrieves tasks of the project with the same filtering, pagination and v2 envelope as GET /tasks, archived projects included
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			pid			path		string			true	"Project ID"
+//	@Param			status		query		string			false	"Task completion status (true/false)"
+//	@Param			date		query		string			false	"Task date (format: YYYY-MM-DD)"
+//	@Param			page		query		string			false	"Page number for pagination"
+//	@Param			tag			query		[]string		false	"Tag names, repeated or comma separated"		collectionFormat(multi)
+//	@Param			tag_mode	query		string			false	"Whether a task needs any or all of the tags"	Enums(any, all)	default(any)
+//	@Param			blocked		query		string			false	"Whether a task has open blockers (true/false)"
+//	@Param			due_from	query		string			false	"Earliest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			due_to		query		string			false	"Latest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			overdue		query		string			false	"Whether a task is past its due time and still open (true/false)"
+//	@Param			created_after	query		string			false	"Creation time lower bound, RFC 3339 or YYYY-MM-DD"
+//	@Param			title_prefix	query		string			false	"Case-insensitive title prefix"
+//	@Param			sort		query		[]string			false	"Sort fields, repeated or comma separated, "-" prefix for descending order; can not be combined with cursor"	collectionFormat(multi)
+//	@Param			q			query		string			false	"Task query, e.g. status:open tag:backend due<2025-01-01 release"
+//	@Success		200			{object}	[]tasktodo.Task	"List of tasks"
+//	@Failure		400			{object}	ErrResp			"Invalid request parameters"
+//	@Failure		401			{object}	ErrResp			"Missing credentials"
+//	@Failure		403			{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404			{object}	MsgResp			"Project or tasks not found"
+//	@Router			/projects/{pid}/tasks [get]
+//	@Router			/v2/projects/{pid}/tasks [get]
+func (s Service) ListProjectTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	projectID := chi.URLParam(r, "pid")
+	if _, err := s.DB.GetProject(r.Context(), projectID); err != nil {
+		projectErrorHandler(w, r, *zerolog.Ctx(r.Context()), projectID, err)
+		return
+	}
+	s.ListTasks(w, r)
+}
+
+// MoveTasks moves tasks into a project.
+//
+//	@Summary		Moves tasks into a project
+//	@Description	Moves up to 100 tasks into the project in a single transaction: either all of them are moved or none
+//	@Tags			Projects
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			pid			path		string					true	"Project ID"
+//	@Param			moveRequest	body		tasktodo.MoveRequest	true	"Tasks to move"
+//	@Success		200			{object}	[]tasktodo.Task			"Moved tasks"
+//	@Failure		400			{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp					"Project not found"
+//	@Failure		409			{object}	ErrResp					"Project is archived"
+//	@Failure		422			{object}	ErrResp					"Invalid JSON or unknown task"
+//	@Router			/projects/{pid}/tasks/move [post]
+func (s Service) MoveTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectID := chi.URLParam(r, "pid")
+	moveRequest := tasktodo.MoveRequest{}
+	if err := render.DecodeJSON(r.Body, &moveRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(moveRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	tasks, err := s.DB.MoveTasks(r.Context(), projectID, moveRequest.TaskIDs)
+	if err != nil {
+		projectErrorHandler(w, r, log, projectID, err)
+		return
+	}
+	log.Info().Str("id", projectID).Int("amount", len(tasks)).Msg("tasks moved successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tasks)
+}
+
+func (s Service) archiveProject(w http.ResponseWriter, r *http.Request, archived bool) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	projectID := chi.URLParam(r, "pid")
+	project, err := s.DB.ArchiveProject(r.Context(), projectID, archived)
+	if err != nil {
+		projectErrorHandler(w, r, log, projectID, err)
+		return
+	}
+	log.Info().Str("id", projectID).Bool("archived", archived).Msg("project archive state changed successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, project)
+}
+
+func decodeProject(w http.ResponseWriter, r *http.Request, log zerolog.Logger) (tasktodo.ProjectRequest, bool) {
+	projectRequest := tasktodo.ProjectRequest{}
+	if err := render.DecodeJSON(r.Body, &projectRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return tasktodo.ProjectRequest{}, false
+	}
+	projectRequest.Name = strings.TrimSpace(projectRequest.Name)
+	if err := validator.New().Struct(projectRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return tasktodo.ProjectRequest{}, false
+	}
+	return projectRequest, true
+}
+
+func projectErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, projectID string, err error) {
+	switch err.Error() {
+	case pgrepo.InvalidProjectIdErr:
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidProjectIdErr).Send(w, r, http.StatusNotFound)
+	case pgrepo.ProjectArchivedErr:
+		log.Warn().Err(err).Send()
+		NewErr("id", projectID, pgrepo.ProjectArchivedErr).Send(w, r, http.StatusConflict)
+	case pgrepo.InvalidIdErr:
+		log.Warn().Err(err).Send()
+		NewErr("task_ids", "", pgrepo.InvalidIdErr).Send(w, r, http.StatusUnprocessableEntity)
+	default:
+		log.Error().Err(err).Send()
+		NewErr("id", projectID, "action fail").Send(w, r, http.StatusInternalServerError)
+	}
+}
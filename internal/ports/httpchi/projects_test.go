@@ -0,0 +1,221 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestProjectHandlers() {
+	type projectTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	project := tasktodo.Project{ID: "test", ProjectRequest: tasktodo.ProjectRequest{Name: "home"},
+		Counts: tasktodo.ProjectCounts{Total: 3, Open: 1, Done: 1, Cancelled: 1}}
+	projectResp := `{"id":"test","name":"home","description":"","counts":{"total":3,"open":1,"done":1,"cancelled":1}}`
+	moved := suite.testTask
+	projectID := "test"
+	moved.ProjectID = &projectID
+	testCases := []projectTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateProject },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateProject", mock.Anything, project.ProjectRequest).Return(project, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: projectResp,
+				reqBody:      `{"name":" home "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateProject },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"name":"  "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListProjects },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListProjects", mock.Anything, true).Return([]tasktodo.Project{project}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[` + projectResp + `]`,
+				reqTarget:    "/projects?archived=true",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListProjects },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"archived","value":"fail","error":"bad archived"}`,
+				reqTarget:     "/projects?archived=fail",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.GetProject },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetProject", mock.Anything, "test").Return(tasktodo.Project{}, errors.New(pgrepo.InvalidProjectIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid project id"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateProject },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("UpdateProject", mock.Anything, project.ProjectRequest, "test").Return(project, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: projectResp,
+				reqBody:      `{"name":"home"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteProject },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("DeleteProject", mock.Anything, "test").Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UnarchiveProject },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ArchiveProject", mock.Anything, "test", false).Return(project, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: projectResp,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.MoveTasks },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("MoveTasks", mock.Anything, "test", []string{"test"}).Return([]tasktodo.Task{moved}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"project_id":"test"}]`,
+				reqBody:      `{"task_ids":["test"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.MoveTasks },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("MoveTasks", mock.Anything, "test", []string{"test", "other"}).
+						Return(nil, errors.New(pgrepo.ProjectArchivedErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"id","value":"test","error":"project is archived"}`,
+				reqBody:      `{"task_ids":["test","other"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.MoveTasks },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("MoveTasks", mock.Anything, "test", []string{"test", "missing"}).
+						Return(nil, errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"task_ids","error":"invalid task id"}`,
+				reqBody:      `{"task_ids":["test","missing"]}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.MoveTasks },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"task_ids":[]}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("pid", "test")
+		target := "/projects"
+		if tc.reqTarget != "" {
+			target = tc.reqTarget
+		}
+		req := newRequest("POST", target, strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestProjectTasks() {
+	projectID := "test"
+	req := suite.taskReq
+	req.ProjectID = &projectID
+	task := suite.testTask
+	task.ProjectID = &projectID
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"project_id":"test"}`
+	repo := suite.storage.(*mocks.Repo)
+	repo.On("CreateTask", mock.Anything, req).Return(task, nil).Once()
+	repo.On("GetProject", mock.Anything, "test").Return(tasktodo.Project{ID: "test"}, nil).Once()
+	repo.On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny, ProjectID: "test"}).Return([]tasktodo.Task{task}, nil).Once()
+	repo.On("ListTasks", mock.Anything, tasktodo.ListParams{Limit: 10, TagMode: tasktodo.TagModeAny, ProjectID: tasktodo.NoProject}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+	repo.On("GetProject", mock.Anything, "missing").Return(tasktodo.Project{}, errors.New(pgrepo.InvalidProjectIdErr)).Once()
+	suite.service = httpchi.NewService(suite.storage, anyDueDate)
+
+	testCases := []struct {
+		handler      http.HandlerFunc
+		pid          string
+		target       string
+		reqBody      string
+		expectedCode int
+		expectedResp string
+	}{
+		{handler: suite.service.CreateProjectTask, pid: "test", target: "/projects/test/tasks", expectedCode: http.StatusCreated, expectedResp: taskResp,
+			reqBody: `{"title":"test","description":"test","due_date":"2024-10-26","status":false,"project_id":"other"}`},
+		{handler: suite.service.ListProjectTasks, pid: "test", target: "/projects/test/tasks?project=none", expectedCode: http.StatusOK,
+			expectedResp: `[` + taskResp + `]`},
+		{handler: suite.service.ListTasks, target: "/tasks?project=none", expectedCode: http.StatusOK,
+			expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}]`},
+		{handler: suite.service.ListProjectTasks, pid: "missing", target: "/projects/missing/tasks", expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"invalid project id"}`},
+	}
+	for _, tc := range testCases {
+		ctx := chi.NewRouteContext()
+		if tc.pid != "" {
+			ctx.URLParams.Add("pid", tc.pid)
+		}
+		r := newRequest("POST", tc.target, strings.NewReader(tc.reqBody))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(w, r)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
@@ -0,0 +1,101 @@
+package httpchi
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultOccurrences = 5
+	maxOccurrences     = 100
+)
+
+// ListOccurrences previews upcoming occurrences of a recurring task.
+//
+//	@Summary		Previews upcoming occurrences of a recurring task
+//	@Description	Lists due dates of the occurrences following the task according to the series recurrence rule
+//	@Tags			Recurrence
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string					true	"Task ID"
+//	@Param			count	query		int						false	"Number of occurrences (1-100)"	default(5)
+//	@Success		200		{object}	tasktodo.Occurrences	"Upcoming occurrences"
+//	@Failure		400		{object}	ErrResp					"Invalid count"
+//	@Failure		401		{object}	ErrResp					"Missing credentials"
+//	@Failure		403		{object}	ErrResp					"Scope tasks:read not granted"
+//	@Failure		404		{object}	MsgResp					"Task not found"
+//	@Failure		422		{object}	ErrResp					"Task is not recurring"
+//	@Router			/task/{id}/occurrences [get]
+func (s Service) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	count := defaultOccurrences
+	if param := r.URL.Query().Get("count"); param != "" {
+		temp, err := strconv.Atoi(param)
+		if err != nil || temp < 1 || temp > maxOccurrences {
+			log.Error().Err(err).Str("count", param).Send()
+			NewErr("count", param, "bad count").Send(w, r, http.StatusBadRequest)
+			return
+		}
+		count = temp
+	}
+	logID := log.With().Str("id", taskID).Logger()
+	task, err := s.DB.GetTask(r.Context(), taskID)
+	if err == nil && task.SeriesID == "" {
+		err = errors.New(tasktodo.NotRecurringErr)
+	}
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	series, err := s.DB.GetSeries(r.Context(), task.SeriesID)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	dates, err := series.NextDates(task.DueDate, time.Now(), count)
+	if err != nil {
+		errorHandler(w, r, logID, task.DueDate, taskID, err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tasktodo.Occurrences{
+		SeriesID:    series.ID,
+		Recurrence:  series.Recurrence,
+		Occurrences: dates,
+	})
+}
+
+func (s Service) patchSeries(w http.ResponseWriter, r *http.Request, log zerolog.Logger, taskID string, patch tasktodo.Patch) {
+	if field := patch.SeriesField(); field != "" {
+		log.Warn().Str("field", field).Msg("field is not shared by the series")
+		NewErr(field, "", "field not shared by the series").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	version, err := s.writeVersion(r, taskID)
+	if err != nil {
+		errorHandler(w, r, log, "", taskID, err)
+		return
+	}
+	task, err := s.DB.PatchSeries(r.Context(), patch, taskID, version)
+	if err != nil {
+		errorHandler(w, r, log, "", taskID, err)
+		return
+	}
+	log.Info().Str("series", task.SeriesID).Msg("series patched successfully")
+	w.Header().Set("ETag", etag(task.Version))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, task)
+}
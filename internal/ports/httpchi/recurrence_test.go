@@ -0,0 +1,157 @@
+package httpchi_test
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestRecurrence() {
+	type recurrenceTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	rule := "FREQ=DAILY;INTERVAL=7;COUNT=3"
+	due := time.Now().AddDate(0, 0, 1)
+	recurring := suite.testTask
+	recurring.SeriesID = "test"
+	recurring.Recurrence = &rule
+	recurring.DueDate = due.Format("2006-01-02")
+	renamed := recurring
+	renamed.Title = "renamed"
+	renamed.Version = 2
+	title := "renamed"
+	testCases := []recurrenceTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListOccurrences },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(recurring, nil).Once()
+					suite.storage.(*mocks.Repo).On("GetSeries", mock.Anything, "test").
+						Return(tasktodo.Series{ID: "test", Recurrence: rule, Generated: 1}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"series_id":"test","recurrence":"FREQ=DAILY;INTERVAL=7;COUNT=3","occurrences":["` +
+					due.AddDate(0, 0, 7).Format("2006-01-02") + `","` + due.AddDate(0, 0, 14).Format("2006-01-02") + `"]}`,
+				reqMethod: "GET",
+				reqTarget: "/task/test/occurrences?count=10",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListOccurrences },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"id","value":"test","error":"task is not recurring"}`,
+				reqMethod:    "GET",
+				reqTarget:    "/task/test/occurrences",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListOccurrences },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"count","value":"0","error":"bad count"}`,
+				reqMethod:     "GET",
+				reqTarget:     "/task/test/occurrences?count=0",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PatchSeries", mock.Anything, tasktodo.Patch{Title: &title}, "test", int64(0)).Return(renamed, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","series_id":"test","title":"renamed","description":"test","due_date":"` + recurring.DueDate +
+					`","status":false,"state":"todo","tags":[],"recurrence":"FREQ=DAILY;INTERVAL=7;COUNT=3","version":2}`,
+				reqBody:   `{"title":"renamed"}`,
+				reqMethod: "PATCH",
+				reqTarget: "/task/test?scope=series",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"due_date","error":"field not shared by the series"}`,
+				reqBody:       `{"due_date":"2024-10-26"}`,
+				reqMethod:     "PATCH",
+				reqTarget:     "/task/test?scope=series",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"due_time","error":"field not shared by the series"}`,
+				reqBody:       `{"due_time":"09:00"}`,
+				reqMethod:     "PATCH",
+				reqTarget:     "/task/test?scope=series",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"priority","error":"field not shared by the series"}`,
+				reqBody:       `{"priority":"P0"}`,
+				reqMethod:     "PATCH",
+				reqTarget:     "/task/test?scope=series",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"scope","value":"all","error":"bad scope"}`,
+				reqBody:       `{"title":"renamed"}`,
+				reqMethod:     "PATCH",
+				reqTarget:     "/task/test?scope=all",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateTask },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"recurrence","value":"FREQ=MINUTELY","error":"bad recurrence rule"}`,
+				reqBody:       `{"title":"test","description":"test","due_date":"2024-10-26","status":false,"recurrence":"FREQ=MINUTELY"}`,
+				reqMethod:     "POST",
+				reqTarget:     "/task",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest(tc.reqMethod, tc.reqTarget, strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
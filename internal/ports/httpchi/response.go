@@ -2,6 +2,7 @@ package httpchi
 
 import (
 	"github.com/go-chi/render"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
 	"net/http"
 )
 
@@ -15,6 +16,42 @@ type MsgResp struct {
 	Msg string `json:"message"`
 }
 
+// TaskPage is a page of tasks listed by cursor, NextCursor is left out on the last page.
+type TaskPage struct {
+	Tasks      []tasktodo.Task `json:"tasks"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// CommentPage is a page of comments of a task, NextCursor is left out on the last page.
+type CommentPage struct {
+	Comments   []tasktodo.Comment `json:"comments"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// ChecklistResp is the checklist of a task after an item is toggled, Task is only set when the task got completed.
+type ChecklistResp struct {
+	tasktodo.Checklist
+	Task *tasktodo.Task `json:"task,omitempty"`
+}
+
+// BulkResult is the result of an operation of a bulk request. Task is left out for deleted tasks
+// and failed operations, Error is set for failed operations only.
+type BulkResult struct {
+	Index  int                 `json:"index"`
+	Action tasktodo.BulkAction `json:"action"`
+	ID     string              `json:"id,omitempty"`
+	Status int                 `json:"status"`
+	Task   *tasktodo.Task      `json:"task,omitempty"`
+	Error  *ErrResp            `json:"error,omitempty"`
+}
+
+// BulkResp lists the results of the operations of a bulk request in their order.
+type BulkResp struct {
+	Applied int          `json:"applied"`
+	Failed  int          `json:"failed"`
+	Results []BulkResult `json:"results"`
+}
+
 func NewErr(param, val, err string) ErrResp {
 	return ErrResp{
 		Param: param,
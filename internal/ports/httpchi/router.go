@@ -27,16 +27,87 @@ func NewRouter(service Service, logger zerolog.Logger) http.Handler {
 	return r
 }
 
+// RegisterRoutes serves the API under /api and its second version under /api/v2,
+// which shares the routes and differs in how lists are returned.
 func RegisterRoutes(r *chi.Mux, service Service) {
+	api := newAPI(service, 1)
+	api.Get("/swagger/*", httpSwagger.WrapHandler)
+
+	r.Mount("/api/v2", newAPI(service, 2))
+	r.Mount("/api", api)
+}
+
+func newAPI(service Service, version int) *chi.Mux {
 	api := chi.NewRouter()
+	api.Use(APIVersion(version))
 
-	api.Post("/task", service.CreateTask)
-	api.Get("/tasks", service.ListTasks)
-	api.Get("/task/{id}", service.GetSingleTask)
-	api.Put("/task/{id}", service.UpdateTask)
-	api.Delete("/task/{id}", service.DeleteTask)
+	api.Post("/auth/register", service.Register)
+	api.Post("/auth/login", service.Login)
+	api.Post("/auth/refresh", service.Refresh)
 
-	api.Get("/swagger/*", httpSwagger.WrapHandler)
+	api.Group(func(r chi.Router) {
+		r.Use(service.Authenticate)
 
-	r.Mount("/api", api)
+		r.Post("/task", service.CreateTask)
+		r.Get("/tasks", service.ListTasks)
+		r.Get("/tasks/search", service.SearchTasks)
+		r.Get("/tasks/matrix", service.GetMatrix)
+		r.Post("/tasks/bulk", service.BulkTasks)
+		r.Get("/task/{id}", service.GetSingleTask)
+		r.Put("/task/{id}", service.UpdateTask)
+		r.Patch("/task/{id}", service.PatchTask)
+		r.Delete("/task/{id}", service.DeleteTask)
+		r.Post("/task/{id}/transition", service.TransitionTask)
+		r.Get("/task/{id}/children", service.ListChildren)
+		r.Get("/task/{id}/subtree", service.ListSubtree)
+		r.Get("/task/{id}/dependencies", service.ListBlockers)
+		r.Post("/task/{id}/dependencies", service.AddDependency)
+		r.Delete("/task/{id}/dependencies/{blocker}", service.RemoveDependency)
+		r.Get("/task/{id}/occurrences", service.ListOccurrences)
+		r.Post("/task/{id}/restore", service.RestoreTask)
+		r.Get("/trash", service.ListTrash)
+		r.Delete("/trash/{id}", service.PurgeTask)
+		r.Get("/task/{id}/history", service.ListHistory)
+		r.Post("/task/{id}/revert", service.RevertTask)
+		r.Get("/task/{id}/comments", service.ListComments)
+		r.Post("/task/{id}/comments", service.CreateComment)
+		r.Put("/task/{id}/comments/{cid}", service.UpdateComment)
+		r.Delete("/task/{id}/comments/{cid}", service.DeleteComment)
+		r.Get("/task/{id}/attachments", service.ListAttachments)
+		r.Post("/task/{id}/attachments", service.UploadAttachment)
+		r.Get("/task/{id}/attachments/{aid}", service.DownloadAttachment)
+		r.Delete("/task/{id}/attachments/{aid}", service.DeleteAttachment)
+		r.Get("/task/{id}/checklist", service.GetChecklist)
+		r.Post("/task/{id}/checklist", service.AddChecklistItem)
+		r.Put("/task/{id}/checklist/order", service.ReorderChecklist)
+		r.Patch("/task/{id}/checklist/{iid}", service.ToggleChecklistItem)
+		r.Delete("/task/{id}/checklist/{iid}", service.RemoveChecklistItem)
+		r.Get("/workflow", service.GetWorkflow)
+		r.Post("/tags", service.CreateTag)
+		r.Get("/tags", service.ListTags)
+		r.Get("/tags/{id}", service.GetTag)
+		r.Put("/tags/{id}", service.UpdateTag)
+		r.Delete("/tags/{id}", service.DeleteTag)
+		r.Post("/projects", service.CreateProject)
+		r.Get("/projects", service.ListProjects)
+		r.Get("/projects/{pid}", service.GetProject)
+		r.Put("/projects/{pid}", service.UpdateProject)
+		r.Delete("/projects/{pid}", service.DeleteProject)
+		r.Post("/projects/{pid}/archive", service.ArchiveProject)
+		r.Post("/projects/{pid}/unarchive", service.UnarchiveProject)
+		r.Get("/projects/{pid}/tasks", service.ListProjectTasks)
+		r.Post("/projects/{pid}/tasks", service.CreateProjectTask)
+		r.Post("/projects/{pid}/tasks/move", service.MoveTasks)
+		r.Post("/views", service.CreateView)
+		r.Get("/views", service.ListViews)
+		r.Get("/views/{id}", service.GetView)
+		r.Put("/views/{id}", service.UpdateView)
+		r.Delete("/views/{id}", service.DeleteView)
+		r.Get("/views/{id}/tasks", service.ListViewTasks)
+		r.Post("/keys", service.CreateAPIKey)
+		r.Get("/keys", service.ListAPIKeys)
+		r.Delete("/keys/{id}", service.RevokeAPIKey)
+	})
+
+	return api
 }
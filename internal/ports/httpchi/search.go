@@ -0,0 +1,75 @@
+package httpchi
+
+import (
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// SearchTasks returns tasks matching a full-text query.
+//
+//	@Summary		Searches tasks by title and description
+//	@Description	Full-text search in the web search syntax: quoted phrases, "or" and "-" for exclusion are supported.
+//	@Description	Results are ranked, matches in the title weigh more than in the description, matched words are highlighted with <mark> tags and the rest of the fragments is HTML-escaped.
+//	@Description	The same filters and pagination as for the list of tasks apply
+//	@Tags			Tasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			q			query		string					true	"Search query"
+//	@Param			status		query		string					false	"Task completion status (true/false)"
+//	@Param			date		query		string					false	"Task date (format: YYYY-MM-DD)"
+//	@Param			page		query		string					false	"Page number for pagination"
+//	@Param			tag			query		[]string				false	"Tag names, repeated or comma separated"		collectionFormat(multi)
+//	@Param			tag_mode	query		string					false	"Whether a task needs any or all of the tags"	Enums(any, all)	default(any)
+//	@Param			blocked		query		string					false	"Whether a task has open blockers (true/false)"
+//	@Param			due_from	query		string					false	"Earliest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			due_to		query		string					false	"Latest due date, inclusive (format: YYYY-MM-DD)"
+//	@Param			overdue		query		string					false	"Whether a task is past its due time and still open (true/false)"
+//	@Param			created_after	query		string					false	"Creation time lower bound, RFC 3339 or YYYY-MM-DD"
+//	@Param			title_prefix	query		string					false	"Case-insensitive title prefix"
+//	@Param			sort		query		[]string					false	"Sort fields, repeated or comma separated, "-" prefix for descending order; can not be combined with cursor"	collectionFormat(multi)
+//	@Param			project		query		string					false	"Project ID, or none for tasks outside any project"
+//	@Success		200			{object}	[]tasktodo.SearchResult	"Matching tasks, best first"
+//	@Failure		400			{object}	ErrResp					"Missing query or invalid request parameters"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Scope tasks:read not granted"
+//	@Failure		404			{object}	MsgResp					"Tasks not found"
+//	@Router			/tasks/search [get]
+func (s Service) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	query := r.URL.Query()
+	search := strings.TrimSpace(query.Get("q"))
+	if search == "" || utf8.RuneCountInString(search) > tasktodo.SearchQueryLimit {
+		log.Error().Str("q", search).Msg("bad search query")
+		NewErr("q", search, "bad query").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	params, errResp, err := validateParams(query)
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	log.Info().Str("q", search).Str("status", params.Status).Str("date", params.Date).Uint("page", params.Page).Msg("params received")
+	results, err := s.DB.SearchTasks(r.Context(), search, params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	if len(results) == 0 {
+		log.Warn().Str("query", query.Encode()).Msg("nothing found")
+		NewMsg("nothing found").Send(w, r, http.StatusNotFound)
+		return
+	}
+	log.Info().Int("amount", len(results)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, results)
+}
@@ -0,0 +1,76 @@
+package httpchi_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestSearchTasks() {
+	result := tasktodo.SearchResult{Task: suite.testTask, Rank: 0.5,
+		Highlights: tasktodo.Highlights{Title: "<mark>test</mark>", Description: "<mark>test</mark>"}}
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("SearchTasks", mock.Anything, "test -draft",
+					tasktodo.ListParams{Status: "false", Date: "2024-10-26", TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.SearchResult{result}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],` +
+				`"rank":0.5,"highlights":{"title":"\u003cmark\u003etest\u003c/mark\u003e","description":"\u003cmark\u003etest\u003c/mark\u003e"}}]`,
+			reqTarget: "/tasks/search?q=+test+-draft&status=false&date=2024-10-26",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("SearchTasks", mock.Anything, "nothing", tasktodo.ListParams{TagMode: tasktodo.TagModeAny}).
+					Return([]tasktodo.SearchResult{}, nil).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedResp: `{"message":"nothing found"}`,
+			reqTarget:    "/tasks/search?q=nothing",
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("SearchTasks", mock.Anything, "fail", tasktodo.ListParams{TagMode: tasktodo.TagModeAny}).
+					Return(nil, errors.New("any error")).Once()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedResp: `{"param":"id","error":"action fail"}`,
+			reqTarget:    "/tasks/search?q=fail",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"q","error":"bad query"}`,
+			reqTarget:     "/tasks/search?q=++",
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"q","value":"` + strings.Repeat("a", 257) + `","error":"bad query"}`,
+			reqTarget:     "/tasks/search?q=" + strings.Repeat("a", 257),
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusBadRequest,
+			expectedResp:  `{"param":"date","value":"12345","error":"bad date format"}`,
+			reqTarget:     "/tasks/search?q=test&date=12345",
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		w := httptest.NewRecorder()
+
+		suite.service.SearchTasks(w, newRequest("GET", tc.reqTarget, nil))
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
@@ -3,18 +3,83 @@ package httpchi
 import (
 	"github.com/rs/zerolog"
 	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/models/account"
 	"github.com/vlasashk/task-manager/internal/models/tasktodo"
 	"net/http"
 )
 
 type Service struct {
-	DB tasktodo.Repo
+	DB          tasktodo.Repo
+	Workflow    tasktodo.Workflow
+	Users       account.Repo
+	Tokens      account.Issuer
+	Pages       tasktodo.Paginator
+	Blobs       tasktodo.BlobStore
+	Attachments tasktodo.AttachmentPolicy
+	DueDates    tasktodo.DueDatePolicy
+	BulkLimit   int
 }
 
-func NewService(db tasktodo.Repo) Service {
-	return Service{
-		DB: db,
+// Option configures optional dependencies of the Service.
+type Option func(*Service)
+
+// WithWorkflow replaces the default task workflow.
+func WithWorkflow(workflow tasktodo.Workflow) Option {
+	return func(s *Service) {
+		s.Workflow = workflow
+	}
+}
+
+// WithAuth enables registration and login of users, tokens are issued and verified by the issuer.
+func WithAuth(users account.Repo, tokens account.Issuer) Option {
+	return func(s *Service) {
+		s.Users = users
+		s.Tokens = tokens
+	}
+}
+
+// WithPagination replaces the default page limits and cursor signing key.
+func WithPagination(pages tasktodo.Paginator) Option {
+	return func(s *Service) {
+		s.Pages = pages
+	}
+}
+
+// WithAttachments keeps contents of attachments in the blob store and replaces the default upload limits.
+func WithAttachments(blobs tasktodo.BlobStore, policy tasktodo.AttachmentPolicy) Option {
+	return func(s *Service) {
+		s.Blobs = blobs
+		s.Attachments = policy
+	}
+}
+
+// WithDueDatePolicy replaces the default limit on due dates in the past.
+func WithDueDatePolicy(policy tasktodo.DueDatePolicy) Option {
+	return func(s *Service) {
+		s.DueDates = policy
+	}
+}
+
+// WithBulkLimit replaces the default number of operations a bulk request may hold.
+func WithBulkLimit(limit int) Option {
+	return func(s *Service) {
+		s.BulkLimit = limit
+	}
+}
+
+func NewService(db tasktodo.Repo, opts ...Option) Service {
+	service := Service{
+		DB:          db,
+		Workflow:    tasktodo.DefaultWorkflow(),
+		Pages:       tasktodo.DefaultPaginator(),
+		Attachments: tasktodo.DefaultAttachmentPolicy(),
+		DueDates:    tasktodo.DefaultDueDatePolicy(),
+		BulkLimit:   tasktodo.DefaultBulkLimit,
+	}
+	for _, opt := range opts {
+		opt(&service)
 	}
+	return service
 }
 
 func Run(service Service, logger zerolog.Logger, cfg config.AppCfg) {
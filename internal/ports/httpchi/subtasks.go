@@ -0,0 +1,68 @@
+package httpchi
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+)
+
+// ListChildren returns direct subtasks of a task.
+//
+//	@Summary		Returns direct subtasks of a task
+//	@Description	Retrieves tasks whose parent is the specified task
+//	@Tags			Subtasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"Task ID"
+//	@Success		200	{object}	[]tasktodo.Task	"List of subtasks"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp			"Task not found"
+//	@Router			/task/{id}/children [get]
+func (s Service) ListChildren(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	s.listRelated(w, r, s.DB.ListChildren)
+}
+
+// ListSubtree returns all descendants of a task.
+//
+//	@Summary		Returns the whole subtree of a task
+//	@Description	Retrieves all descendants of the specified task ordered by depth, the tree can be rebuilt with parent_id
+//	@Tags			Subtasks
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"Task ID"
+//	@Success		200	{object}	[]tasktodo.Task	"List of descendants"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp			"Task not found"
+//	@Router			/task/{id}/subtree [get]
+func (s Service) ListSubtree(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	s.listRelated(w, r, s.DB.ListSubtree)
+}
+
+func (s Service) listRelated(w http.ResponseWriter, r *http.Request, list func(ctx context.Context, taskID string) ([]tasktodo.Task, error)) {
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	tasks, err := list(r.Context(), taskID)
+	if err != nil {
+		logID := log.With().Str("id", taskID).Logger()
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	log.Info().Int("amount", len(tasks)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tasks)
+}
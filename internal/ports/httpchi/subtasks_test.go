@@ -0,0 +1,116 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestSubtasks() {
+	type subtaskTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	parent := "test"
+	child := suite.testTask
+	child.ID = "child"
+	child.ParentID = &parent
+	top := ""
+	testCases := []subtaskTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListChildren },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListChildren", mock.Anything, "test").Return([]tasktodo.Task{child}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"child","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"parent_id":"test"}]`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListSubtree },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListSubtree", mock.Anything, "test").Return(nil, errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid task id"}`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListSubtree },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListSubtree", mock.Anything, "test").Return([]tasktodo.Task{}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[]`,
+				reqMethod:    "GET",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{ParentID: &top}, "test", int64(0)).Return(suite.testTask, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
+				reqBody:      `{"parent_id":null}`,
+				reqMethod:    "PATCH",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{ParentID: &child.ID}, "test", int64(0)).Return(tasktodo.Task{}, errors.New(pgrepo.CycleErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"parent_id","error":"task hierarchy cycle"}`,
+				reqBody:      `{"parent_id":"child"}`,
+				reqMethod:    "PATCH",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PatchTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{ParentID: &child.ID}, "test", int64(0)).Return(tasktodo.Task{}, errors.New(pgrepo.ParentErr)).Once()
+				},
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedResp: `{"param":"parent_id","error":"invalid parent id"}`,
+				reqBody:      `{"parent_id":"child"}`,
+				reqMethod:    "PATCH",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest(tc.reqMethod, "/task", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
@@ -0,0 +1,205 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"strings"
+)
+
+// CreateTag creates a new tag.
+//
+//	@Summary		Creates a new tag
+//	@Description	Creates a tag with a unique name, tags are also created on the fly when assigned to a task
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			tagRequest	body		tasktodo.TagRequest	true	"Data of the new tag"
+//	@Success		201			{object}	tasktodo.Tag		"Tag successfully created"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		409			{object}	ErrResp				"Tag already exists"
+//	@Failure		422			{object}	ErrResp				"Invalid JSON"
+//	@Router			/tags [post]
+func (s Service) CreateTag(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	tagRequest := tasktodo.TagRequest{}
+	log := *zerolog.Ctx(r.Context())
+	if err := render.DecodeJSON(r.Body, &tagRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	tagRequest.Name = strings.TrimSpace(tagRequest.Name)
+	if err := validator.New().Struct(tagRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	tag, err := s.DB.CreateTag(r.Context(), tagRequest)
+	if err != nil {
+		tagErrorHandler(w, r, log, tagRequest.Name, "", err)
+		return
+	}
+	log.Info().Str("id", tag.ID).Msg("tag created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, tag)
+}
+
+// ListTags returns all tags.
+//
+//	@Summary		Returns all tags
+//	@Description	Retrieves all tags ordered by name
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Success		200	{object}	[]tasktodo.Tag	"List of tags"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Router			/tags [get]
+func (s Service) ListTags(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	tags, err := s.DB.ListTags(r.Context())
+	if err != nil {
+		tagErrorHandler(w, r, log, "", "", err)
+		return
+	}
+	log.Info().Int("amount", len(tags)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tags)
+}
+
+// GetTag returns a tag based on the specified ID.
+//
+//	@Summary		Gets a tag by ID
+//	@Description	Retrieves a tag based on the provided identifier
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"Tag ID"
+//	@Success		200	{object}	tasktodo.Tag	"Tag successfully retrieved"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp			"Tag not found"
+//	@Router			/tags/{id} [get]
+func (s Service) GetTag(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	tagID := chi.URLParam(r, "id")
+	log.Info().Str("id", tagID).Msg("tag id received")
+	tag, err := s.DB.GetTag(r.Context(), tagID)
+	if err != nil {
+		tagErrorHandler(w, r, log.With().Str("id", tagID).Logger(), "", tagID, err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tag)
+}
+
+// UpdateTag renames a tag by the specified ID.
+//
+//	@Summary		Renames a tag by ID
+//	@Description	Renames a tag, tasks having the tag get the new name
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Tag ID"
+//	@Param			tagRequest	body		tasktodo.TagRequest	true	"New tag data"
+//	@Success		200			{object}	tasktodo.Tag		"Tag successfully updated"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp				"Tag not found"
+//	@Failure		409			{object}	ErrResp				"Tag already exists"
+//	@Failure		422			{object}	ErrResp				"Invalid JSON"
+//	@Router			/tags/{id} [put]
+func (s Service) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	tagRequest := tasktodo.TagRequest{}
+	log := *zerolog.Ctx(r.Context())
+	tagID := chi.URLParam(r, "id")
+	log.Info().Str("id", tagID).Msg("tag id received")
+	if err := render.DecodeJSON(r.Body, &tagRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	tagRequest.Name = strings.TrimSpace(tagRequest.Name)
+	if err := validator.New().Struct(tagRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	tag, err := s.DB.UpdateTag(r.Context(), tagRequest, tagID)
+	if err != nil {
+		tagErrorHandler(w, r, log.With().Str("id", tagID).Logger(), tagRequest.Name, tagID, err)
+		return
+	}
+	log.Info().Str("id", tagID).Msg("tag updated successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tag)
+}
+
+// DeleteTag deletes a tag by the specified ID.
+//
+//	@Summary		Deletes a tag by ID
+//	@Description	Deletes a tag and removes it from all tasks
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Tag ID"
+//	@Success		200	{object}	MsgResp	"Tag successfully deleted"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"Tag not found"
+//	@Router			/tags/{id} [delete]
+func (s Service) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	tagID := chi.URLParam(r, "id")
+	log.Info().Str("id", tagID).Msg("tag id received")
+	if err := s.DB.DeleteTag(r.Context(), tagID); err != nil {
+		tagErrorHandler(w, r, log.With().Str("id", tagID).Logger(), "", tagID, err)
+		return
+	}
+	log.Info().Str("id", tagID).Msg("deleted successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
+
+func tagErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, name, tagID string, err error) {
+	switch err.Error() {
+	case pgrepo.InvalidTagIdErr:
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidTagIdErr).Send(w, r, http.StatusNotFound)
+	case pgrepo.TagExistsErr:
+		log.Warn().Err(err).Send()
+		NewErr("name", name, pgrepo.TagExistsErr).Send(w, r, http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		NewErr("id", tagID, "action fail").Send(w, r, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,146 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestTagHandlers() {
+	type tagTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	tag := tasktodo.Tag{ID: "tag", TagRequest: tasktodo.TagRequest{Name: "work"}}
+	testCases := []tagTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateTag },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateTag", mock.Anything, tag.TagRequest).Return(tag, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: `{"id":"tag","name":"work"}`,
+				reqBody:      `{"name":" work "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateTag },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateTag", mock.Anything, tag.TagRequest).Return(tasktodo.Tag{}, errors.New(pgrepo.TagExistsErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"name","value":"work","error":"tag already exists"}`,
+				reqBody:      `{"name":"work"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateTag },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"name":"work,home"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListTags },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListTags", mock.Anything).Return([]tasktodo.Tag{tag}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"tag","name":"work"}]`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.GetTag },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetTag", mock.Anything, "test").Return(tasktodo.Tag{}, errors.New(pgrepo.InvalidTagIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid tag id"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateTag },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("UpdateTag", mock.Anything, tasktodo.TagRequest{Name: "home"}, "test").
+						Return(tasktodo.Tag{ID: "test", TagRequest: tasktodo.TagRequest{Name: "home"}}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","name":"home"}`,
+				reqBody:      `{"name":"home"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteTag },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("DeleteTag", mock.Anything, "test").Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest("POST", "/tags", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
+
+func (suite *UnitTestSuite) TestTaskTags() {
+	tagged := suite.testTask
+	tagged.Tags = []string{"home", "work"}
+	req := suite.taskReq
+	req.Tags = []string{"home", "work"}
+	suite.storage.(*mocks.Repo).On("CreateTask", mock.Anything, req).Return(tagged, nil).Once()
+	suite.service = httpchi.NewService(suite.storage, anyDueDate)
+	w := httptest.NewRecorder()
+
+	suite.service.CreateTask(w, newRequest("POST", "/task",
+		strings.NewReader(`{"title":"test","description":"test","due_date":"2024-10-26","status":false,"tags":["work"," home","work"]}`)))
+
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Equal(`{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":["home","work"]}`,
+		strings.TrimSpace(w.Body.String()))
+
+	suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{Tags: &[]string{}}, "test", int64(0)).Return(suite.testTask, nil).Once()
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("id", "test")
+	patchReq := newRequest("PATCH", "/task", strings.NewReader(`{"tags":null}`))
+	patchReq = patchReq.WithContext(context.WithValue(patchReq.Context(), chi.RouteCtxKey, ctx))
+	w = httptest.NewRecorder()
+
+	suite.service.PatchTask(w, patchReq)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(`{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`,
+		strings.TrimSpace(w.Body.String()))
+}
@@ -0,0 +1,109 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"net/http"
+)
+
+// ListTrash returns deleted tasks.
+//
+//	@Summary		Returns deleted tasks
+//	@Description	Retrieves tasks in the trash, the latest deleted first. Tasks are purged for good once the retention period has passed
+//	@Tags			Trash
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			page	query		string					false	"Page number for pagination"
+//	@Param			limit	query		int						false	"Page size, limited by the server"
+//	@Success		200		{object}	[]tasktodo.DeletedTask	"List of deleted tasks"
+//	@Failure		400		{object}	ErrResp					"Invalid request parameters"
+//	@Failure		401		{object}	ErrResp					"Missing credentials"
+//	@Failure		403		{object}	ErrResp					"Scope tasks:read not granted"
+//	@Router			/trash [get]
+func (s Service) ListTrash(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	params, errResp, err := s.pageParams(r.URL.Query())
+	if err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusBadRequest)
+		return
+	}
+	tasks, err := s.DB.ListTrash(r.Context(), params)
+	if err != nil {
+		errorHandler(w, r, log, "", "", err)
+		return
+	}
+	log.Info().Int("amount", len(tasks)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, tasks)
+}
+
+// RestoreTask takes a task out of the trash.
+//
+//	@Summary		Restores a deleted task
+//	@Description	Restores a task from the trash together with the subtasks deleted with it.
+//	@Description	A subtask can not be restored while its parent is deleted, nor a task whose due date has passed
+//	@Tags			Trash
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"Task ID"
+//	@Success		200	{object}	tasktodo.Task	"Task successfully restored"
+//	@Header			200	{string}	ETag			"Task version"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:write not granted"
+//	@Failure		404	{object}	MsgResp			"Task not found in the trash"
+//	@Failure		409	{object}	ErrResp			"Parent task is deleted or the due date has passed"
+//	@Router			/task/{id}/restore [post]
+func (s Service) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	task, err := s.DB.RestoreTask(r.Context(), taskID)
+	if err != nil {
+		errorHandler(w, r, log.With().Str("id", taskID).Logger(), "", taskID, err)
+		return
+	}
+	log.Info().Str("id", taskID).Msg("restored successfully")
+	w.Header().Set("ETag", etag(task.Version))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, task)
+}
+
+// PurgeTask deletes a task from the trash for good.
+//
+//	@Summary		Purges a deleted task
+//	@Description	Deletes a task in the trash permanently together with its subtasks, it can not be restored afterwards
+//	@Tags			Trash
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Task ID"
+//	@Success		200	{object}	MsgResp	"Task successfully purged"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"Task not found in the trash"
+//	@Router			/trash/{id} [delete]
+func (s Service) PurgeTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if err := s.DB.PurgeTask(r.Context(), taskID); err != nil {
+		errorHandler(w, r, log.With().Str("id", taskID).Logger(), "", taskID, err)
+		return
+	}
+	log.Info().Str("id", taskID).Msg("purged successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
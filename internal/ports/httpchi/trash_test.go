@@ -0,0 +1,120 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestTrashHandlers() {
+	type trashTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	deleted := tasktodo.DeletedTask{Task: suite.testTask, DeletedAt: time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)}
+	restored := suite.testTask
+	restored.Version = 2
+	testCases := []trashTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListTrash },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListTrash", mock.Anything, tasktodo.ListParams{Page: 2, Limit: 5}).
+						Return([]tasktodo.DeletedTask{deleted}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],` +
+					`"deleted_at":"2024-10-20T12:00:00Z"}]`,
+				reqTarget: "/trash?page=2&limit=5",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListTrash },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListTrash", mock.Anything, tasktodo.ListParams{Limit: 10}).
+						Return([]tasktodo.DeletedTask{}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[]`,
+				reqTarget:    "/trash",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListTrash },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusBadRequest,
+				expectedResp:  `{"param":"page","value":"last","error":"bad page"}`,
+				reqTarget:     "/trash?page=last",
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RestoreTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("RestoreTask", mock.Anything, "test").Return(restored, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[],"version":2}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.RestoreTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("RestoreTask", mock.Anything, "test").Return(tasktodo.Task{}, errors.New(pgrepo.ParentDeletedErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"parent_id","error":"parent task is deleted"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PurgeTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PurgeTask", mock.Anything, "test").Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.PurgeTask },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("PurgeTask", mock.Anything, "test").Return(errors.New(pgrepo.InvalidIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid task id"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		target := "/trash/test"
+		if tc.reqTarget != "" {
+			target = tc.reqTarget
+		}
+		req := newRequest("POST", target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
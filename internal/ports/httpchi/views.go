@@ -0,0 +1,302 @@
+package httpchi
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CreateView saves a new view.
+//
+//	@Summary		Saves a new view
+//	@Description	Saves a named task listing: filters take the names and values of the GET /tasks parameters,
+//	@Description	sort and page_size are its sort and limit. The view is checked the way a listing is, broken views are not saved
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			viewRequest	body		tasktodo.ViewRequest	true	"Data of the new view"
+//	@Success		201			{object}	tasktodo.View			"View successfully saved"
+//	@Failure		400			{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		409			{object}	ErrResp					"View already exists"
+//	@Failure		422			{object}	ErrResp					"Invalid JSON, unknown filter or invalid filter value"
+//	@Router			/views [post]
+func (s Service) CreateView(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	viewRequest, ok := s.decodeView(w, r, log)
+	if !ok {
+		return
+	}
+	view, err := s.DB.CreateView(r.Context(), viewRequest)
+	if err != nil {
+		viewErrorHandler(w, r, log, viewRequest.Name, "", err)
+		return
+	}
+	log.Info().Str("id", view.ID).Msg("view created successfully")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, view)
+}
+
+// ListViews returns views of the user.
+//
+//	@Summary		Returns views
+//	@Description	Retrieves saved views ordered by name
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Success		200	{object}	[]tasktodo.View	"List of views"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Router			/views [get]
+func (s Service) ListViews(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	views, err := s.DB.ListViews(r.Context())
+	if err != nil {
+		viewErrorHandler(w, r, log, "", "", err)
+		return
+	}
+	log.Info().Int("amount", len(views)).Msg("found successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, views)
+}
+
+// GetView returns a view based on the specified ID.
+//
+//	@Summary		Gets a view by ID
+//	@Description	Retrieves a saved view based on the provided identifier
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string			true	"View ID"
+//	@Success		200	{object}	tasktodo.View	"View successfully retrieved"
+//	@Failure		401	{object}	ErrResp			"Missing credentials"
+//	@Failure		403	{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404	{object}	MsgResp			"View not found"
+//	@Router			/views/{id} [get]
+func (s Service) GetView(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	viewID := chi.URLParam(r, "id")
+	log.Info().Str("id", viewID).Msg("view id received")
+	view, err := s.DB.GetView(r.Context(), viewID)
+	if err != nil {
+		viewErrorHandler(w, r, log.With().Str("id", viewID).Logger(), "", viewID, err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, view)
+}
+
+// UpdateView replaces a view by the specified ID.
+//
+//	@Summary		Replaces a view by ID
+//	@Description	Replaces the name, filters, sort and page size of a view, the view is checked as on creation
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string					true	"View ID"
+//	@Param			viewRequest	body		tasktodo.ViewRequest	true	"New data of the view"
+//	@Success		200			{object}	tasktodo.View			"View successfully updated"
+//	@Failure		400			{object}	ErrResp					"Incorrect JSON"
+//	@Failure		401			{object}	ErrResp					"Missing credentials"
+//	@Failure		403			{object}	ErrResp					"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp					"View not found"
+//	@Failure		409			{object}	ErrResp					"View already exists"
+//	@Failure		422			{object}	ErrResp					"Invalid JSON, unknown filter or invalid filter value"
+//	@Router			/views/{id} [put]
+func (s Service) UpdateView(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	viewID := chi.URLParam(r, "id")
+	log.Info().Str("id", viewID).Msg("view id received")
+	viewRequest, ok := s.decodeView(w, r, log)
+	if !ok {
+		return
+	}
+	view, err := s.DB.UpdateView(r.Context(), viewRequest, viewID)
+	if err != nil {
+		viewErrorHandler(w, r, log.With().Str("id", viewID).Logger(), viewRequest.Name, viewID, err)
+		return
+	}
+	log.Info().Str("id", viewID).Msg("view updated successfully")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, view)
+}
+
+// DeleteView deletes a view by the specified ID.
+//
+//	@Summary		Deletes a view by ID
+//	@Description	Deletes a saved view, tasks are not affected
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"View ID"
+//	@Success		200	{object}	MsgResp	"View successfully deleted"
+//	@Failure		401	{object}	ErrResp	"Missing credentials"
+//	@Failure		403	{object}	ErrResp	"Scope tasks:delete not granted"
+//	@Failure		404	{object}	MsgResp	"View not found"
+//	@Router			/views/{id} [delete]
+func (s Service) DeleteView(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksDelete) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	viewID := chi.URLParam(r, "id")
+	log.Info().Str("id", viewID).Msg("view id received")
+	if err := s.DB.DeleteView(r.Context(), viewID); err != nil {
+		viewErrorHandler(w, r, log.With().Str("id", viewID).Logger(), "", viewID, err)
+		return
+	}
+	log.Info().Str("id", viewID).Msg("deleted successfully")
+	NewMsg("success").Send(w, r, http.StatusOK)
+}
+
+// ListViewTasks runs a view.
+//
+//	@Summary		Returns tasks of a view
+//	@Description	Lists tasks with the parameters stored in the view, the response is the same as of GET /tasks with them.
+//	@Description	Only page and cursor are taken from the request
+//	@Tags			Views
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string			true	"View ID"
+//	@Param			page	query		string			false	"Page number for pagination"
+//	@Param			cursor	query		string			false	"Cursor returned as next_cursor, can not be combined with page"
+//	@Success		200		{object}	[]tasktodo.Task	"List of tasks, or TaskPage when the view has a page size"
+//	@Failure		400		{object}	ErrResp			"Invalid page or cursor"
+//	@Failure		401		{object}	ErrResp			"Missing credentials"
+//	@Failure		403		{object}	ErrResp			"Scope tasks:read not granted"
+//	@Failure		404		{object}	MsgResp			"View or tasks not found"
+//	@Router			/views/{id}/tasks [get]
+//	@Router			/v2/views/{id}/tasks [get]
+func (s Service) ListViewTasks(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	log := *zerolog.Ctx(r.Context())
+	viewID := chi.URLParam(r, "id")
+	view, err := s.DB.GetView(r.Context(), viewID)
+	if err != nil {
+		viewErrorHandler(w, r, log.With().Str("id", viewID).Logger(), "", viewID, err)
+		return
+	}
+	query := viewQuery(view.ViewRequest)
+	for _, param := range []string{"page", "cursor"} {
+		if values, ok := r.URL.Query()[param]; ok {
+			query[param] = values
+		}
+	}
+	log.Info().Str("id", viewID).Str("query", query.Encode()).Msg("running view")
+	target := *r.URL
+	target.RawQuery = query.Encode()
+	r = r.WithContext(r.Context())
+	r.URL = &target
+	s.ListTasks(w, r)
+}
+
+// decodeView reads a view and checks it with the same rules as the query of a listing.
+func (s Service) decodeView(w http.ResponseWriter, r *http.Request, log zerolog.Logger) (tasktodo.ViewRequest, bool) {
+	viewRequest := tasktodo.ViewRequest{}
+	if err := render.DecodeJSON(r.Body, &viewRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return tasktodo.ViewRequest{}, false
+	}
+	viewRequest.Name = strings.TrimSpace(viewRequest.Name)
+	if err := validator.New().Struct(viewRequest); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return tasktodo.ViewRequest{}, false
+	}
+	if errResp, err := s.validateView(viewRequest); err != nil {
+		log.Error().Err(err).Send()
+		errResp.Send(w, r, http.StatusUnprocessableEntity)
+		return tasktodo.ViewRequest{}, false
+	}
+	return viewRequest, true
+}
+
+func (s Service) validateView(view tasktodo.ViewRequest) (ErrResp, error) {
+	for name := range view.Filters {
+		if !isViewFilter(name) {
+			return NewErr("filters", name, "unknown filter"), errors.New("unknown filter")
+		}
+	}
+	if view.PageSize > s.Pages.MaxLimit {
+		return NewErr("page_size", strconv.FormatUint(uint64(view.PageSize), 10),
+			fmt.Sprintf("page_size must not exceed %d", s.Pages.MaxLimit)), errors.New("bad page size")
+	}
+	query := viewQuery(view)
+	params, errResp, err := validateParams(query)
+	if err != nil {
+		return errResp, err
+	}
+	return parseQuery(query, &params)
+}
+
+// viewQuery turns a view into the query of a listing.
+func viewQuery(view tasktodo.ViewRequest) url.Values {
+	query := make(url.Values, len(view.Filters)+2)
+	for name, value := range view.Filters {
+		query.Set(name, value)
+	}
+	if view.Sort != "" {
+		query.Set("sort", view.Sort)
+	}
+	if view.PageSize != 0 {
+		query.Set("limit", strconv.FormatUint(uint64(view.PageSize), 10))
+	}
+	return query
+}
+
+func isViewFilter(name string) bool {
+	for _, filter := range tasktodo.ViewFilters {
+		if name == filter {
+			return true
+		}
+	}
+	return false
+}
+
+func viewErrorHandler(w http.ResponseWriter, r *http.Request, log zerolog.Logger, name, viewID string, err error) {
+	switch err.Error() {
+	case pgrepo.InvalidViewIdErr:
+		log.Warn().Err(err).Send()
+		NewMsg(pgrepo.InvalidViewIdErr).Send(w, r, http.StatusNotFound)
+	case pgrepo.ViewExistsErr:
+		log.Warn().Err(err).Send()
+		NewErr("name", name, pgrepo.ViewExistsErr).Send(w, r, http.StatusConflict)
+	default:
+		log.Error().Err(err).Send()
+		NewErr("id", viewID, "action fail").Send(w, r, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,203 @@
+package httpchi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/adapters/pgrepo"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestViewHandlers() {
+	type viewTestCase struct {
+		handler func(s httpchi.Service) http.HandlerFunc
+		TestCase
+	}
+	view := tasktodo.View{ID: "test", ViewRequest: tasktodo.ViewRequest{Name: "mine",
+		Filters: map[string]string{"status": "false", "tag": "backend,api", "q": "overdue:true"}, Sort: "-due_date", PageSize: 20}}
+	viewResp := `{"id":"test","name":"mine","filters":{"q":"overdue:true","status":"false","tag":"backend,api"},"sort":"-due_date","page_size":20}`
+	viewBody := `{"name":" mine ","filters":{"status":"false","tag":"backend,api","q":"overdue:true"},"sort":"-due_date","page_size":20}`
+	testCases := []viewTestCase{
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateView", mock.Anything, view.ViewRequest).Return(view, nil).Once()
+				},
+				expectedCode: http.StatusCreated,
+				expectedResp: viewResp,
+				reqBody:      viewBody,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("CreateView", mock.Anything, view.ViewRequest).Return(tasktodo.View{}, errors.New(pgrepo.ViewExistsErr)).Once()
+				},
+				expectedCode: http.StatusConflict,
+				expectedResp: `{"param":"name","value":"mine","error":"view already exists"}`,
+				reqBody:      viewBody,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"filters","value":"limit","error":"unknown filter"}`,
+				reqBody:       `{"name":"mine","filters":{"limit":"5"}}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"status","value":"maybe","error":"bad status"}`,
+				reqBody:       `{"name":"mine","filters":{"status":"maybe"}}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"q","value":"assignee","error":"unknown field at position 1"}`,
+				reqBody:       `{"name":"mine","filters":{"q":"assignee:me"}}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.CreateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"sort","value":"id","error":"bad sort"}`,
+				reqBody:       `{"name":"mine","sort":"id"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"param":"page_size","value":"101","error":"page_size must not exceed 100"}`,
+				reqBody:       `{"name":"mine","page_size":101}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateView },
+			TestCase: TestCase{
+				storageOutput: func() {},
+				expectedCode:  http.StatusUnprocessableEntity,
+				expectedResp:  `{"error":"invalid JSON"}`,
+				reqBody:       `{"name":"  "}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.UpdateView },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("UpdateView", mock.Anything, view.ViewRequest, "test").Return(view, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: viewResp,
+				reqBody:      viewBody,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.ListViews },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("ListViews", mock.Anything).Return([]tasktodo.View{view}, nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `[` + viewResp + `]`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.GetView },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("GetView", mock.Anything, "test").Return(tasktodo.View{}, errors.New(pgrepo.InvalidViewIdErr)).Once()
+				},
+				expectedCode: http.StatusNotFound,
+				expectedResp: `{"message":"invalid view id"}`,
+			},
+		},
+		{
+			handler: func(s httpchi.Service) http.HandlerFunc { return s.DeleteView },
+			TestCase: TestCase{
+				storageOutput: func() {
+					suite.storage.(*mocks.Repo).On("DeleteView", mock.Anything, "test").Return(nil).Once()
+				},
+				expectedCode: http.StatusOK,
+				expectedResp: `{"message":"success"}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest("POST", "/views", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		tc.handler(suite.service)(w, req)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
+
+func (suite *UnitTestSuite) TestListViewTasks() {
+	taskResp := `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"todo","tags":[]}`
+	repo := suite.storage.(*mocks.Repo)
+	repo.On("GetView", mock.Anything, "paged").Return(tasktodo.View{ID: "paged", ViewRequest: tasktodo.ViewRequest{Name: "paged",
+		Filters: map[string]string{"status": "false", "tag": "backend,api"}, Sort: "-due_date", PageSize: 20}}, nil).Once()
+	repo.On("ListTasks", mock.Anything, tasktodo.ListParams{Status: "false", Tags: []string{"api", "backend"}, TagMode: tasktodo.TagModeAny,
+		Page: 1, Limit: 20, Lookahead: 1, Sort: []tasktodo.SortField{{Field: "due_date", Desc: true}}}).Return([]tasktodo.Task{suite.testTask}, nil).Once()
+	repo.On("GetView", mock.Anything, "plain").Return(tasktodo.View{ID: "plain", ViewRequest: tasktodo.ViewRequest{Name: "plain",
+		Filters: map[string]string{"overdue": "true"}}}, nil).Once()
+	repo.On("ListTasks", mock.Anything, tasktodo.ListParams{Overdue: "true", TagMode: tasktodo.TagModeAny, Limit: 10}).
+		Return([]tasktodo.Task{suite.testTask}, nil).Once()
+	// a sorted view keeps its page size and is paged by number, its pages carry no cursor
+	repo.On("GetView", mock.Anything, "sorted").Return(tasktodo.View{ID: "sorted", ViewRequest: tasktodo.ViewRequest{Name: "sorted",
+		Sort: "title", PageSize: 1}}, nil).Once()
+	repo.On("ListTasks", mock.Anything, tasktodo.ListParams{TagMode: tasktodo.TagModeAny, Page: 2, Limit: 1, Lookahead: 1,
+		Sort: []tasktodo.SortField{{Field: "title"}}}).Return([]tasktodo.Task{suite.testTask, suite.testTask}, nil).Once()
+	repo.On("GetView", mock.Anything, "missing").Return(tasktodo.View{}, errors.New(pgrepo.InvalidViewIdErr)).Once()
+	suite.service = httpchi.NewService(suite.storage)
+
+	testCases := []struct {
+		viewID       string
+		target       string
+		expectedCode int
+		expectedResp string
+	}{
+		{viewID: "paged", target: "/views/paged/tasks?page=1&status=true", expectedCode: http.StatusOK, expectedResp: `{"tasks":[` + taskResp + `]}`},
+		{viewID: "sorted", target: "/views/sorted/tasks?page=2", expectedCode: http.StatusOK, expectedResp: `{"tasks":[` + taskResp + `]}`},
+		{viewID: "plain", target: "/views/plain/tasks", expectedCode: http.StatusOK, expectedResp: `[` + taskResp + `]`},
+		{viewID: "missing", target: "/views/missing/tasks", expectedCode: http.StatusNotFound, expectedResp: `{"message":"invalid view id"}`},
+	}
+	for _, tc := range testCases {
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", tc.viewID)
+		r := newRequest("GET", tc.target, nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.ListViewTasks(w, r)
+
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, strings.TrimSpace(w.Body.String()))
+	}
+}
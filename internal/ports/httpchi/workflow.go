@@ -0,0 +1,101 @@
+package httpchi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/internal/models/account"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"net/http"
+)
+
+// TransitionTask moves a task to another workflow state.
+//
+//	@Summary		Moves a task to another state
+//	@Description	Changes the workflow state of a task if the transition is allowed by the configured workflow
+//	@Tags			Workflow
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Task ID"
+//	@Param			If-Match	header		string				false	"Expected task ETag"
+//	@Param			transition	body		tasktodo.Transition	true	"Target state"
+//	@Success		200			{object}	tasktodo.Task		"Task moved to the new state"
+//	@Header			200			{string}	ETag				"New task version"
+//	@Failure		400			{object}	ErrResp				"Incorrect JSON or malformed precondition header"
+//	@Failure		401			{object}	ErrResp				"Missing credentials"
+//	@Failure		403			{object}	ErrResp				"Scope tasks:write not granted"
+//	@Failure		404			{object}	MsgResp				"Task not found"
+//	@Failure		409			{object}	ErrResp				"Transition is not allowed or task has open blockers"
+//	@Failure		412			{object}	ErrResp				"Task version does not match"
+//	@Failure		422			{object}	ErrResp				"Unknown state"
+//	@Router			/task/{id}/transition [post]
+func (s Service) TransitionTask(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksWrite) {
+		return
+	}
+	transition := tasktodo.Transition{}
+	log := *zerolog.Ctx(r.Context())
+	taskID := chi.URLParam(r, "id")
+	log.Info().Str("id", taskID).Msg("task id received")
+	if err := render.DecodeJSON(r.Body, &transition); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "bad JSON").Send(w, r, http.StatusBadRequest)
+		return
+	}
+	if err := validator.New().Struct(transition); err != nil {
+		log.Error().Err(err).Send()
+		NewErr("", "", "invalid JSON").Send(w, r, http.StatusUnprocessableEntity)
+		return
+	}
+	logID := log.With().Str("id", taskID).Logger()
+	current, err := s.DB.GetTask(r.Context(), taskID)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	version, err := preconditions(r, current)
+	if err != nil {
+		errorHandler(w, r, logID, "", taskID, err)
+		return
+	}
+	if err = s.Workflow.Check(current.State, transition.State); err != nil {
+		stateErrorHandler(w, r, logID, transition.State, err)
+		return
+	}
+	task := current
+	if current.State != transition.State {
+		done := transition.State == tasktodo.StateDone
+		task, err = s.DB.PatchTask(r.Context(), tasktodo.Patch{Status: &done, State: &transition.State}, taskID, version)
+		if err != nil {
+			errorHandler(w, r, logID, "", taskID, err)
+			return
+		}
+	}
+	logID.Info().Str("from", string(current.State)).Str("to", string(task.State)).Msg("task transitioned")
+	w.Header().Set("ETag", etag(task.Version))
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, task)
+}
+
+// GetWorkflow returns the configured workflow.
+//
+//	@Summary		Returns the task workflow
+//	@Description	Lists enabled states and allowed transitions between them
+//	@Tags			Workflow
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Success		200	{object}	tasktodo.WorkflowDefinition	"Workflow definition"
+//	@Failure		401	{object}	ErrResp						"Missing credentials"
+//	@Failure		403	{object}	ErrResp						"Scope tasks:read not granted"
+//	@Router			/workflow [get]
+func (s Service) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, account.ScopeTasksRead) {
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, s.Workflow.Definition())
+}
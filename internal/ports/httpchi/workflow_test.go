@@ -0,0 +1,94 @@
+package httpchi_test
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/vlasashk/task-manager/internal/models/mocks"
+	"github.com/vlasashk/task-manager/internal/models/tasktodo"
+	"github.com/vlasashk/task-manager/internal/ports/httpchi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (suite *UnitTestSuite) TestTransitionTask() {
+	inProgress := tasktodo.StateInProgress
+	open := false
+	moved := suite.testTask
+	moved.SetState(tasktodo.StateInProgress)
+	moved.Version = 2
+	done := suite.testTask
+	done.SetState(tasktodo.StateDone)
+	testCases := []TestCase{
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+				suite.storage.(*mocks.Repo).On("PatchTask", mock.Anything, tasktodo.Patch{Status: &open, State: &inProgress}, "test", int64(0)).Return(moved, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedResp: `{"id":"test","title":"test","description":"test","due_date":"2024-10-26","status":false,"state":"in_progress","tags":[],"version":2}`,
+			reqBody:      `{"state":"in_progress","tags":[]}`,
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(done, nil).Once()
+			},
+			expectedCode: http.StatusConflict,
+			expectedResp: `{"param":"state","value":"blocked","error":"transition not allowed"}`,
+			reqBody:      `{"state":"blocked","tags":[]}`,
+		},
+		{
+			storageOutput: func() {
+				suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(suite.testTask, nil).Once()
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedResp: `{"param":"state","value":"archived","error":"unknown state"}`,
+			reqBody:      `{"state":"archived","tags":[]}`,
+		},
+		{
+			storageOutput: func() {},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedResp:  `{"error":"invalid JSON"}`,
+			reqBody:       `{}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc.storageOutput()
+		suite.service = httpchi.NewService(suite.storage)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", "test")
+		req := newRequest("POST", "/task", strings.NewReader(tc.reqBody))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+		w := httptest.NewRecorder()
+
+		suite.service.TransitionTask(w, req)
+
+		body, err := io.ReadAll(w.Body)
+		bodyStr := strings.TrimSpace(string(body))
+		suite.NoError(err)
+		suite.Equal(tc.expectedCode, w.Code)
+		suite.Equal(tc.expectedResp, bodyStr)
+	}
+}
+
+func (suite *UnitTestSuite) TestUpdateTaskLegacyStatus() {
+	current := suite.testTask
+	current.SetState(tasktodo.StateInProgress)
+	current.Version = 5
+	expected := suite.taskReq
+	expected.SetState(tasktodo.StateInProgress)
+	suite.storage.(*mocks.Repo).On("GetTask", mock.Anything, "test").Return(current, nil).Once()
+	suite.storage.(*mocks.Repo).On("UpdateTask", mock.Anything, expected, "test", int64(5)).Return(current, nil).Once()
+	suite.service = httpchi.NewService(suite.storage)
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("id", "test")
+	req := newRequest("PUT", "/task", strings.NewReader(`{"title":"test","description":"test","due_date":"2024-10-26","status":false}`))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+	w := httptest.NewRecorder()
+
+	suite.service.UpdateTask(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+}
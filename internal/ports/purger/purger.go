@@ -0,0 +1,64 @@
+// Package purger removes tasks which have been in the trash longer than the retention period
+// and contents of attachments nothing refers to anymore.
+package purger
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/vlasashk/task-manager/config"
+	"time"
+)
+
+// Repo deletes for good the tasks deleted longer than the retention period ago.
+// PurgeBlobs removes the blobs no attachment refers to, remove deletes the content of each of them from the store.
+type Repo interface {
+	PurgeTasks(ctx context.Context, retention time.Duration) (int64, error)
+	PurgeBlobs(ctx context.Context, remove func(ctx context.Context, key string) error) (int64, error)
+}
+
+// Blobs deletes contents of attachments.
+type Blobs interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// Run purges the trash right away and then every purge interval until the context is done.
+// Blobs of attachments of the purged tasks are removed right after them.
+// A failed purge is logged and retried on the next tick.
+func Run(ctx context.Context, repo Repo, blobs Blobs, cfg config.TrashCfg, log zerolog.Logger) {
+	log = log.With().Str("worker", "purger").Logger()
+	log.Info().Dur("retention", cfg.Retention).Dur("interval", cfg.PurgeInterval).Msg("purger started")
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		purge(ctx, repo, cfg.Retention, log)
+		purgeBlobs(ctx, repo, blobs, log)
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purge(ctx context.Context, repo Repo, retention time.Duration, log zerolog.Logger) {
+	purged, err := repo.PurgeTasks(ctx, retention)
+	if err != nil {
+		log.Error().Err(err).Int64("purged", purged).Msg("purging trash fail")
+		return
+	}
+	if purged != 0 {
+		log.Info().Int64("purged", purged).Msg("trash purged")
+	}
+}
+
+func purgeBlobs(ctx context.Context, repo Repo, blobs Blobs, log zerolog.Logger) {
+	removed, err := repo.PurgeBlobs(ctx, blobs.Delete)
+	if err != nil {
+		log.Error().Err(err).Int64("removed", removed).Msg("purging blobs fail")
+		return
+	}
+	if removed != 0 {
+		log.Info().Int64("removed", removed).Msg("blobs purged")
+	}
+}
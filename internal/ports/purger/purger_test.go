@@ -0,0 +1,69 @@
+package purger_test
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlasashk/task-manager/config"
+	"github.com/vlasashk/task-manager/internal/ports/purger"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	calls      atomic.Int32
+	retention  atomic.Int64
+	blobCalls  atomic.Int32
+	removedKey atomic.Value
+}
+
+func (f *fakeRepo) PurgeTasks(_ context.Context, retention time.Duration) (int64, error) {
+	f.retention.Store(int64(retention))
+	if f.calls.Add(1) == 1 {
+		return 0, errors.New("any error")
+	}
+	return 3, nil
+}
+
+func (f *fakeRepo) PurgeBlobs(ctx context.Context, remove func(ctx context.Context, key string) error) (int64, error) {
+	f.blobCalls.Add(1)
+	if err := remove(ctx, "blob"); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+type fakeBlobs struct {
+	repo *fakeRepo
+}
+
+func (f fakeBlobs) Delete(_ context.Context, key string) error {
+	f.repo.removedKey.Store(key)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	repo := &fakeRepo{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		purger.Run(ctx, repo, fakeBlobs{repo: repo}, config.TrashCfg{Retention: time.Hour, PurgeInterval: time.Millisecond}, zerolog.Nop())
+		close(done)
+	}()
+
+	// a failed purge does not stop the worker
+	assert.Eventually(t, func() bool { return repo.calls.Load() >= 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(time.Hour), repo.retention.Load())
+	// blobs are purged even when purging the trash fails
+	assert.Eventually(t, func() bool { return repo.blobCalls.Load() >= 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, "blob", repo.removedKey.Load())
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop")
+	}
+}
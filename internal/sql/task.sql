@@ -8,3 +8,290 @@ CREATE TABLE IF NOT EXISTS tasks (
 );
 
 CREATE INDEX IF NOT EXISTS idx_tasks_not_deleted ON tasks (id) WHERE deleted_at IS NULL;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+DO $$
+BEGIN
+    CREATE TYPE task_state AS ENUM ('todo', 'in_progress', 'blocked', 'in_review', 'done', 'cancelled');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'tasks' AND column_name = 'state') THEN
+        ALTER TABLE tasks ADD COLUMN state task_state NOT NULL DEFAULT 'todo';
+        -- completed tasks are likely overdue, so the due date check is re-added without validation
+        ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_due_date_check;
+        UPDATE tasks SET state = 'done' WHERE status;
+        ALTER TABLE tasks ADD CONSTRAINT tasks_due_date_check CHECK (due_date >= CURRENT_DATE) NOT VALID;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS tags (
+     id VARCHAR(255) PRIMARY KEY,
+     name VARCHAR(64) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+     task_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     tag_id VARCHAR(255) NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+     PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags (tag_id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id VARCHAR(255) REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks (parent_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+     task_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     blocker_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     PRIMARY KEY (task_id, blocker_id),
+     CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker ON task_dependencies (blocker_id);
+
+CREATE TABLE IF NOT EXISTS task_series (
+     id VARCHAR(255) PRIMARY KEY,
+     title VARCHAR(255) NOT NULL,
+     description TEXT NOT NULL,
+     recurrence TEXT NOT NULL
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id VARCHAR(255) REFERENCES task_series (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_series ON tasks (series_id);
+
+CREATE TABLE IF NOT EXISTS users (
+     id VARCHAR(255) PRIMARY KEY,
+     email VARCHAR(255) NOT NULL UNIQUE,
+     password_hash TEXT NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- rows created before accounts existed have no owner and are not visible to anyone
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE task_series ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner ON tasks (owner_id, due_date) WHERE deleted_at IS NULL;
+
+-- tag names are unique per user instead of globally
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_owner_name ON tags (owner_id, name);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+     id VARCHAR(255) PRIMARY KEY,
+     owner_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+     name VARCHAR(64) NOT NULL,
+     prefix VARCHAR(16) NOT NULL,
+     key_hash CHAR(64) NOT NULL UNIQUE,
+     scopes TEXT[] NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+     last_used_at TIMESTAMP,
+     revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner ON api_keys (owner_id);
+
+CREATE TABLE IF NOT EXISTS projects (
+     id VARCHAR(255) PRIMARY KEY,
+     owner_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+     name VARCHAR(255) NOT NULL,
+     description TEXT NOT NULL DEFAULT '',
+     archived_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_owner ON projects (owner_id, name);
+
+-- deleting a project keeps its tasks, they are just left without a project
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id VARCHAR(255) REFERENCES projects (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks (project_id) WHERE deleted_at IS NULL;
+
+-- search is generated with the text search configuration passed by the application in
+-- task_manager.search_language, the column is rebuilt when the configuration changes
+DO $$
+DECLARE
+    lang regconfig := COALESCE(NULLIF(current_setting('task_manager.search_language', true), ''), 'english')::regconfig;
+BEGIN
+    IF col_description('tasks'::regclass, (SELECT attnum FROM pg_attribute
+            WHERE attrelid = 'tasks'::regclass AND attname = 'search' AND NOT attisdropped)) IS DISTINCT FROM lang::text THEN
+        ALTER TABLE tasks DROP COLUMN IF EXISTS search;
+        EXECUTE format('ALTER TABLE tasks ADD COLUMN search tsvector GENERATED ALWAYS AS
+            (setweight(to_tsvector(%1$L, title), ''A'') || setweight(to_tsvector(%1$L, description), ''B'')) STORED', lang);
+        EXECUTE format('COMMENT ON COLUMN tasks.search IS %L', lang);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search ON tasks USING GIN (search);
+
+-- tasks created before the column existed get the time of the migration
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_created ON tasks (owner_id, created_at) WHERE deleted_at IS NULL;
+
+-- text_pattern_ops lets the lower(title) LIKE 'prefix%' filter use the index regardless of the collation
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_title ON tasks (owner_id, lower(title) text_pattern_ops) WHERE deleted_at IS NULL;
+
+-- overdue tasks are looked up among the open ones only
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_open ON tasks (owner_id, due_date)
+    WHERE deleted_at IS NULL AND state NOT IN ('done', 'cancelled');
+
+CREATE TABLE IF NOT EXISTS saved_views (
+     id VARCHAR(255) PRIMARY KEY,
+     owner_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+     name VARCHAR(255) NOT NULL,
+     filters JSONB NOT NULL DEFAULT '{}',
+     sort VARCHAR(255) NOT NULL DEFAULT '',
+     page_size INTEGER NOT NULL DEFAULT 0,
+     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+     UNIQUE (owner_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_trash ON tasks (owner_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_purge ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- task_events is the history of task changes, it has no foreign keys as it outlives purged tasks
+CREATE TABLE IF NOT EXISTS task_events (
+     id BIGSERIAL PRIMARY KEY,
+     task_id VARCHAR(255) NOT NULL,
+     owner_id VARCHAR(255) NOT NULL,
+     action VARCHAR(16) NOT NULL,
+     version BIGINT NOT NULL,
+     actor VARCHAR(255) NOT NULL,
+     api_key_id VARCHAR(255),
+     request_id VARCHAR(255) NOT NULL DEFAULT '',
+     before JSONB,
+     after JSONB,
+     snapshot JSONB NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task ON task_events (owner_id, task_id, id);
+
+-- the history is append-only
+CREATE OR REPLACE FUNCTION task_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'task_events is append-only';
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS task_events_append_only ON task_events;
+CREATE TRIGGER task_events_append_only BEFORE UPDATE OR DELETE ON task_events
+    FOR EACH STATEMENT EXECUTE FUNCTION task_events_append_only();
+
+-- comments are soft deleted like tasks, they go away for good together with their purged task
+CREATE TABLE IF NOT EXISTS comments (
+     id VARCHAR(255) PRIMARY KEY,
+     task_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     author_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+     body TEXT NOT NULL,
+     html TEXT NOT NULL,
+     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+     edited_at TIMESTAMPTZ,
+     deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_task ON comments (task_id, created_at, id) WHERE deleted_at IS NULL;
+
+-- blobs are contents of attachments kept once per SHA-256, the ones no attachment refers to are removed by the purger
+CREATE TABLE IF NOT EXISTS blobs (
+     sha256 CHAR(64) PRIMARY KEY,
+     used_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS attachments (
+     id VARCHAR(255) PRIMARY KEY,
+     task_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     name VARCHAR(255) NOT NULL,
+     sha256 CHAR(64) NOT NULL REFERENCES blobs (sha256),
+     content_type VARCHAR(255) NOT NULL,
+     size BIGINT NOT NULL,
+     uploader_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task ON attachments (task_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_sha256 ON attachments (sha256);
+
+-- checklist items are listed by position, positions of removed items are left unused
+CREATE TABLE IF NOT EXISTS checklist_items (
+     id VARCHAR(255) PRIMARY KEY,
+     task_id VARCHAR(255) NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+     text VARCHAR(500) NOT NULL,
+     done BOOLEAN NOT NULL DEFAULT FALSE,
+     position INT NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task ON checklist_items (task_id, position);
+
+DO $$
+BEGIN
+    CREATE TYPE task_priority AS ENUM ('P0', 'P1', 'P2', 'P3', 'P4');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority task_priority NOT NULL DEFAULT 'P2';
+
+-- the default order of listings and its cursors, keyset pagination walks the live tasks of a user in this order
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_priority ON tasks (owner_id, priority, due_date, id) WHERE deleted_at IS NULL;
+
+-- the (due_date, id) order it replaced is not used anymore
+DROP INDEX IF EXISTS idx_tasks_owner_keyset;
+
+-- tasks changed or done before the columns existed get the time of the migration and no completion time
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+-- these columns were created without a time zone at first, their values are read in the time zone of the server
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN SELECT table_name, column_name FROM information_schema.columns
+        WHERE table_schema = current_schema() AND data_type = 'timestamp without time zone'
+            AND (table_name::text, column_name::text) IN (('tasks', 'created_at'), ('tasks', 'updated_at'),
+                ('tasks', 'completed_at'), ('comments', 'created_at'), ('comments', 'edited_at'),
+                ('comments', 'deleted_at'), ('attachments', 'created_at'))
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE TIMESTAMPTZ', col.table_name, col.column_name);
+    END LOOP;
+END $$;
+
+-- the due time is the wall clock time on due_date in time_zone, UTC if there is none
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_time TIME;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64);
+
+-- every write path bumps the version, so the timestamps follow the version and the status here
+CREATE OR REPLACE FUNCTION tasks_timestamps() RETURNS trigger AS $$
+BEGIN
+    IF NOT COALESCE(NEW.status, FALSE) THEN
+        NEW.completed_at := NULL;
+    ELSIF TG_OP = 'INSERT' THEN
+        NEW.completed_at := NOW();
+    ELSIF NOT COALESCE(OLD.status, FALSE) THEN
+        NEW.completed_at := NOW();
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.version <> OLD.version THEN
+            NEW.updated_at := NOW();
+        END IF;
+    END IF;
+    RETURN NEW;
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tasks_timestamps ON tasks;
+CREATE TRIGGER tasks_timestamps BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION tasks_timestamps();
+
+-- due dates in the past are limited by the service only for new and changed due dates, the check kept
+-- overdue tasks from being updated at all
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_due_date_check;